@@ -0,0 +1,439 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/go-shiori/dom"
+	"github.com/markusmobius/go-trafilatura/internal/etree"
+	"github.com/markusmobius/go-trafilatura/internal/selector"
+	"golang.org/x/net/html"
+)
+
+// Article container must have at least this much paragraph text and this many
+// paragraphs, so teasers in home page are not regarded as complete articles.
+const (
+	minSplitArticleLength     = 250
+	minSplitArticleParagraphs = 2
+)
+
+// articleBylineSelector matches the byline or publish date inside article container.
+const articleBylineSelector = `time, [rel="author"], [itemprop="author"], [itemprop="datePublished"], ` +
+	`[class*="byline"], [class*="author"], [class*="date"]`
+
+// _SplitArticle is an independent article that found in the page, along with its
+// JSON+LD schema if any.
+type _SplitArticle struct {
+	container *html.Node
+	headline  string
+	url       string
+	schema    map[string]any
+}
+
+// ExtractArticles parses a reader that might contain several independent articles,
+// e.g. home page, live page or "infinite scroll" article page, then extracts each
+// article separately with its own title, author, date and content. If the page only
+// contains a single article, the result is the same as `Extract`.
+func ExtractArticles(r io.Reader, opts Options) ([]*ExtractResult, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, encoding, err := parseHTML(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := ExtractDocumentArticles(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		result.Encoding = encoding
+	}
+	return results, nil
+}
+
+// ExtractDocumentArticles is like `ExtractArticles`, but the page is already parsed
+// as HTML document.
+func ExtractDocumentArticles(doc *html.Node, opts Options) ([]*ExtractResult, error) {
+	// Find the articles before extraction, since the document might be modified
+	articles := findSplitArticles(doc, opts)
+	if len(articles) < 2 {
+		result, err := ExtractDocument(doc, opts)
+		if err != nil {
+			return nil, err
+		}
+		return []*ExtractResult{result}, nil
+	}
+
+	var articleDocs []*html.Node
+	for _, article := range articles {
+		articleDocs = append(articleDocs, createArticleDocument(doc, article))
+	}
+
+	var lastErr error
+	var results []*ExtractResult
+	for i, articleDoc := range articleDocs {
+		result, err := ExtractDocument(articleDoc, opts)
+		if err != nil {
+			logWarn(opts, "failed to extract article %d: %v", i+1, err)
+			lastErr = err
+			continue
+		}
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no article can be extracted: %w", lastErr)
+	}
+	return results, nil
+}
+
+// findSplitArticles returns the independent articles in the page. The containers
+// are looked in explicit article elements first, then in repeated structures that
+// contain headline and body, and lastly in JSON+LD.
+func findSplitArticles(doc *html.Node, opts Options) []_SplitArticle {
+	// Collect the articles from JSON+LD
+	var schemas []map[string]any
+	seenHeadlines := make(map[string]struct{})
+	jsonLdArticles := jsonLdSchemas(doc, opts).articles
+	for _, article := range jsonLdArticles {
+		headline := normalizeHeadline(strOr(
+			getSingleStringValue(article.Data, "headline"),
+			getSingleStringValue(article.Data, "name")))
+		if article.Importance < 3 || headline == "" || isLiveBlogUpdateSchema(article) {
+			continue
+		}
+
+		if _, seen := seenHeadlines[headline]; !seen {
+			seenHeadlines[headline] = struct{}{}
+			schemas = append(schemas, article.Data)
+		}
+	}
+
+	// Updates of live blog are not independent articles, so live blog is never split
+	if isLiveBlog(doc, opts) {
+		return nil
+	}
+
+	// Find the containers in DOM
+	containers := findArticleContainers(doc)
+	if len(containers) < 2 {
+		return splitArticlesFromJsonLd(schemas)
+	}
+
+	var articles []_SplitArticle
+	usedSchemas := make(map[int]struct{})
+	for _, container := range containers {
+		article := _SplitArticle{container: container}
+		if heading := dom.QuerySelector(container, "h1, h2, h3, [itemprop=headline]"); heading != nil {
+			article.headline = trim(dom.TextContent(heading))
+			if link := dom.QuerySelector(heading, "a[href]"); link != nil {
+				href := createAbsoluteURL(dom.GetAttribute(link, "href"), opts.OriginalURL)
+				if isAbs, _ := isAbsoluteURL(href); isAbs {
+					article.url = href
+				}
+			}
+		}
+
+		// Use the JSON+LD whose headline matches with the container
+		headline := normalizeHeadline(article.headline)
+		for i, schema := range schemas {
+			if _, used := usedSchemas[i]; used {
+				continue
+			}
+
+			schemaHeadline := normalizeHeadline(strOr(
+				getSingleStringValue(schema, "headline"),
+				getSingleStringValue(schema, "name")))
+			if headline != "" && (schemaHeadline == headline || strings.Contains(headline, schemaHeadline)) {
+				article.schema = schema
+				usedSchemas[i] = struct{}{}
+				break
+			}
+		}
+
+		articles = append(articles, article)
+	}
+
+	return articles
+}
+
+// Selectors that used while looking for article containers, compiled once.
+var (
+	articleElementSelector = cascadia.MustCompile(`article, [itemtype*="Article"], [itemtype*="BlogPosting"]`)
+	articleHeadingSelector = cascadia.MustCompile(`h1, h2, h3, [itemprop=headline]`)
+	articleBylineMatcher   = cascadia.MustCompile(articleBylineSelector)
+	commentRegionSelector  = cascadia.MustCompile(strings.Join(commentSystemRegions, ", "))
+)
+
+// _ArticleNodeStats is the content summary of an element and its descendants, used
+// to decide whether it contains a complete article.
+type _ArticleNodeStats struct {
+	hasHeadline     bool
+	hasByline       bool
+	nParagraphs     int
+	paragraphLength int
+	textLength      int
+	linkLength      int
+}
+
+// findArticleContainers returns the elements that each contains a complete article.
+// The candidates are the explicit article elements and the repeated siblings, which
+// are all summarized in a single pass. Comment sections are skipped, so comments are
+// never split as articles.
+func findArticleContainers(doc *html.Node) []*html.Node {
+	var explicitCandidates []*html.Node
+	var bestGroup []*html.Node
+	var bestLength int
+
+	var summarize func(*html.Node) _ArticleNodeStats
+	summarize = func(node *html.Node) _ArticleNodeStats {
+		var stats _ArticleNodeStats
+		var repeated []*html.Node
+		var repeatedLengths []int
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			switch child.Type {
+			case html.TextNode:
+				stats.textLength += strLength(strings.TrimSpace(child.Data))
+				continue
+			case html.ElementNode:
+			default:
+				continue
+			}
+
+			if isCommentContainer(child) {
+				continue
+			}
+
+			cs := summarize(child)
+
+			stats.hasHeadline = stats.hasHeadline || cs.hasHeadline || articleHeadingSelector.Match(child)
+			stats.hasByline = stats.hasByline || cs.hasByline || articleBylineMatcher.Match(child)
+			stats.nParagraphs += cs.nParagraphs
+			stats.paragraphLength += cs.paragraphLength
+			stats.textLength += cs.textLength
+
+			switch dom.TagName(child) {
+			case "p":
+				if cs.textLength > 0 {
+					stats.nParagraphs++
+					stats.paragraphLength += cs.textLength
+				}
+				stats.linkLength += cs.linkLength
+			case "a":
+				stats.linkLength += cs.textLength
+			default:
+				stats.linkLength += cs.linkLength
+			}
+
+			if cs.isArticleContainer() {
+				if articleElementSelector.Match(child) {
+					explicitCandidates = append(explicitCandidates, child)
+				}
+
+				if cs.hasByline {
+					repeated = append(repeated, child)
+					repeatedLengths = append(repeatedLengths, cs.textLength)
+				}
+			}
+		}
+
+		// Repeated siblings with the same tag and class, each with its own headline,
+		// byline and body. The byline is required, so sections of a single article are
+		// not split. If there are several groups, the one with the longest text is used.
+		if len(repeated) < 2 {
+			return stats
+		}
+
+		groups := make(map[string][]*html.Node)
+		groupLengths := make(map[string]int)
+		for i, child := range repeated {
+			signature := dom.TagName(child) + "." + dom.ClassName(child)
+			groups[signature] = append(groups[signature], child)
+			groupLengths[signature] += repeatedLengths[i]
+		}
+
+		for signature, articles := range groups {
+			if length := groupLengths[signature]; len(articles) >= 2 && length > bestLength {
+				bestGroup, bestLength = articles, length
+			}
+		}
+
+		return stats
+	}
+	summarize(doc)
+
+	// If article is nested, only the innermost is used since the outer one is usually
+	// the wrapper of whole page.
+	outerCandidates := make(map[*html.Node]struct{})
+	for _, candidate := range explicitCandidates {
+		for parent := candidate.Parent; parent != nil; parent = parent.Parent {
+			outerCandidates[parent] = struct{}{}
+		}
+	}
+
+	var containers []*html.Node
+	for _, candidate := range explicitCandidates {
+		if _, isOuter := outerCandidates[candidate]; !isOuter {
+			containers = append(containers, candidate)
+		}
+	}
+
+	if len(containers) >= 2 {
+		return containers
+	}
+	return bestGroup
+}
+
+// isArticleContainer checks whether the element contains a headline and enough
+// paragraphs to be a complete article.
+func (stats _ArticleNodeStats) isArticleContainer() bool {
+	if !stats.hasHeadline {
+		return false
+	}
+
+	var density float64
+	if stats.textLength > 0 {
+		density = float64(stats.linkLength) / float64(stats.textLength)
+	}
+
+	return stats.nParagraphs >= minSplitArticleParagraphs &&
+		stats.paragraphLength >= minSplitArticleLength &&
+		density < minBoilerplateLinkDensity
+}
+
+// isCommentContainer checks whether the element is a comment section or a single
+// comment, using the same selectors as the comments extraction.
+func isCommentContainer(node *html.Node) bool {
+	// All comment selectors look at these attributes, so skip the element without them
+	if !dom.HasAttribute(node, "id") && !dom.HasAttribute(node, "class") && !dom.HasAttribute(node, "itemtype") {
+		return false
+	}
+
+	for _, rules := range [][]selector.Rule{selector.Comments, selector.RemovedComments} {
+		for _, rule := range rules {
+			if rule(node) {
+				return true
+			}
+		}
+	}
+
+	if commentRegionSelector.Match(node) || strings.Contains(dom.GetAttribute(node, "itemtype"), "Comment") {
+		return true
+	}
+
+	// The rules above only cover the block containers, so check the comment items too
+	if tagName := dom.TagName(node); tagName == "li" || tagName == "article" {
+		for _, class := range strings.Fields(strings.ToLower(dom.ClassName(node))) {
+			if strings.HasPrefix(class, "comment") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// splitArticlesFromJsonLd creates the articles from JSON+LD that contains the article
+// body. Used when the articles are not found in DOM, e.g. because it's rendered by JS.
+func splitArticlesFromJsonLd(schemas []map[string]any) []_SplitArticle {
+	var articles []_SplitArticle
+	for _, schema := range schemas {
+		body := trim(getSingleStringValue(schema, "articleBody"))
+		if strLength(body) < minSplitArticleLength {
+			continue
+		}
+
+		headline := trim(strOr(getSingleStringValue(schema, "headline"), getSingleStringValue(schema, "name")))
+		container := etree.Element("article")
+		etree.SetText(etree.SubElement(container, "h1"), headline)
+		for _, line := range strings.Split(getSingleStringValue(schema, "articleBody"), "\n") {
+			if line = trim(line); line != "" {
+				etree.SetText(etree.SubElement(container, "p"), line)
+			}
+		}
+
+		articles = append(articles, _SplitArticle{
+			container: container,
+			headline:  headline,
+			schema:    schema,
+		})
+	}
+
+	if len(articles) < 2 {
+		return nil
+	}
+	return articles
+}
+
+// createArticleDocument creates a standalone document for the article. The page-wide
+// metadata (e.g. title and description) is not copied, so the metadata is extracted
+// from the article itself and its JSON+LD.
+func createArticleDocument(doc *html.Node, article _SplitArticle) *html.Node {
+	// Document node is regarded as void by dom package, so append the root manually
+	root := &html.Node{Type: html.DocumentNode}
+	htmlNode := etree.Element("html")
+	root.AppendChild(htmlNode)
+	if lang := dom.QuerySelector(doc, "html[lang]"); lang != nil {
+		dom.SetAttribute(htmlNode, "lang", dom.GetAttribute(lang, "lang"))
+	}
+
+	head := etree.SubElement(htmlNode, "head")
+	if article.headline != "" {
+		etree.SetText(etree.SubElement(head, "title"), article.headline)
+	}
+
+	if article.url != "" {
+		link := etree.SubElement(head, "link")
+		dom.SetAttribute(link, "rel", "canonical")
+		dom.SetAttribute(link, "href", article.url)
+	}
+
+	// Site-wide metadata is still valid for each article
+	siteMetaSelector := `meta[property="og:site_name"], meta[property="og:locale"], meta[http-equiv="content-language" i]`
+	for _, meta := range dom.QuerySelectorAll(doc, siteMetaSelector) {
+		dom.AppendChild(head, dom.Clone(meta, false))
+	}
+
+	if article.schema != nil {
+		if jsonLd, err := json.Marshal(article.schema); err == nil {
+			script := etree.SubElement(head, "script")
+			dom.SetAttribute(script, "type", "application/ld+json")
+			dom.AppendChild(script, dom.CreateTextNode(string(jsonLd)))
+		}
+	}
+
+	body := etree.SubElement(htmlNode, "body")
+	dom.AppendChild(body, dom.Clone(article.container, true))
+	return root
+}
+
+func normalizeHeadline(headline string) string {
+	return strings.ToLower(trim(headline))
+}
@@ -0,0 +1,129 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func articleParagraphs(subject string) string {
+	return strings.Repeat("<p>"+strings.Repeat("This paragraph is about "+subject+" and nothing else. ", 3)+"</p>", 3)
+}
+
+func Test_ExtractArticles(t *testing.T) {
+	opts := zeroOpts
+	opts.EnableFallback = false
+
+	// Repeated article elements, with metadata from JSON+LD
+	rawHTML := `<html lang="en"><head><title>Daily News</title>
+		<meta property="og:site_name" content="Daily News"/>
+		<script type="application/ld+json">[
+			{"@context":"https://schema.org","@type":"NewsArticle","headline":"Bridge reopens",
+			 "author":{"@type":"Person","name":"Jane Doe"},"datePublished":"2024-03-01"},
+			{"@context":"https://schema.org","@type":"NewsArticle","headline":"Library expands",
+			 "author":{"@type":"Person","name":"John Roe"},"datePublished":"2024-03-02"}
+		]</script></head><body>
+		<nav><a href="/">Home</a></nav>
+		<article><h2><a href="/bridge">Bridge reopens</a></h2>` + articleParagraphs("the bridge") + `</article>
+		<article><h2>Library expands</h2>` + articleParagraphs("the library") + `</article>
+		<article><h2>Short teaser</h2><p>Only a teaser.</p></article>
+	</body></html>`
+
+	results, err := ExtractArticles(strings.NewReader(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	assert.Equal(t, "Bridge reopens", results[0].Metadata.Title)
+	assert.Equal(t, "Jane Doe", results[0].Metadata.Author)
+	assert.Equal(t, "2024-03-01", results[0].Metadata.Date.Format("2006-01-02"))
+	assert.Equal(t, "https://example.org/bridge", results[0].Metadata.URL)
+	assert.Equal(t, "Daily News", results[0].Metadata.Sitename)
+	assert.Contains(t, results[0].ContentText, "the bridge")
+	assert.NotContains(t, results[0].ContentText, "the library")
+
+	assert.Equal(t, "Library expands", results[1].Metadata.Title)
+	assert.Equal(t, "John Roe", results[1].Metadata.Author)
+	assert.Contains(t, results[1].ContentText, "the library")
+
+	// Repeated structures with headline and byline
+	var sb strings.Builder
+	for i, subject := range []string{"the harbour", "the airport", "the stadium"} {
+		sb.WriteString(fmt.Sprintf(`<div class="post"><h2>Story %d</h2><span class="byline">By Reporter %d</span>%s</div>`,
+			i+1, i+1, articleParagraphs(subject)))
+	}
+
+	results, err = ExtractArticles(strings.NewReader(`<html><body><main>`+sb.String()+`</main></body></html>`), opts)
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, "Story 3", results[2].Metadata.Title)
+	assert.Contains(t, results[2].ContentText, "the stadium")
+
+	// Comments of single article are not split, even when each of them looks like an
+	// article with headline, byline and paragraphs
+	sb.Reset()
+	for i := range 3 {
+		sb.WriteString(fmt.Sprintf(`<li class="comment depth-1"><article class="comment-body"><h3>Reader %d</h3>`+
+			`<time datetime="2024-03-0%d">March %d</time>%s</article></li>`, i+1, i+1, i+1, articleParagraphs("my opinion")))
+	}
+
+	rawHTML = `<html><body><article><h1>Bridge reopens</h1><span class="byline">By Jane Doe</span>` +
+		articleParagraphs("the bridge") + `</article><section id="comments"><h2>Comments</h2>` +
+		`<ol class="comment-list">` + sb.String() + `</ol></section></body></html>`
+	results, err = ExtractArticles(strings.NewReader(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Contains(t, results[0].ContentText, "the bridge")
+
+	// The same goes for comment items without comment section
+	rawHTML = `<html><body><article><h1>Bridge reopens</h1>` + articleParagraphs("the bridge") +
+		`</article><ul>` + sb.String() + `</ul></body></html>`
+	results, err = ExtractArticles(strings.NewReader(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	// Sections of single article are not split
+	rawHTML = `<html><body><article><h1>Guide</h1>
+		<section><h2>Part one</h2>` + articleParagraphs("part one") + `</section>
+		<section><h2>Part two</h2>` + articleParagraphs("part two") + `</section>
+	</article></body></html>`
+	results, err = ExtractArticles(strings.NewReader(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Contains(t, results[0].ContentText, "part one")
+	assert.Contains(t, results[0].ContentText, "part two")
+
+	// Articles only available in JSON+LD
+	body := func(subject string) string {
+		return strings.Repeat("This paragraph is about "+subject+" and nothing else. ", 6)
+	}
+	rawHTML = `<html><head><script type="application/ld+json">{"@graph":[
+		{"@type":"BlogPosting","headline":"First post","articleBody":"` + body("the garden") + `\n` + body("the flowers") + `"},
+		{"@type":"BlogPosting","headline":"Second post","articleBody":"` + body("the kitchen") + `"}
+	]}</script></head><body><div id="app"></div></body></html>`
+	results, err = ExtractArticles(strings.NewReader(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "First post", results[0].Metadata.Title)
+	assert.Contains(t, results[0].ContentText, "the flowers")
+	assert.Contains(t, results[1].ContentText, "the kitchen")
+}
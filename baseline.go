@@ -3,7 +3,6 @@ package trafilatura
 import (
 	"encoding/json"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/go-shiori/dom"
 	"github.com/markusmobius/go-trafilatura/internal/etree"
@@ -94,7 +93,7 @@ func baseline(doc *html.Node) (*html.Node, string) {
 	}
 
 	tmpText = trim(tmpText)
-	if utf8.RuneCountInString(tmpText) > 100 {
+	if strLength(tmpText) > 100 {
 		return postBody, tmpText
 	}
 
@@ -105,7 +104,7 @@ func baseline(doc *html.Node) (*html.Node, string) {
 	articleElement := dom.QuerySelector(doc, "article")
 	if articleElement != nil {
 		articleText := trim(dom.TextContent(articleElement))
-		if utf8.RuneCountInString(articleText) > 100 {
+		if strLength(articleText) > 100 {
 			p := etree.SubElement(postBody, "p")
 			etree.SetText(p, articleText)
 			tmpText += " " + articleText
@@ -130,14 +129,14 @@ func baseline(doc *html.Node) (*html.Node, string) {
 	}
 
 	tmpText = trim(tmpText)
-	if utf8.RuneCountInString(tmpText) > 100 {
+	if strLength(tmpText) > 100 {
 		return postBody, tmpText
 	}
 
 	// Default strategy: clean the tree and take everything
 	if body := dom.QuerySelector(doc, "body"); body != nil {
 		text := trim(etree.IterText(body, "\n"))
-		if utf8.RuneCountInString(text) > 100 {
+		if strLength(text) > 100 {
 			elem := etree.SubElement(postBody, "p")
 			etree.SetText(elem, text)
 			return postBody, text
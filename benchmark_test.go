@@ -0,0 +1,109 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// benchmarkCorpus is all documents from test-files/comparison, parsed once and
+// shared between benchmarks.
+var benchmarkCorpus []*html.Node
+
+func loadBenchmarkCorpus(b *testing.B) []*html.Node {
+	b.Helper()
+	if benchmarkCorpus != nil {
+		return benchmarkCorpus
+	}
+
+	dir := filepath.Join("test-files", "comparison")
+	paths, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		doc, err := dom.Parse(f)
+		f.Close()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		benchmarkCorpus = append(benchmarkCorpus, doc)
+	}
+
+	return benchmarkCorpus
+}
+
+func benchmarkExtraction(b *testing.B, opts Options) {
+	docs := loadBenchmarkCorpus(b)
+	b.ReportAllocs()
+
+	start := time.Now()
+	for b.Loop() {
+		for _, doc := range docs {
+			ExtractDocument(doc, opts)
+		}
+	}
+
+	perDoc := time.Since(start) / time.Duration(b.N*len(docs))
+	b.ReportMetric(float64(perDoc.Microseconds())/1000, "ms/doc")
+}
+
+func Benchmark_Fallback(b *testing.B) {
+	b.Run("Sequential", func(b *testing.B) {
+		benchmarkExtraction(b, Options{EnableFallback: true})
+	})
+
+	b.Run("Concurrent", func(b *testing.B) {
+		benchmarkExtraction(b, Options{EnableFallback: true, FallbackConcurrency: 2})
+	})
+}
+
+func Benchmark_ExtractDocument(b *testing.B) {
+	b.Run("Balanced", func(b *testing.B) {
+		benchmarkExtraction(b, Options{})
+	})
+
+	b.Run("FavorPrecision", func(b *testing.B) {
+		benchmarkExtraction(b, Options{Focus: FavorPrecision})
+	})
+
+	b.Run("FavorRecall", func(b *testing.B) {
+		benchmarkExtraction(b, Options{Focus: FavorRecall})
+	})
+
+	b.Run("Fallback", func(b *testing.B) {
+		benchmarkExtraction(b, Options{EnableFallback: true})
+	})
+}
@@ -0,0 +1,363 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-shiori/dom"
+	"github.com/markusmobius/go-trafilatura/internal/selector"
+	"golang.org/x/net/html"
+)
+
+// SiteProfile is the boilerplate profile of a website, learned from several pages
+// of the same host. It can be used in `Options.SiteProfile` to remove the site
+// specific boilerplate before extraction. The profile can be saved and loaded as
+// JSON, so it can be reused between runs.
+type SiteProfile struct {
+	// Host is the host name of the website.
+	Host string `json:"host"`
+
+	// Pages is the number of pages that used to learn this profile.
+	Pages int `json:"pages"`
+
+	// Paths is the DOM path of link-heavy elements that recur across pages,
+	// e.g. "body>div#page>aside.sidebar". The path is made of tag name, id and
+	// class names of each element, so it's not affected by the element position.
+	Paths []string `json:"paths"`
+
+	// Texts is the text blocks that recur across pages, in lowercase.
+	Texts []string `json:"texts"`
+}
+
+// SiteLearner learns the boilerplate of a website from its pages.
+type SiteLearner struct {
+	// MinRatio is the minimum ratio of pages that must contain the block, so
+	// it considered as boilerplate. Default to 0.6.
+	MinRatio float64
+
+	host       string
+	pages      int
+	pathCounts map[string]int
+	pathLinks  map[string]float64
+	textCounts map[string]int
+}
+
+// Max length of text block that checked for boilerplate. Longer block is unlikely
+// to be a boilerplate, and it's expensive to keep.
+const maxBoilerplateTextLength = 1000
+
+// Min link density for element to be considered as boilerplate by its path.
+const minBoilerplateLinkDensity = 0.6
+
+var boilerplateBlockTags = []string{
+	"p", "div", "section", "aside", "header", "footer", "nav", "li", "ul", "ol",
+	"h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "figcaption", "table", "dl", "form",
+}
+
+// NewSiteLearner returns a new learner for the specified host.
+func NewSiteLearner(host string) *SiteLearner {
+	return &SiteLearner{
+		MinRatio:   0.6,
+		host:       host,
+		pathCounts: make(map[string]int),
+		pathLinks:  make(map[string]float64),
+		textCounts: make(map[string]int),
+	}
+}
+
+// Add learns the blocks of a single page.
+func (sl *SiteLearner) Add(doc *html.Node) {
+	body := dom.QuerySelector(doc, "body")
+	if body == nil {
+		return
+	}
+
+	// Each block is only counted once per page
+	pagePaths := make(map[string]float64)
+	pageTexts := make(map[string]struct{})
+
+	for _, block := range findBoilerplateBlocks(body) {
+		if block.text != "" {
+			pageTexts[block.text] = struct{}{}
+		}
+
+		if _, exist := pagePaths[block.path]; !exist {
+			pagePaths[block.path] = block.linkDensity()
+		}
+	}
+
+	sl.pages++
+	for text := range pageTexts {
+		sl.textCounts[text]++
+	}
+
+	for path, density := range pagePaths {
+		sl.pathCounts[path]++
+		sl.pathLinks[path] += density
+	}
+}
+
+// Profile returns the boilerplate profile from pages that learned so far. At least
+// two pages are required to find the recurring blocks.
+func (sl *SiteLearner) Profile() *SiteProfile {
+	profile := &SiteProfile{Host: sl.host, Pages: sl.pages}
+	if sl.pages < 2 {
+		return profile
+	}
+
+	minCount := max(int(math.Ceil(sl.MinRatio*float64(sl.pages))), 2)
+	for text, count := range sl.textCounts {
+		if count >= minCount {
+			profile.Texts = append(profile.Texts, text)
+		}
+	}
+
+	for path, count := range sl.pathCounts {
+		if count >= minCount && sl.pathLinks[path]/float64(count) >= minBoilerplateLinkDensity {
+			profile.Paths = append(profile.Paths, path)
+		}
+	}
+
+	// Remove paths whose ancestor is already listed, since it will be removed as well
+	allPaths := slices.Clone(profile.Paths)
+	profile.Paths = slices.DeleteFunc(profile.Paths, func(path string) bool {
+		return slices.ContainsFunc(allPaths, func(ancestor string) bool {
+			return strings.HasPrefix(path, ancestor+">")
+		})
+	})
+	slices.Sort(profile.Paths)
+
+	slices.Sort(profile.Texts)
+	return profile
+}
+
+// LoadSiteProfile reads the JSON encoded site profile.
+func LoadSiteProfile(r io.Reader) (*SiteProfile, error) {
+	var profile SiteProfile
+	if err := json.NewDecoder(r).Decode(&profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Save writes the site profile as JSON.
+func (sp *SiteProfile) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(sp)
+}
+
+// boilerplateNodes returns the blocks inside body that match the boilerplate in
+// this profile.
+func (sp *SiteProfile) boilerplateNodes(body *html.Node) []*html.Node {
+	paths := make(map[string]struct{}, len(sp.Paths))
+	for _, path := range sp.Paths {
+		paths[path] = struct{}{}
+	}
+
+	texts := make(map[string]struct{}, len(sp.Texts))
+	for _, text := range sp.Texts {
+		texts[text] = struct{}{}
+	}
+
+	var nodes []*html.Node
+	for _, block := range findBoilerplateBlocks(body) {
+		if _, exist := texts[block.text]; exist && block.text != "" {
+			nodes = append(nodes, block.node)
+			continue
+		}
+
+		if _, exist := paths[block.path]; exist && block.linkDensity() >= minBoilerplateLinkDensity {
+			nodes = append(nodes, block.node)
+		}
+	}
+
+	return nodes
+}
+
+// pruneSiteBoilerplate removes the boilerplate that listed in profile from a copy
+// of the document. If it removes too much, the original document is used instead.
+func pruneSiteBoilerplate(doc *html.Node, profile *SiteProfile) *html.Node {
+	if profile == nil || (len(profile.Paths) == 0 && len(profile.Texts) == 0) {
+		return doc
+	}
+
+	clone := dom.Clone(doc, true)
+	body := dom.QuerySelector(clone, "body")
+	if body == nil {
+		return doc
+	}
+
+	nodes := profile.boilerplateNodes(body)
+	if len(nodes) == 0 {
+		return doc
+	}
+
+	boilerplate := make(map[*html.Node]struct{}, len(nodes))
+	for _, node := range nodes {
+		boilerplate[node] = struct{}{}
+	}
+
+	oldLen := utf8.RuneCountInString(dom.TextContent(clone))
+	pruneUnwantedNodesInPlace(clone, []selector.Rule{func(n *html.Node) bool {
+		_, exist := boilerplate[n]
+		return exist
+	}})
+
+	if utf8.RuneCountInString(dom.TextContent(clone)) <= oldLen/7 {
+		return doc
+	}
+	return clone
+}
+
+// _BoilerplateBlock is a block element along with the values that used to match
+// it against the site profile.
+type _BoilerplateBlock struct {
+	node *html.Node
+
+	// path is the path of element from the body. Id and class names that contain
+	// digit are skipped since they are usually unique for each page.
+	path string
+
+	// text is the normalized text of a leaf block, i.e. block that doesn't have any
+	// block inside it. Empty for other blocks.
+	text string
+
+	textLength int
+	linkLength int
+}
+
+// linkDensity returns the ratio of text inside links to the whole text of block.
+func (b _BoilerplateBlock) linkDensity() float64 {
+	if b.textLength == 0 {
+		return 0
+	}
+	return float64(b.linkLength) / float64(b.textLength)
+}
+
+// findBoilerplateBlocks returns the block elements inside body in document order.
+// Their path, text and link density are computed in a single post-order pass, so
+// each node is only visited once.
+func findBoilerplateBlocks(body *html.Node) []_BoilerplateBlock {
+	var blocks []_BoilerplateBlock
+
+	var walk func(node *html.Node, path string, inLink bool) (hasBlock bool, textLength, linkLength int)
+	walk = func(node *html.Node, path string, inLink bool) (hasBlock bool, textLength, linkLength int) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			switch child.Type {
+			case html.TextNode:
+				length := utf8.RuneCountInString(trim(child.Data))
+				textLength += length
+				if inLink {
+					linkLength += length
+				}
+
+			case html.ElementNode:
+				tagName := dom.TagName(child)
+				childPath := path + ">" + boilerplatePathPart(child)
+				isBlock := slices.Contains(boilerplateBlockTags, tagName)
+
+				// Block is added before its children, so the order is kept
+				idx := len(blocks)
+				if isBlock {
+					blocks = append(blocks, _BoilerplateBlock{node: child, path: childPath})
+				}
+
+				childHasBlock, childText, childLinks := walk(child, childPath, inLink || tagName == "a")
+				textLength += childText
+				linkLength += childLinks
+				hasBlock = hasBlock || isBlock || childHasBlock
+
+				if isBlock {
+					blocks[idx].textLength = childText
+					blocks[idx].linkLength = childLinks
+					if !childHasBlock {
+						blocks[idx].text = boilerplateText(child)
+					}
+				}
+			}
+		}
+		return
+	}
+
+	walk(body, "body", false)
+	return blocks
+}
+
+// boilerplateText returns the normalized text of a leaf block, or empty string if
+// it's too long or doesn't contain any letter.
+func boilerplateText(node *html.Node) string {
+	text := strings.ToLower(trim(dom.TextContent(node)))
+	if utf8.RuneCountInString(text) > maxBoilerplateTextLength || !strings.ContainsFunc(text, unicode.IsLetter) {
+		return ""
+	}
+	return text
+}
+
+func hasBlockDescendant(node *html.Node) bool {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+
+		if slices.Contains(boilerplateBlockTags, dom.TagName(child)) || hasBlockDescendant(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// boilerplatePathPart returns the part of boilerplate path for a single element.
+func boilerplatePathPart(node *html.Node) string {
+	part := dom.TagName(node)
+	if id := dom.ID(node); id != "" && !strings.ContainsFunc(id, unicode.IsDigit) {
+		part += "#" + id
+	}
+
+	for _, class := range strings.Fields(dom.ClassName(node)) {
+		if !strings.ContainsFunc(class, unicode.IsDigit) {
+			part += "." + class
+		}
+	}
+
+	return part
+}
+
+// linkDensity returns the ratio of text inside links to the whole text of element.
+func linkDensity(node *html.Node) float64 {
+	textLength := utf8.RuneCountInString(trim(dom.TextContent(node)))
+	if textLength == 0 {
+		return 0
+	}
+
+	var linkLength int
+	for _, a := range dom.GetElementsByTagName(node, "a") {
+		linkLength += utf8.RuneCountInString(trim(dom.TextContent(a)))
+	}
+
+	return float64(linkLength) / float64(textLength)
+}
@@ -0,0 +1,94 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SiteLearner(t *testing.T) {
+	createPage := func(number int) string {
+		var related string
+		for i := 1; i <= 4; i++ {
+			related += fmt.Sprintf(`<li><a href="/story-%d-%d">Related story number %d of page %d</a></li>`, number, i, i, number)
+		}
+
+		return fmt.Sprintf(`<html><body><div id="page"><article>
+			<h1>Story number %d</h1>
+			<p>%s</p>
+			<p>John Doe is a senior writer who covers technology and science for Example Magazine.</p>
+			</article>
+			<div class="related"><ul>%s</ul></div>
+			<footer><p>Example Magazine is a registered trademark, all rights reserved.</p></footer>
+			</div></body></html>`,
+			number, strings.Repeat(fmt.Sprintf("The unique content of story number %d. ", number), 20), related)
+	}
+
+	learner := NewSiteLearner("example.org")
+	for i := 1; i <= 3; i++ {
+		learner.Add(docFromStr(createPage(i)))
+	}
+
+	profile := learner.Profile()
+	assert.Equal(t, "example.org", profile.Host)
+	assert.Equal(t, 3, profile.Pages)
+	assert.Contains(t, profile.Texts, "john doe is a senior writer who covers technology and science for example magazine.")
+	assert.Contains(t, profile.Texts, "example magazine is a registered trademark, all rights reserved.")
+	assert.Contains(t, profile.Paths, "body>div#page>div.related")
+	assert.NotContains(t, profile.Paths, "body>div#page>div.related>ul")
+	for _, text := range profile.Texts {
+		assert.NotContains(t, text, "unique content")
+	}
+
+	// Profile can be saved and loaded
+	var buf bytes.Buffer
+	assert.NoError(t, profile.Save(&buf))
+	loaded, err := LoadSiteProfile(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, profile, loaded)
+
+	// Without profile, the boilerplate is extracted
+	rawHTML := createPage(4)
+	opts := zeroOpts
+	opts.EnableFallback = false
+	opts.IncludeLinks = true
+
+	result, err := ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Contains(t, result.ContentText, "senior writer")
+	assert.Contains(t, result.ContentText, "The unique content of story number 4.")
+
+	// With profile, the boilerplate is removed
+	opts.SiteProfile = loaded
+	result, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.ContentText, "senior writer")
+	assert.NotContains(t, result.ContentText, "Related story")
+	assert.Contains(t, result.ContentText, "The unique content of story number 4.")
+
+	// A single page is not enough to learn
+	learner = NewSiteLearner("example.org")
+	learner.Add(docFromStr(createPage(1)))
+	assert.Empty(t, learner.Profile().Texts)
+}
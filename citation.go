@@ -0,0 +1,318 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Entry types of BibTeX and RIS for each CSL type.
+var (
+	bibTeXTypes = map[string]string{
+		"article-journal":  "article",
+		"paper-conference": "inproceedings",
+		"thesis":           "phdthesis",
+		"report":           "techreport",
+		"chapter":          "incollection",
+		"book":             "book",
+	}
+
+	risTypes = map[string]string{
+		"article-journal":  "JOUR",
+		"paper-conference": "CPAPER",
+		"thesis":           "THES",
+		"report":           "RPRT",
+		"chapter":          "CHAP",
+		"book":             "BOOK",
+		"preprint":         "UNPB",
+	}
+)
+
+var bibTeXEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`, `{`, `\{`, `}`, `\}`,
+	`&`, `\&`, `%`, `\%`, `$`, `\$`, `#`, `\#`, `_`, `\_`,
+	`~`, `\textasciitilde{}`, `^`, `\textasciicircum{}`,
+)
+
+// CitationKey returns the key that used to cite the record in BibTeX, created from
+// the family name of the first author, the year and the first word of the title,
+// e.g. "doe2021deep".
+func (r ScholarlyRecord) CitationKey() string {
+	var parts []string
+	if len(r.Authors) > 0 {
+		parts = append(parts, strOr(r.Authors[0].Family, r.Authors[0].Name))
+	}
+
+	if r.DateParts > 0 {
+		parts = append(parts, r.Date.Format("2006"))
+	}
+
+	for _, word := range strings.Fields(r.Title) {
+		if word = citationKeyPart(word); len(word) > 3 {
+			parts = append(parts, word)
+			break
+		}
+	}
+
+	var key string
+	for _, part := range parts {
+		key += citationKeyPart(part)
+	}
+
+	return strOr(key, "untitled")
+}
+
+// BibTeX returns the record as BibTeX entry.
+func (r ScholarlyRecord) BibTeX() string {
+	entryType := bibTeXTypes[r.Type]
+	if entryType == "" {
+		entryType = "misc"
+	}
+
+	var fields [][2]string
+	addField := func(name, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			fields = append(fields, [2]string{name, value})
+		}
+	}
+
+	var authors []string
+	for _, author := range r.Authors {
+		if author.Family != "" && author.Given != "" {
+			authors = append(authors, bibTeXEscaper.Replace(author.Family)+", "+bibTeXEscaper.Replace(author.Given))
+		} else {
+			authors = append(authors, "{"+bibTeXEscaper.Replace(author.Name)+"}")
+		}
+	}
+
+	addField("title", r.Title)
+	addField("author", strings.Join(authors, " and "))
+	switch entryType {
+	case "article":
+		addField("journal", r.Journal)
+	case "inproceedings", "incollection":
+		addField("booktitle", strOr(r.Conference, r.Journal))
+	case "phdthesis":
+		addField("school", r.Institution)
+	case "techreport":
+		addField("institution", strOr(r.Institution, r.Publisher))
+	}
+
+	if r.DateParts > 0 {
+		addField("year", r.Date.Format("2006"))
+	}
+
+	// Month is written as macro, e.g. "month = mar", so it's localized by BibTeX style
+	if r.DateParts > 1 {
+		addField("month", strings.ToLower(r.Date.Format("Jan")))
+	}
+
+	addField("volume", r.Volume)
+	addField("number", r.Issue)
+	addField("pages", r.pages("--"))
+	addField("publisher", r.Publisher)
+	addField("issn", strings.Join(r.ISSN, ", "))
+	addField("isbn", r.ISBN)
+	addField("doi", r.DOI)
+	if r.ArXivID != "" {
+		addField("eprint", r.ArXivID)
+		addField("archiveprefix", "arXiv")
+	}
+	addField("url", r.URL)
+	addField("abstract", r.Abstract)
+	addField("keywords", strings.Join(r.Keywords, ", "))
+	addField("language", r.Language)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "@%s{%s,\n", entryType, r.CitationKey())
+	for i, field := range fields {
+		name, value := field[0], field[1]
+		switch name {
+		case "month":
+			fmt.Fprintf(&sb, "  %s = %s", name, value)
+		case "author", "url", "doi":
+			// Author is already escaped, while URL and DOI are used verbatim
+			fmt.Fprintf(&sb, "  %s = {%s}", name, value)
+		default:
+			fmt.Fprintf(&sb, "  %s = {%s}", name, bibTeXEscaper.Replace(value))
+		}
+
+		if i < len(fields)-1 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// RIS returns the record in RIS format.
+func (r ScholarlyRecord) RIS() string {
+	var sb strings.Builder
+	addTag := func(tag, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			fmt.Fprintf(&sb, "%s  - %s\n", tag, value)
+		}
+	}
+
+	entryType := risTypes[r.Type]
+	if entryType == "" {
+		entryType = "GEN"
+	}
+
+	addTag("TY", entryType)
+	addTag("TI", r.Title)
+	for _, author := range r.Authors {
+		if author.Family != "" && author.Given != "" {
+			addTag("AU", author.Family+", "+author.Given)
+		} else {
+			addTag("AU", author.Name)
+		}
+
+		for _, affiliation := range author.Affiliations {
+			addTag("AD", affiliation)
+		}
+	}
+
+	addTag("T2", strOr(r.Journal, r.Conference))
+	addTag("J2", r.JournalAbbrev)
+	switch r.DateParts {
+	case 1:
+		addTag("PY", r.Date.Format("2006"))
+	case 2:
+		addTag("PY", r.Date.Format("2006"))
+		addTag("DA", r.Date.Format("2006/01//"))
+	case 3:
+		addTag("PY", r.Date.Format("2006"))
+		addTag("DA", r.Date.Format("2006/01/02/"))
+	}
+
+	addTag("VL", r.Volume)
+	addTag("IS", r.Issue)
+	addTag("SP", r.FirstPage)
+	addTag("EP", r.LastPage)
+	addTag("PB", strOr(r.Publisher, r.Institution))
+	for _, issn := range r.ISSN {
+		addTag("SN", issn)
+	}
+	addTag("SN", r.ISBN)
+	addTag("DO", r.DOI)
+	addTag("UR", r.URL)
+	addTag("L1", r.PDFURL)
+	addTag("AB", r.Abstract)
+	for _, keyword := range r.Keywords {
+		addTag("KW", keyword)
+	}
+	addTag("LA", r.Language)
+	sb.WriteString("ER  - \n")
+	return sb.String()
+}
+
+// CSL returns the record as CSL-JSON item, which can be encoded to JSON and used
+// by citation processors like citeproc and Zotero.
+func (r ScholarlyRecord) CSL() map[string]any {
+	item := map[string]any{
+		"id":   r.CitationKey(),
+		"type": strOr(r.Type, "article"),
+	}
+
+	addField := func(name, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			item[name] = value
+		}
+	}
+
+	addField("title", r.Title)
+	addField("container-title", strOr(r.Journal, r.Conference))
+	addField("container-title-short", r.JournalAbbrev)
+	addField("event-title", r.Conference)
+	addField("publisher", strOr(r.Publisher, r.Institution))
+	addField("volume", r.Volume)
+	addField("issue", r.Issue)
+	addField("page", r.pages("-"))
+	addField("ISSN", strings.Join(r.ISSN, ", "))
+	addField("ISBN", r.ISBN)
+	addField("DOI", r.DOI)
+	addField("URL", r.URL)
+	addField("abstract", r.Abstract)
+	addField("keyword", strings.Join(r.Keywords, ", "))
+	addField("language", r.Language)
+	if r.ArXivID != "" {
+		addField("number", "arXiv:"+r.ArXivID)
+	}
+
+	var authors []map[string]string
+	for _, author := range r.Authors {
+		if author.Family != "" && author.Given != "" {
+			authors = append(authors, map[string]string{"family": author.Family, "given": author.Given})
+		} else {
+			authors = append(authors, map[string]string{"literal": author.Name})
+		}
+	}
+
+	if len(authors) > 0 {
+		item["author"] = authors
+	}
+
+	if r.DateParts > 0 {
+		dateParts := []int{r.Date.Year(), int(r.Date.Month()), r.Date.Day()}
+		item["issued"] = map[string]any{"date-parts": [][]int{dateParts[:min(r.DateParts, 3)]}}
+	}
+
+	return item
+}
+
+// CSLJSON returns the record as CSL-JSON, i.e. an array that contains a single item.
+func (r ScholarlyRecord) CSLJSON() ([]byte, error) {
+	// Characters like "&" are common in titles, so don't escape them
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode([]map[string]any{r.CSL()}); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buffer.Bytes()), nil
+}
+
+// pages returns the page range, joined with the separator.
+func (r ScholarlyRecord) pages(separator string) string {
+	switch {
+	case r.FirstPage != "" && r.LastPage != "" && r.FirstPage != r.LastPage:
+		return r.FirstPage + separator + r.LastPage
+	default:
+		return r.FirstPage
+	}
+}
+
+// citationKeyPart converts the string into lowercase ASCII letters and digits.
+func citationKeyPart(s string) string {
+	var sb strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return sb.String()
+}
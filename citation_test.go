@@ -0,0 +1,112 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testScholarlyRecord = ScholarlyRecord{
+	Type:  "article-journal",
+	Title: "Ökologie & Deep Learning",
+	Authors: []ScholarlyAuthor{
+		{Name: "Jane Doe", Given: "Jane", Family: "Doe", Affiliations: []string{"University of Somewhere"}},
+		{Name: "OpenScience Consortium"},
+	},
+	Date:      time.Date(2021, 3, 15, 0, 0, 0, 0, time.UTC),
+	DateParts: 2,
+	Journal:   "Journal of Marine Science",
+	Volume:    "12",
+	Issue:     "3",
+	FirstPage: "101",
+	LastPage:  "115",
+	ISSN:      []string{"1234-5678"},
+	DOI:       "10.1234/jms_2021",
+	URL:       "https://example.org/articles/42",
+	Keywords:  []string{"coral", "deep learning"},
+}
+
+func Test_ScholarlyRecord_BibTeX(t *testing.T) {
+	expected := "@article{doe2021okologie,\n" +
+		"  title = {Ökologie \\& Deep Learning},\n" +
+		"  author = {Doe, Jane and {OpenScience Consortium}},\n" +
+		"  journal = {Journal of Marine Science},\n" +
+		"  year = {2021},\n" +
+		"  month = mar,\n" +
+		"  volume = {12},\n" +
+		"  number = {3},\n" +
+		"  pages = {101--115},\n" +
+		"  issn = {1234-5678},\n" +
+		"  doi = {10.1234/jms_2021},\n" +
+		"  url = {https://example.org/articles/42},\n" +
+		"  keywords = {coral, deep learning}\n" +
+		"}\n"
+	assert.Equal(t, expected, testScholarlyRecord.BibTeX())
+
+	// Special characters in title and authors are escaped
+	record := ScholarlyRecord{
+		Title:   "50% of {Reefs} & More",
+		Authors: []ScholarlyAuthor{{Name: "R&D {Lab}"}, {Name: "Anne O'Neil", Given: "Anne", Family: "O'Neil_Smith"}},
+	}
+	expected = "@misc{rdlabreefs,\n" +
+		"  title = {50\\% of \\{Reefs\\} \\& More},\n" +
+		"  author = {{R\\&D \\{Lab\\}} and O'Neil\\_Smith, Anne}\n" +
+		"}\n"
+	assert.Equal(t, expected, record.BibTeX())
+
+	// Unknown type is exported as misc
+	assert.Equal(t, "@misc{untitled,\n}\n", ScholarlyRecord{}.BibTeX())
+}
+
+func Test_ScholarlyRecord_RIS(t *testing.T) {
+	expected := "TY  - JOUR\n" +
+		"TI  - Ökologie & Deep Learning\n" +
+		"AU  - Doe, Jane\n" +
+		"AD  - University of Somewhere\n" +
+		"AU  - OpenScience Consortium\n" +
+		"T2  - Journal of Marine Science\n" +
+		"PY  - 2021\n" +
+		"DA  - 2021/03//\n" +
+		"VL  - 12\n" +
+		"IS  - 3\n" +
+		"SP  - 101\n" +
+		"EP  - 115\n" +
+		"SN  - 1234-5678\n" +
+		"DO  - 10.1234/jms_2021\n" +
+		"UR  - https://example.org/articles/42\n" +
+		"KW  - coral\n" +
+		"KW  - deep learning\n" +
+		"ER  - \n"
+	assert.Equal(t, expected, testScholarlyRecord.RIS())
+}
+
+func Test_ScholarlyRecord_CSLJSON(t *testing.T) {
+	expected := `[{"DOI":"10.1234/jms_2021","ISSN":"1234-5678","URL":"https://example.org/articles/42",` +
+		`"author":[{"family":"Doe","given":"Jane"},{"literal":"OpenScience Consortium"}],` +
+		`"container-title":"Journal of Marine Science","id":"doe2021okologie",` +
+		`"issue":"3","issued":{"date-parts":[[2021,3]]},"keyword":"coral, deep learning",` +
+		`"page":"101-115","title":"Ökologie & Deep Learning","type":"article-journal","volume":"12"}]`
+
+	data, err := testScholarlyRecord.CSLJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(data))
+}
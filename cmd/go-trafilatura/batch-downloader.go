@@ -37,6 +37,11 @@ type batchDownloader struct {
 	delay          time.Duration
 	cancelOnError  bool
 	writeFunc      func(*trafilatura.ExtractResult, *nurl.URL, int) error
+	maxPages       int
+
+	// prepareOptions is optional function to adjust the extraction options
+	// for each URL, e.g. to add metadata hints.
+	prepareOptions func(*trafilatura.Options, *nurl.URL, int)
 }
 
 func (bd *batchDownloader) downloadURLs(ctx context.Context, urls []*nurl.URL) error {
@@ -53,7 +58,12 @@ func (bd *batchDownloader) downloadURLs(ctx context.Context, urls []*nurl.URL) e
 			}
 
 			// Process URL
-			result, err := processURL(bd.httpClient, bd.userAgent, url, bd.extractOptions)
+			opts := bd.extractOptions
+			if bd.prepareOptions != nil {
+				bd.prepareOptions(&opts, url, i)
+			}
+
+			result, err := processURL(bd.httpClient, bd.userAgent, url, opts, bd.maxPages)
 			bd.semaphore.Release(1)
 			if err != nil {
 				if bd.cancelOnError {
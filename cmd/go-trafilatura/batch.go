@@ -61,6 +61,7 @@ func batchCmdHandler(cmd *cobra.Command, args []string) {
 	nThread, _ := flags.GetInt("parallel")
 	outputDir, _ := flags.GetString("output")
 	userAgent, _ := cmd.Flags().GetString("user-agent")
+	maxPages, _ := cmd.Flags().GetInt("max-pages")
 
 	// Parse input file
 	urls, names, err := parseBatchFile(cmd, args[0])
@@ -95,6 +96,7 @@ func batchCmdHandler(cmd *cobra.Command, args []string) {
 		delay:          time.Duration(delay) * time.Second,
 		cancelOnError:  false,
 		writeFunc:      fnWrite,
+		maxPages:       maxPages,
 	}).downloadURLs(context.Background(), urls)
 
 	if err != nil {
@@ -0,0 +1,227 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Max edit distance that calculated when computing similarity ratio. Beyond this,
+// the documents are considered very different and the ratio is only estimated.
+const maxRatioDistance = 5000
+
+type diffOp byte
+
+const (
+	diffEqual  diffOp = ' '
+	diffDelete diffOp = '-'
+	diffInsert diffOp = '+'
+)
+
+type diffEdit struct {
+	op   diffOp
+	text string
+}
+
+// diffLines returns the shortest edit script that converts a into b, using the
+// Myers' diff algorithm.
+func diffLines(a, b []string) []diffEdit {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+
+	// Find the shortest path, saving the state of each step for backtracking
+	var trace [][]int
+	var found bool
+	for d := 0; d <= n+m && !found; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+
+		// Only the diagonals that reachable in this step are needed
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+
+	// Backtrack from the end to build the edit script
+	var edits []diffEdit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		k := x - y
+		var prevK int
+		if d > 0 {
+			prev := trace[d-1]
+			get := func(k int) int { return prev[k+d-1] }
+			if k == -d || (k != d && get(k-1) < get(k+1)) {
+				prevK = k + 1
+			} else {
+				prevK = k - 1
+			}
+
+			prevX := get(prevK)
+			prevY := prevX - prevK
+			for x > prevX && y > prevY {
+				x, y = x-1, y-1
+				edits = append(edits, diffEdit{diffEqual, a[x]})
+			}
+
+			if x == prevX {
+				y--
+				edits = append(edits, diffEdit{diffInsert, b[y]})
+			} else {
+				x--
+				edits = append(edits, diffEdit{diffDelete, a[x]})
+			}
+		} else {
+			for x > 0 && y > 0 {
+				x, y = x-1, y-1
+				edits = append(edits, diffEdit{diffEqual, a[x]})
+			}
+		}
+	}
+
+	// Reverse since it's built from the end
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
+
+// similarityRatio returns the similarity of two token lists between 0 and 1, i.e.
+// twice the number of matching tokens divided by the total number of tokens.
+func similarityRatio(a, b []string) float64 {
+	n, m := len(a), len(b)
+	if n+m == 0 {
+		return 1
+	}
+
+	// Find the edit distance, we don't need the script so only keep the last state
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	distance := min(n+m, maxRatioDistance)
+
+	for d := 0; d <= distance; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return float64(n+m-d) / float64(n+m)
+			}
+		}
+	}
+
+	return float64(n+m-distance) / float64(n+m)
+}
+
+// unifiedDiff formats the line differences in unified diff format, with the
+// specified number of context lines around each change.
+func unifiedDiff(nameA, nameB string, a, b []string, context int) string {
+	edits := diffLines(a, b)
+
+	// Find the changed edits, then group the nearby changes into hunks
+	type hunk struct{ start, end int }
+	var hunks []hunk
+	for i, edit := range edits {
+		if edit.op == diffEqual {
+			continue
+		}
+
+		start, end := max(i-context, 0), min(i+context+1, len(edits))
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].end {
+			hunks[len(hunks)-1].end = end
+		} else {
+			hunks = append(hunks, hunk{start, end})
+		}
+	}
+
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	// Count the line number of each edit
+	lineA := make([]int, len(edits)+1)
+	lineB := make([]int, len(edits)+1)
+	for i, edit := range edits {
+		lineA[i+1], lineB[i+1] = lineA[i], lineB[i]
+		if edit.op != diffInsert {
+			lineA[i+1]++
+		}
+		if edit.op != diffDelete {
+			lineB[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", nameA, nameB)
+	for _, h := range hunks {
+		countA := lineA[h.end] - lineA[h.start]
+		countB := lineB[h.end] - lineB[h.start]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(lineA[h.start], countA),
+			hunkRange(lineB[h.start], countB))
+
+		for _, edit := range edits[h.start:h.end] {
+			sb.WriteByte(byte(edit.op))
+			sb.WriteString(edit.text)
+			sb.WriteByte('\n')
+		}
+	}
+
+	return sb.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,325 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Metadata fields that evaluated, in the order they are reported.
+var evalFields = []string{"title", "author", "date", "sitename", "description", "license", "categories", "tags"}
+
+var evalReportWriters = map[string]func(io.Writer, []evalRunResult) error{
+	"table": writeEvalTable,
+	"json":  writeEvalJSON,
+	"junit": writeEvalJUnit,
+}
+
+type evalCounts struct {
+	TruePositives  int `json:"truePositives"`
+	FalseNegatives int `json:"falseNegatives"`
+	FalsePositives int `json:"falsePositives"`
+	TrueNegatives  int `json:"trueNegatives"`
+}
+
+type evalMismatch struct {
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+type evalFieldCheck struct {
+	field   string
+	correct bool
+}
+
+type evalDocResult struct {
+	Name       string         `json:"name"`
+	Passed     bool           `json:"passed"`
+	Error      string         `json:"error,omitempty"`
+	Missing    []string       `json:"missing,omitempty"`
+	Unwanted   []string       `json:"unwanted,omitempty"`
+	Mismatches []evalMismatch `json:"metadataMismatches,omitempty"`
+
+	counts evalCounts
+	fields []evalFieldCheck
+}
+
+type evalFieldScore struct {
+	Field    string  `json:"field"`
+	Total    int     `json:"total"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+type evalRunResult struct {
+	Name      string           `json:"name"`
+	Duration  float64          `json:"durationSeconds"`
+	Documents int              `json:"documents"`
+	Failures  int              `json:"failures"`
+	Counts    evalCounts       `json:"counts"`
+	Precision float64          `json:"precision"`
+	Recall    float64          `json:"recall"`
+	Accuracy  float64          `json:"accuracy"`
+	FScore    float64          `json:"fScore"`
+	Metadata  []evalFieldScore `json:"metadata"`
+	Results   []evalDocResult  `json:"results"`
+}
+
+// failureMessage describes why the document failed, one problem per line.
+func (dr evalDocResult) failureMessage() string {
+	var lines []string
+	if dr.Error != "" {
+		lines = append(lines, "error: "+dr.Error)
+	}
+
+	for _, str := range dr.Missing {
+		lines = append(lines, fmt.Sprintf("missing: %q", str))
+	}
+
+	for _, str := range dr.Unwanted {
+		lines = append(lines, fmt.Sprintf("unwanted: %q", str))
+	}
+
+	for _, m := range dr.Mismatches {
+		lines = append(lines, fmt.Sprintf("%s: expected %q, got %q", m.Field, m.Expected, m.Actual))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func summarizeRun(name string, duration time.Duration, docResults []evalDocResult) evalRunResult {
+	result := evalRunResult{
+		Name:      name,
+		Duration:  duration.Seconds(),
+		Documents: len(docResults),
+	}
+
+	fieldScores := make(map[string]*evalFieldScore)
+	for _, field := range evalFields {
+		fieldScores[field] = &evalFieldScore{Field: field}
+	}
+
+	for i, dr := range docResults {
+		result.Counts.TruePositives += dr.counts.TruePositives
+		result.Counts.FalseNegatives += dr.counts.FalseNegatives
+		result.Counts.FalsePositives += dr.counts.FalsePositives
+		result.Counts.TrueNegatives += dr.counts.TrueNegatives
+
+		for _, check := range dr.fields {
+			fieldScores[check.field].Total++
+			if check.correct {
+				fieldScores[check.field].Correct++
+			}
+		}
+
+		docResults[i].Passed = dr.failureMessage() == ""
+		if !docResults[i].Passed {
+			result.Failures++
+		}
+	}
+
+	tp := float64(result.Counts.TruePositives)
+	fn := float64(result.Counts.FalseNegatives)
+	fp := float64(result.Counts.FalsePositives)
+	tn := float64(result.Counts.TrueNegatives)
+	result.Precision = safeDivide(tp, tp+fp)
+	result.Recall = safeDivide(tp, tp+fn)
+	result.Accuracy = safeDivide(tp+tn, tp+tn+fp+fn)
+	result.FScore = safeDivide(2*tp, 2*tp+fp+fn)
+
+	for _, field := range evalFields {
+		score := fieldScores[field]
+		if score.Total > 0 {
+			score.Accuracy = float64(score.Correct) / float64(score.Total)
+			result.Metadata = append(result.Metadata, *score)
+		}
+	}
+
+	result.Results = docResults
+	return result
+}
+
+func safeDivide(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+func writeEvalTable(w io.Writer, results []evalRunResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	// Print failures of each run
+	for _, result := range results {
+		if result.Failures == 0 {
+			continue
+		}
+
+		fmt.Fprintf(tw, "Failures in %s:\n", result.Name)
+		for _, dr := range result.Results {
+			if dr.Passed {
+				continue
+			}
+
+			fmt.Fprintf(tw, "- %s\n", dr.Name)
+			for line := range strings.SplitSeq(dr.failureMessage(), "\n") {
+				fmt.Fprintf(tw, "    %s\n", line)
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+
+	// Print aggregate scores
+	fmt.Fprintln(tw, "Run\tDocuments\tFailures\tPrecision\tRecall\tAccuracy\tF-Score\tDuration (s)")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.3f\t%.3f\t%.3f\t%.3f\t%.3f\n",
+			r.Name, r.Documents, r.Failures, r.Precision, r.Recall, r.Accuracy, r.FScore, r.Duration)
+	}
+
+	// Print metadata accuracy, one column per run
+	hasMetadata := false
+	for _, r := range results {
+		hasMetadata = hasMetadata || len(r.Metadata) > 0
+	}
+
+	if hasMetadata {
+		fmt.Fprintln(tw)
+		fmt.Fprint(tw, "Metadata")
+		for _, r := range results {
+			fmt.Fprintf(tw, "\t%s", r.Name)
+		}
+		fmt.Fprintln(tw)
+
+		for _, field := range evalFields {
+			var cells []string
+			for _, r := range results {
+				cell := "-"
+				for _, score := range r.Metadata {
+					if score.Field == field {
+						cell = fmt.Sprintf("%.3f (%d/%d)", score.Accuracy, score.Correct, score.Total)
+					}
+				}
+				cells = append(cells, cell)
+			}
+
+			if strings.Trim(strings.Join(cells, ""), "-") != "" {
+				fmt.Fprintf(tw, "%s\t%s\n", field, strings.Join(cells, "\t"))
+			}
+		}
+	}
+
+	return tw.Flush()
+}
+
+func writeEvalJSON(w io.Writer, results []evalRunResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+func writeEvalJUnit(w io.Writer, results []evalRunResult) error {
+	var suites junitTestSuites
+	for _, r := range results {
+		suite := junitTestSuite{
+			Name:     r.Name,
+			Tests:    r.Documents,
+			Failures: r.Failures,
+			Time:     fmt.Sprintf("%.3f", r.Duration),
+			Properties: []junitProperty{
+				{Name: "precision", Value: fmt.Sprintf("%.3f", r.Precision)},
+				{Name: "recall", Value: fmt.Sprintf("%.3f", r.Recall)},
+				{Name: "accuracy", Value: fmt.Sprintf("%.3f", r.Accuracy)},
+				{Name: "fScore", Value: fmt.Sprintf("%.3f", r.FScore)},
+			},
+		}
+
+		for _, score := range r.Metadata {
+			suite.Properties = append(suite.Properties, junitProperty{
+				Name:  "metadata." + score.Field,
+				Value: fmt.Sprintf("%.3f", score.Accuracy),
+			})
+		}
+
+		for _, dr := range r.Results {
+			testCase := junitTestCase{Name: dr.Name, ClassName: r.Name}
+			if !dr.Passed {
+				message := dr.failureMessage()
+				summary, _, _ := strings.Cut(message, "\n")
+				testCase.Failure = &junitFailure{Message: summary, Content: message}
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,467 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	fp "path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/markusmobius/go-trafilatura"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+var focusNames = map[string]trafilatura.ExtractionFocus{
+	"balanced":  trafilatura.Balanced,
+	"precision": trafilatura.FavorPrecision,
+	"recall":    trafilatura.FavorRecall,
+}
+
+func evalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eval [flags] [gold-set...]",
+		Short: "Evaluate extraction quality using annotated gold set",
+		Long: "Evaluate extraction quality using annotated gold set. The gold set is either a JSONL\n" +
+			"file with one document per line, a JSON file for a single document, or a directory\n" +
+			"which contains those files. Each document has the path of HTML file (relative to the\n" +
+			"gold file), its URL, the text snippets that expected to be found (\"with\") or not\n" +
+			"(\"without\") in the extracted content, and optionally the expected metadata (\"title\",\n" +
+			"\"author\", \"date\", \"sitename\", \"description\", \"license\", \"categories\" and \"tags\").\n" +
+			"If the JSON file doesn't specify the HTML file, the HTML with the same name is used.\n\n" +
+			"Every combination of the specified focus and fallback settings is evaluated, and the\n" +
+			"report is printed as table, JSON or JUnit XML.",
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			evalCmdHandler(cmd, args)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSlice("focus", []string{"balanced"}, "extraction focus to evaluate: balanced, precision and/or recall")
+	flags.BoolSlice("fallback", nil, "fallback settings to evaluate, e.g. false,true (default follows --no-fallback)")
+	flags.String("report", "table", "report format, either 'table', 'json' or 'junit'")
+	flags.StringP("output", "o", "", "file to save the report (default stdout)")
+	flags.Float64("min-fscore", 0, "exit with error if F-score of any run is below this value")
+	flags.Int("parallel", 10, "number of concurrent extraction (default 10)")
+
+	return cmd
+}
+
+// goldEntry is a single annotated document in gold set. The JSON fields are
+// compatible with the data that used in comparison script.
+type goldEntry struct {
+	URL         string   `json:"url"`
+	File        string   `json:"file"`
+	With        []string `json:"with"`
+	Without     []string `json:"without"`
+	Title       string   `json:"title"`
+	Authors     []string `json:"author"`
+	Date        string   `json:"date"`
+	Sitename    string   `json:"sitename"`
+	Description string   `json:"description"`
+	License     string   `json:"license"`
+	Categories  []string `json:"categories"`
+	Tags        []string `json:"tags"`
+
+	// path is the resolved path of HTML file
+	path string
+}
+
+func (ge goldEntry) name() string {
+	if ge.URL != "" {
+		return ge.URL
+	}
+	return ge.path
+}
+
+type evalRun struct {
+	name     string
+	focus    trafilatura.ExtractionFocus
+	fallback bool
+}
+
+func evalCmdHandler(cmd *cobra.Command, args []string) {
+	// Parse flags
+	flags := cmd.Flags()
+	focusList, _ := flags.GetStringSlice("focus")
+	fallbackList, _ := flags.GetBoolSlice("fallback")
+	reportFormat, _ := flags.GetString("report")
+	outputPath, _ := flags.GetString("output")
+	minFScore, _ := flags.GetFloat64("min-fscore")
+	nThread, _ := flags.GetInt("parallel")
+
+	fnWriteReport, exist := evalReportWriters[reportFormat]
+	if !exist {
+		log.Fatal().Msgf("unknown report format %q", reportFormat)
+	}
+
+	// Prepare the runs
+	baseOpts := createExtractorOptions(cmd)
+	if len(fallbackList) == 0 {
+		fallbackList = []bool{baseOpts.EnableFallback}
+	}
+
+	var runs []evalRun
+	for _, focusName := range focusList {
+		focus, exist := focusNames[strings.ToLower(focusName)]
+		if !exist {
+			log.Fatal().Msgf("unknown focus %q", focusName)
+		}
+
+		for _, fallback := range fallbackList {
+			name := "Trafilatura + " + strings.ToLower(focusName)
+			if fallback {
+				name += " + fallback"
+			}
+			runs = append(runs, evalRun{name: name, focus: focus, fallback: fallback})
+		}
+	}
+
+	// Load gold set
+	entries, err := loadGoldSet(args)
+	if err != nil {
+		log.Fatal().Msgf("failed to load gold set: %v", err)
+	}
+
+	if len(entries) == 0 {
+		log.Fatal().Msgf("gold set is empty")
+	}
+	log.Info().Msgf("loaded %d documents", len(entries))
+
+	// Evaluate each run
+	var results []evalRunResult
+	for _, run := range runs {
+		log.Info().Msgf("running %s", run.name)
+
+		opts := baseOpts
+		opts.Focus = run.focus
+		opts.EnableFallback = run.fallback
+
+		result, err := evaluateRun(context.Background(), run.name, entries, opts, nThread)
+		if err != nil {
+			log.Fatal().Msgf("evaluation failed: %v", err)
+		}
+		results = append(results, result)
+	}
+
+	// Write the report
+	var w io.Writer = os.Stdout
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			log.Fatal().Msgf("failed to create report: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	err = fnWriteReport(w, results)
+	if err != nil {
+		log.Fatal().Msgf("failed to write report: %v", err)
+	}
+
+	// Check the minimum score for regression gate
+	for _, result := range results {
+		if result.FScore < minFScore {
+			log.Fatal().Msgf("F-score of %s is %.3f, below the minimum %.3f", result.Name, result.FScore, minFScore)
+		}
+	}
+}
+
+// loadGoldSet loads the gold entries from the sources, which can be JSONL file,
+// JSON file or directory that contains them.
+func loadGoldSet(sources []string) ([]goldEntry, error) {
+	var entries []goldEntry
+
+	for _, source := range sources {
+		if !dirExists(source) {
+			fileEntries, err := loadGoldFile(source)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, fileEntries...)
+			continue
+		}
+
+		err := fp.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			switch strings.ToLower(fp.Ext(path)) {
+			case ".json", ".jsonl":
+				fileEntries, err := loadGoldFile(path)
+				if err != nil {
+					return err
+				}
+				entries = append(entries, fileEntries...)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+func loadGoldFile(path string) ([]goldEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Single JSON file describes one document
+	baseDir := fp.Dir(path)
+	if strings.EqualFold(fp.Ext(path), ".json") {
+		var entry goldEntry
+		if err := json.NewDecoder(f).Decode(&entry); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+
+		if entry.File == "" {
+			entry.path = findSiblingHTML(path)
+		}
+
+		entry, err = resolveGoldEntry(entry, baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		return []goldEntry{entry}, nil
+	}
+
+	// JSONL file contains one document per line
+	var entries []goldEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+
+	for nLine := 1; scanner.Scan(); nLine++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry goldEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse %s line %d: %v", path, nLine, err)
+		}
+
+		entry, err = resolveGoldEntry(entry, baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", path, nLine, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+func resolveGoldEntry(entry goldEntry, baseDir string) (goldEntry, error) {
+	if entry.path == "" && entry.File != "" {
+		entry.path = entry.File
+		if !fp.IsAbs(entry.path) {
+			entry.path = fp.Join(baseDir, entry.path)
+		}
+	}
+
+	if entry.path == "" {
+		return entry, fmt.Errorf("html file is not specified")
+	}
+
+	if !fileExists(entry.path) {
+		return entry, fmt.Errorf("html file %s not found", entry.path)
+	}
+
+	if entry.URL != "" && !isValidURL(entry.URL) {
+		log.Warn().Msgf("url %q is not valid, it won't be used for extraction", entry.URL)
+	}
+
+	return entry, nil
+}
+
+// findSiblingHTML returns the HTML file which has the same name as the JSON file.
+func findSiblingHTML(jsonPath string) string {
+	basePath := strings.TrimSuffix(jsonPath, fp.Ext(jsonPath))
+	for _, ext := range []string{".html", ".htm", ".html.gz", ".htm.gz"} {
+		if fileExists(basePath + ext) {
+			return basePath + ext
+		}
+	}
+	return ""
+}
+
+// evaluateRun extracts all entries using the options, then scores the result.
+func evaluateRun(ctx context.Context, name string, entries []goldEntry, opts trafilatura.Options, nThread int) (evalRunResult, error) {
+	docResults := make([]evalDocResult, len(entries))
+	sem := semaphore.NewWeighted(int64(max(nThread, 1)))
+	g, ctx := errgroup.WithContext(ctx)
+
+	start := time.Now()
+	for i, entry := range entries {
+		g.Go(func() error {
+			if err := sem.Acquire(ctx, 1); err != nil {
+				return err
+			}
+			defer sem.Release(1)
+
+			entryOpts := opts
+			if url, valid := validateURL(entry.URL); valid {
+				entryOpts.OriginalURL = url
+			}
+
+			result, err := processFile(entry.path, entryOpts)
+			docResults[i] = evaluateDocument(entry, result, err)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return evalRunResult{}, err
+	}
+
+	return summarizeRun(name, time.Since(start), docResults), nil
+}
+
+// evaluateDocument checks the extraction result against the gold entry.
+func evaluateDocument(entry goldEntry, result *trafilatura.ExtractResult, err error) evalDocResult {
+	docResult := evalDocResult{Name: entry.name()}
+	if err != nil {
+		docResult.Error = err.Error()
+	}
+
+	// Check the content
+	var content string
+	if result != nil {
+		content = result.ContentText
+	}
+
+	for _, str := range entry.With {
+		if content != "" && strings.Contains(content, str) {
+			docResult.counts.TruePositives++
+		} else {
+			docResult.counts.FalseNegatives++
+			docResult.Missing = append(docResult.Missing, str)
+		}
+	}
+
+	for _, str := range entry.Without {
+		if content != "" && strings.Contains(content, str) {
+			docResult.counts.FalsePositives++
+			docResult.Unwanted = append(docResult.Unwanted, str)
+		} else {
+			docResult.counts.TrueNegatives++
+		}
+	}
+
+	// Check the metadata
+	var metadata trafilatura.Metadata
+	if result != nil {
+		metadata = result.Metadata
+	}
+
+	var actualDate string
+	if !metadata.Date.IsZero() {
+		actualDate = metadata.Date.Format("2006-01-02")
+	}
+
+	expectedDate := entry.Date
+	if len(expectedDate) > 10 {
+		expectedDate = expectedDate[:10]
+	}
+
+	docResult.checkField("title", entry.Title, metadata.Title)
+	docResult.checkField("author", strings.Join(entry.Authors, "; "), metadata.Author)
+	docResult.checkField("date", expectedDate, actualDate)
+	docResult.checkField("sitename", entry.Sitename, metadata.Sitename)
+	docResult.checkField("description", entry.Description, metadata.Description)
+	docResult.checkField("license", entry.License, metadata.License)
+	docResult.checkListField("categories", entry.Categories, metadata.Categories)
+	docResult.checkListField("tags", entry.Tags, metadata.Tags)
+
+	return docResult
+}
+
+func (dr *evalDocResult) checkField(field, expected, actual string) {
+	expected = normalizeEvalText(expected)
+	if expected == "" {
+		return
+	}
+
+	actual = normalizeEvalText(actual)
+	correct := strings.EqualFold(expected, actual)
+	dr.fields = append(dr.fields, evalFieldCheck{field: field, correct: correct})
+
+	if !correct {
+		dr.Mismatches = append(dr.Mismatches, evalMismatch{
+			Field:    field,
+			Expected: expected,
+			Actual:   actual,
+		})
+	}
+}
+
+// checkListField compares list of values (e.g. categories) regardless of their order.
+func (dr *evalDocResult) checkListField(field string, expected, actual []string) {
+	normalize := func(values []string) []string {
+		var normalized []string
+		for _, value := range values {
+			if value = strings.ToLower(normalizeEvalText(value)); value != "" {
+				normalized = append(normalized, value)
+			}
+		}
+		slices.Sort(normalized)
+		return slices.Compact(normalized)
+	}
+
+	expected, actual = normalize(expected), normalize(actual)
+	if len(expected) == 0 {
+		return
+	}
+
+	correct := slices.Equal(expected, actual)
+	dr.fields = append(dr.fields, evalFieldCheck{field: field, correct: correct})
+
+	if !correct {
+		dr.Mismatches = append(dr.Mismatches, evalMismatch{
+			Field:    field,
+			Expected: strings.Join(expected, ", "),
+			Actual:   strings.Join(actual, ", "),
+		})
+	}
+}
+
+func normalizeEvalText(str string) string {
+	return strings.Join(strings.Fields(str), " ")
+}
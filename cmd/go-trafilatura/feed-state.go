@@ -0,0 +1,138 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	fp "path/filepath"
+	"time"
+
+	"github.com/markusmobius/go-trafilatura/feed"
+)
+
+// Seen entries that no longer listed in feed are forgotten after this duration.
+const feedStateRetention = 30 * 24 * time.Hour
+
+// feedState is the data that persisted between feed polls, so only the new entries
+// are processed and unchanged feed is not downloaded again.
+type feedState struct {
+	SourceURL string `json:"sourceUrl,omitempty"`
+	feedValidators
+	Seen map[string]time.Time `json:"seen"`
+}
+
+// feedValidators is the URL of the feed and its caching headers, used to make the
+// next fetch conditional.
+type feedValidators struct {
+	FeedURL      string `json:"feedUrl,omitempty"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+func loadFeedState(path string) (*feedState, error) {
+	state := &feedState{Seen: map[string]time.Time{}}
+	if path == "" {
+		return state, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, err
+	}
+
+	if state.Seen == nil {
+		state.Seen = map[string]time.Time{}
+	}
+
+	return state, nil
+}
+
+func (state *feedState) save(path string) error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to temporary file first so the state is not corrupted on crash
+	tmpPath := path + ".tmp"
+	err = os.MkdirAll(fp.Dir(path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(tmpPath, content, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// useSource makes sure the state belongs to the URL that given by user. If the state
+// is saved for another URL, the feed cache and seen entries are reset.
+func (state *feedState) useSource(sourceURL string) {
+	if state.SourceURL == sourceURL {
+		return
+	}
+
+	if state.SourceURL != "" || len(state.Seen) > 0 {
+		log.Warn().Msgf("state is saved for %q, resetting it for %q", state.SourceURL, sourceURL)
+	}
+
+	state.SourceURL = sourceURL
+	state.feedValidators = feedValidators{}
+	state.Seen = map[string]time.Time{}
+}
+
+func (state *feedState) hasSeen(entry feed.Entry) bool {
+	_, seen := state.Seen[entry.Key()]
+	return seen
+}
+
+// markSeen records the processed entries and the caching headers of the feed, then
+// forget the old entries that no longer listed in the feed to keep the state small.
+// It must only be called once the entries are successfully processed, otherwise the
+// next conditional fetch would skip the unprocessed entries.
+func (state *feedState) markSeen(parsedFeed *feed.Feed, entries []feedEntry, validators feedValidators) {
+	state.feedValidators = validators
+	now := time.Now().UTC()
+	for _, entry := range entries {
+		state.Seen[entry.Key()] = now
+	}
+
+	listed := make(map[string]struct{}, len(parsedFeed.Entries))
+	for _, entry := range parsedFeed.Entries {
+		listed[entry.Key()] = struct{}{}
+	}
+
+	for key, seenAt := range state.Seen {
+		if _, exist := listed[key]; !exist && now.Sub(seenAt) > feedStateRetention {
+			delete(state.Seen, key)
+		}
+	}
+}
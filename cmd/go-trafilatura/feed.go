@@ -19,19 +19,20 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	nurl "net/url"
 	"os"
 	fp "path/filepath"
 	"strings"
 	"time"
+	"unicode/utf8"
 
-	betree "github.com/beevik/etree"
 	"github.com/markusmobius/go-trafilatura"
+	"github.com/markusmobius/go-trafilatura/feed"
 	gonanoid "github.com/matoous/go-nanoid/v2"
 
 	"github.com/go-shiori/dom"
@@ -42,10 +43,12 @@ import (
 
 var feedTypes = sliceToMap(
 	"application/atom+xml",
+	"application/feed+json",
 	"application/rdf+xml",
 	"application/rss+xml",
 	"application/x.atom+xml",
 	"application/x-atom+xml",
+	"application/xml",
 	"text/atom+xml",
 	"text/rdf+xml",
 	"text/rss+xml",
@@ -57,9 +60,12 @@ func feedCmd() *cobra.Command {
 		Use:   "feed [flags] [url]",
 		Short: "Download and extract pages from a feed",
 		Long: "Download and extract pages from list of urls that specified in a feed.\n" +
-			"It supports both RSS and Atom feed. Trafilatura supports simple feed\n" +
+			"It supports RSS, Atom and JSON Feed. Trafilatura supports simple feed\n" +
 			"finder, so you can point the url into an ordinary web page then\n" +
-			"Trafilatura will attempt to find the feed.",
+			"Trafilatura will attempt to find the feed. Title, author and date from\n" +
+			"the feed are used when they are missing in the page.\n\n" +
+			"Use --watch to keep polling the feed and only process the new entries.\n" +
+			"Use --state to remember the processed entries between runs.",
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			newFeedCmdHandler(cmd).run(args)
@@ -75,6 +81,10 @@ func feedCmd() *cobra.Command {
 	flags.StringArray("domains", nil, "list of allowed domains")
 	flags.StringArray("no-domains", nil, "list of excluded domains")
 	flags.Bool("url-only", false, "only print page urls without downloading or processing them")
+	flags.Bool("feed-content", false, "extract the content that embedded in feed entry instead of downloading the page")
+	flags.Int("min-feed-content", 1000, "min length of embedded content to be used, otherwise the page is downloaded (default 1000)")
+	flags.Duration("watch", 0, "keep polling the feed with the specified interval, e.g. 15m (default disabled)")
+	flags.String("state", "", "path to file for saving the seen entries and feed cache headers between runs")
 
 	return cmd
 }
@@ -85,6 +95,16 @@ type feedCmdHandler struct {
 	pagesDownloader *batchDownloader
 	filterFunc      func(url *nurl.URL) bool
 	urlOnly         bool
+	watchInterval   time.Duration
+	stateFile       string
+
+	useFeedContent bool
+	minFeedContent int
+}
+
+type feedEntry struct {
+	feed.Entry
+	url *nurl.URL
 }
 
 func newFeedCmdHandler(cmd *cobra.Command) *feedCmdHandler {
@@ -98,7 +118,12 @@ func newFeedCmdHandler(cmd *cobra.Command) *feedCmdHandler {
 	excludedDomains, _ := flags.GetStringArray("no-domains")
 	outputDir, _ := flags.GetString("output")
 	urlOnly, _ := flags.GetBool("url-only")
+	watchInterval, _ := flags.GetDuration("watch")
+	stateFile, _ := flags.GetString("state")
+	useFeedContent, _ := flags.GetBool("feed-content")
+	minFeedContent, _ := flags.GetInt("min-feed-content")
 	userAgent, _ := cmd.Flags().GetString("user-agent")
+	maxPages, _ := cmd.Flags().GetInt("max-pages")
 
 	// Prepare http client
 	httpClient := createHttpClient(cmd)
@@ -162,6 +187,7 @@ func newFeedCmdHandler(cmd *cobra.Command) *feedCmdHandler {
 		delay:          time.Duration(delay) * time.Second,
 		cancelOnError:  false,
 		writeFunc:      fnWrite,
+		maxPages:       maxPages,
 	}
 
 	// Make sure output dir exist
@@ -174,163 +200,274 @@ func newFeedCmdHandler(cmd *cobra.Command) *feedCmdHandler {
 		pagesDownloader: pagesDownloader,
 		filterFunc:      fnFilter,
 		urlOnly:         urlOnly,
+		watchInterval:   watchInterval,
+		stateFile:       stateFile,
+		useFeedContent:  useFeedContent,
+		minFeedContent:  minFeedContent,
 	}
 }
 
 func (fch *feedCmdHandler) run(args []string) {
-	// Find feed page
-	feedPage, err := fch.findFeedPage(args[0])
+	// Load state of the previous runs
+	state, err := loadFeedState(fch.stateFile)
 	if err != nil {
-		log.Fatal().Msgf("failed to find feed: %v", err)
+		log.Fatal().Msgf("failed to load state: %v", err)
 	}
+	state.useSource(args[0])
+
+	for {
+		// Process the new entries in feed
+		err := fch.poll(args[0], state)
+		if err != nil {
+			if fch.watchInterval <= 0 {
+				log.Fatal().Msgf("%v", err)
+			}
+			log.Warn().Msgf("%v", err)
+		}
 
-	// Parse feed page
-	pageURLs, err := fch.parseFeedPage(feedPage)
+		// Save the state for the next cycle
+		if fch.stateFile != "" {
+			if err := state.save(fch.stateFile); err != nil {
+				log.Warn().Msgf("failed to save state: %v", err)
+			}
+		}
+
+		// If not watching, we are done
+		if fch.watchInterval <= 0 {
+			return
+		}
+
+		log.Info().Msgf("waiting %s before checking the feed again", fch.watchInterval)
+		time.Sleep(fch.watchInterval)
+	}
+}
+
+func (fch *feedCmdHandler) poll(baseURL string, state *feedState) error {
+	// Fetch and parse feed
+	parsedFeed, validators, err := fch.fetchFeed(baseURL, state)
 	if err != nil {
-		log.Fatal().Msgf("failed to parse feed: %v", err)
+		return fmt.Errorf("failed to fetch feed: %v", err)
+	}
+
+	if parsedFeed == nil {
+		log.Info().Msgf("feed is not modified")
+		return nil
 	}
-	log.Info().Msgf("found %d page URLs", len(pageURLs))
+
+	// Find the new entries
+	newEntries := fch.selectEntries(parsedFeed, state)
+	entries := newEntries
+	log.Info().Msgf("found %d new entries from %d entries", len(entries), len(parsedFeed.Entries))
 
 	// If user only want to print URLs, stop
 	if fch.urlOnly {
-		for _, url := range pageURLs {
-			fmt.Println(url.String())
+		for _, entry := range entries {
+			fmt.Println(entry.url.String())
 		}
-		return
+		state.markSeen(parsedFeed, entries, validators)
+		return nil
+	}
+
+	// If allowed, extract the content that embedded in feed. The entries whose
+	// content is missing or too short will be downloaded instead.
+	if fch.useFeedContent {
+		entries = fch.processFeedContents(parsedFeed, entries)
+	}
+
+	// Download and process pages concurrently, using the feed data as metadata hints
+	pageURLs := make([]*nurl.URL, len(entries))
+	for i, entry := range entries {
+		pageURLs[i] = entry.url
+	}
+
+	fch.pagesDownloader.prepareOptions = func(opts *trafilatura.Options, _ *nurl.URL, idx int) {
+		opts.MetadataHints = metadataHintsFromFeed(parsedFeed, entries[idx].Entry)
 	}
 
-	// Download and process pages concurrently
 	err = fch.pagesDownloader.downloadURLs(context.Background(), pageURLs)
 	if err != nil {
-		log.Fatal().Msgf("download pages failed: %v", err)
+		return fmt.Errorf("download pages failed: %v", err)
 	}
-}
 
-func (fch *feedCmdHandler) findFeedPage(baseURL string) (io.Reader, error) {
-	// Make sure URL valid
-	parsedBaseURL, valid := validateURL(baseURL)
-	if !valid {
-		return nil, fmt.Errorf("url is not valid")
-	}
+	state.markSeen(parsedFeed, newEntries, validators)
+	return nil
+}
 
-	// Prepare buffer for containing result
-	buffer := bytes.NewBuffer(nil)
-	var feedURL string
+// processFeedContents extracts the HTML content that embedded in the feed entries,
+// which saves bandwidth and avoids the page chrome (e.g. paywall) of the original
+// page. Returns the entries that still need to be downloaded.
+func (fch *feedCmdHandler) processFeedContents(parsedFeed *feed.Feed, entries []feedEntry) []feedEntry {
+	var remaining []feedEntry
+	var nProcessed int
 
-	// Process the URL
-	err := func() error {
-		// Downloading base URL
-		log.Info().Msgf("downloading %q", baseURL)
-		resp, err := download(fch.httpClient, fch.userAgent, baseURL)
+	for _, entry := range entries {
+		result, err := fch.extractFeedContent(parsedFeed, entry)
 		if err != nil {
-			return err
+			log.Info().Msgf("feed content for %s is not usable: %v", entry.url, err)
+			remaining = append(remaining, entry)
+			continue
 		}
-		defer resp.Body.Close()
 
-		// If it's XML, we got the feed so return it
-		contentType := resp.Header.Get("Content-Type")
-		if fch.contentIsFeed(contentType) {
-			_, err = io.Copy(buffer, resp.Body)
-			feedURL = baseURL
-			return err
+		err = fch.pagesDownloader.writeFunc(result, entry.url, nProcessed)
+		if err != nil {
+			log.Warn().Msgf("failed to write %s: %v", entry.url, err)
 		}
+		nProcessed++
+	}
 
-		// If it's HTML, look for feed URL in document
-		if !strings.Contains(contentType, "text/html") {
-			return fmt.Errorf("page is not html: \"%s\"", contentType)
-		}
+	log.Info().Msgf("extracted %d entries from feed content, %d need to be downloaded", nProcessed, len(remaining))
+	return remaining
+}
 
-		feedURL, err = fch.findFeedUrlInHtml(resp.Body, parsedBaseURL)
-		return err
-	}()
+func (fch *feedCmdHandler) extractFeedContent(parsedFeed *feed.Feed, entry feedEntry) (*trafilatura.ExtractResult, error) {
+	if entry.Content == "" {
+		return nil, fmt.Errorf("no content")
+	}
 
+	doc, err := html.Parse(strings.NewReader(entry.Content))
 	if err != nil {
-		return nil, fmt.Errorf("failed looking for feed: %v", err)
+		return nil, err
 	}
 
-	// If feed URL not found, give up
-	if feedURL == "" {
-		return nil, fmt.Errorf("feed not found")
+	// Make sure the content is long enough to be the full article, not only summary
+	text := strings.TrimSpace(dom.TextContent(doc))
+	if length := utf8.RuneCountInString(text); length < fch.minFeedContent {
+		return nil, fmt.Errorf("content too short (%d chars)", length)
 	}
 
-	// If feed URL found and buffer not empty, we are finished
-	if feedURL != "" && buffer.Len() != 0 {
-		return buffer, nil
+	opts := fch.pagesDownloader.extractOptions
+	opts.OriginalURL = entry.url
+	opts.MetadataHints = metadataHintsFromFeed(parsedFeed, entry.Entry)
+
+	result, err := trafilatura.ExtractDocument(doc, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// At this point, buffer is empty but feed URL is found, so download it.
-	err = func() error {
-		// Downloading feed URL
-		log.Info().Msgf("downloading feed %q", feedURL)
-		resp, err := download(fch.httpClient, fch.userAgent, feedURL)
-		if err != nil {
-			return err
-		}
-		defer resp.Body.Close()
+	if result.ContentText == "" {
+		return nil, fmt.Errorf("no content extracted")
+	}
 
-		// Fail if it's not XML
-		contentType := resp.Header.Get("Content-Type")
-		if !fch.contentIsFeed(contentType) {
-			return fmt.Errorf("page is not feed: \"%s\"", contentType)
-		}
+	return result, nil
+}
 
-		_, err = io.Copy(buffer, resp.Body)
-		return err
-	}()
+// fetchFeed downloads and parses the feed. If the URL is an ordinary web page, it
+// will look for the feed URL inside it. Returns nil if the feed hasn't changed
+// since the previous fetch. The validators of the fetched feed are returned instead
+// of saved into state, so they are only saved once its entries are processed.
+func (fch *feedCmdHandler) fetchFeed(baseURL string, state *feedState) (*feed.Feed, feedValidators, error) {
+	// Make sure URL valid
+	parsedBaseURL, valid := validateURL(baseURL)
+	if !valid {
+		return nil, feedValidators{}, fmt.Errorf("url is not valid")
+	}
 
+	// If the feed URL is known from the previous fetch, use it directly
+	if state.FeedURL != "" {
+		return fch.downloadFeed(state.FeedURL, state)
+	}
+
+	// Downloading base URL
+	log.Info().Msgf("downloading %q", baseURL)
+	resp, err := download(fch.httpClient, fch.userAgent, baseURL)
 	if err != nil {
-		return nil, fmt.Errorf("download feed failed: %v", err)
+		return nil, feedValidators{}, err
 	}
+	defer resp.Body.Close()
 
-	if buffer.Len() == 0 {
-		return nil, fmt.Errorf("feed not found")
+	// If it's feed, parse it
+	contentType := resp.Header.Get("Content-Type")
+	if fch.responseIsFeed(contentType) {
+		return fch.parseFeed(resp, baseURL)
 	}
 
-	return buffer, nil
-}
+	// If it's HTML, look for feed URL in document
+	if !strings.Contains(contentType, "text/html") {
+		return nil, feedValidators{}, fmt.Errorf("page is not html: \"%s\"", contentType)
+	}
 
-func (fch *feedCmdHandler) parseFeedPage(r io.Reader) ([]*nurl.URL, error) {
-	var pageURLs []*nurl.URL
+	feedURL, err := fch.findFeedUrlInHtml(resp.Body, parsedBaseURL)
+	if err != nil {
+		return nil, feedValidators{}, err
+	}
 
-	// Parse doc
-	doc := betree.NewDocument()
-	_, err := doc.ReadFrom(r)
+	if feedURL == "" {
+		return nil, feedValidators{}, fmt.Errorf("feed not found")
+	}
+
+	return fch.downloadFeed(feedURL, state)
+}
+
+// downloadFeed downloads the feed from the URL. If it has been downloaded before,
+// the request will be conditional using the saved ETag and Last-Modified.
+func (fch *feedCmdHandler) downloadFeed(feedURL string, state *feedState) (*feed.Feed, feedValidators, error) {
+	req, err := http.NewRequest("GET", feedURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, feedValidators{}, err
 	}
 
-	// Get feed items (for RSS)
-	for _, item := range doc.FindElements("//item") {
-		for _, link := range item.FindElements("./link") {
-			parsedURL, valid := validateURL(link.Text())
-			if valid {
-				pageURLs = append(pageURLs, parsedURL)
-				break
-			}
+	req.Header.Set("User-Agent", fch.userAgent)
+	if state.FeedURL == feedURL {
+		if state.ETag != "" {
+			req.Header.Set("If-None-Match", state.ETag)
+		}
+		if state.LastModified != "" {
+			req.Header.Set("If-Modified-Since", state.LastModified)
 		}
 	}
 
-	// Get feed entries (for Atom)
-	for _, entry := range doc.FindElements("//entry") {
-		for _, link := range entry.FindElements("./link") {
-			href := link.SelectAttrValue("href", "")
-			if href == "" {
-				continue
-			}
+	log.Info().Msgf("downloading feed %q", feedURL)
+	resp, err := fch.httpClient.Do(req)
+	if err != nil {
+		return nil, feedValidators{}, err
+	}
+	defer resp.Body.Close()
 
-			parsedURL, valid := validateURL(href)
-			if valid {
-				pageURLs = append(pageURLs, parsedURL)
-				break
-			}
-		}
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, feedValidators{}, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, feedValidators{}, fmt.Errorf("feed returns status %q", resp.Status)
+	}
+
+	// Fail if it's not feed
+	contentType := resp.Header.Get("Content-Type")
+	if !fch.responseIsFeed(contentType) {
+		return nil, feedValidators{}, fmt.Errorf("page is not feed: \"%s\"", contentType)
+	}
+
+	return fch.parseFeed(resp, feedURL)
+}
+
+// parseFeed parses the feed in response body and returns it along with its caching
+// headers, so the next fetch can be conditional.
+func (fch *feedCmdHandler) parseFeed(resp *http.Response, feedURL string) (*feed.Feed, feedValidators, error) {
+	parsedFeed, err := feed.Parse(resp.Body)
+	if err != nil {
+		return nil, feedValidators{}, err
 	}
 
-	// Make sure page URLs are unique
-	uniquePageURLs := []*nurl.URL{}
+	validators := feedValidators{
+		FeedURL:      feedURL,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	return parsedFeed, validators, nil
+}
+
+// selectEntries returns the entries with valid URL which allowed by filter and
+// haven't been processed in the previous cycles.
+func (fch *feedCmdHandler) selectEntries(parsedFeed *feed.Feed, state *feedState) []feedEntry {
+	var entries []feedEntry
 	uniqueTracker := make(map[string]struct{})
 
-	for _, url := range pageURLs {
+	for _, entry := range parsedFeed.Entries {
+		url, valid := validateURL(entry.Link)
+		if !valid {
+			continue
+		}
+
 		if fch.filterFunc != nil && !fch.filterFunc(url) {
 			continue
 		}
@@ -339,14 +476,43 @@ func (fch *feedCmdHandler) parseFeedPage(r io.Reader) ([]*nurl.URL, error) {
 		if _, exist := uniqueTracker[strURL]; exist {
 			continue
 		}
-
 		uniqueTracker[strURL] = struct{}{}
-		uniquePageURLs = append(uniquePageURLs, url)
+
+		if state.hasSeen(entry) {
+			continue
+		}
+
+		entries = append(entries, feedEntry{Entry: entry, url: url})
 	}
 
-	return uniquePageURLs, nil
+	return entries
 }
 
+// metadataHintsFromFeed converts data of feed entry into metadata, which will be
+// used when the page itself doesn't have it.
+func metadataHintsFromFeed(parsedFeed *feed.Feed, entry feed.Entry) *trafilatura.Metadata {
+	hints := &trafilatura.Metadata{
+		Title:      entry.Title,
+		Author:     strings.Join(entry.Authors, "; "),
+		Date:       entry.Date(),
+		Sitename:   parsedFeed.Title,
+		Language:   parsedFeed.Language,
+		Categories: entry.Categories,
+	}
+
+	if hints.Author == "" {
+		hints.Author = strings.Join(parsedFeed.Authors, "; ")
+	}
+
+	for _, enclosure := range entry.Enclosures {
+		if strings.HasPrefix(enclosure.Type, "image/") {
+			hints.Image = enclosure.URL
+			break
+		}
+	}
+
+	return hints
+}
 func (fch *feedCmdHandler) findFeedUrlInHtml(r io.Reader, baseURL *nurl.URL) (string, error) {
 	// Parse document
 	doc, err := html.Parse(r)
@@ -376,6 +542,19 @@ func (fch *feedCmdHandler) findFeedUrlInHtml(r io.Reader, baseURL *nurl.URL) (st
 }
 
 func (fch *feedCmdHandler) contentIsFeed(contentType string) bool {
-	_, exist := feedTypes[contentType]
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	_, exist := feedTypes[mediaType]
 	return exist
 }
+
+// responseIsFeed is like contentIsFeed, but also accepts generic JSON since JSON Feed
+// is commonly served that way. It's not used for feed finder because in HTML page
+// generic JSON link is usually used for API instead of feed.
+func (fch *feedCmdHandler) responseIsFeed(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json" || fch.contentIsFeed(contentType)
+}
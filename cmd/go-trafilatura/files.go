@@ -0,0 +1,332 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	fp "path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/markusmobius/go-trafilatura"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+var defaultIncludePatterns = []string{"*.html", "*.htm", "*.html.gz", "*.htm.gz"}
+
+type inputFile struct {
+	path    string
+	relPath string
+}
+
+type filesProcessor struct {
+	extractOptions trafilatura.Options
+	nThread        int
+	outputDir      string
+	outputExt      string
+	splitArticles  bool
+	writeFunc      func(*trafilatura.ExtractResult, string) error
+
+	// usedPaths is the lowercased output paths that already planned, so the numbered
+	// output of split articles doesn't overwrite output of the other files.
+	usedPaths map[string]struct{}
+	pathMutex sync.Mutex
+}
+
+func processFiles(cmd *cobra.Command, sources []string) {
+	// Parse flags
+	flags := cmd.Flags()
+	nThread, _ := flags.GetInt("parallel")
+	outputDir, _ := flags.GetString("output")
+	includePatterns, _ := flags.GetStringArray("include")
+	splitArticles, _ := flags.GetBool("split-articles")
+
+	// Only local files are processed here, so reject the sources and flags that
+	// can't be handled along with them.
+	for _, source := range sources {
+		switch {
+		case source == "-":
+			log.Fatal().Msgf("stdin can't be combined with other sources")
+		case !fileExists(source) && isValidURL(source):
+			log.Fatal().Msgf("url %q can't be combined with files, use batch command to process several urls", source)
+		}
+	}
+
+	if flags.Changed("max-pages") {
+		log.Fatal().Msgf("--max-pages is only supported for url source")
+	}
+
+	// Collect input files
+	files, err := collectInputFiles(sources, includePatterns)
+	if err != nil {
+		log.Fatal().Msgf("failed to collect input: %v", err)
+	}
+
+	if len(files) == 0 {
+		log.Fatal().Msgf("no input file found")
+	}
+	log.Info().Msgf("found %d input files", len(files))
+
+	// Process files concurrently
+	fnWrite := func(result *trafilatura.ExtractResult, dstPath string) error {
+		err := os.MkdirAll(fp.Dir(dstPath), os.ModePerm)
+		if err != nil {
+			return err
+		}
+
+		dst, err := os.Create(dstPath)
+		if err != nil {
+			return err
+		}
+		defer dst.Close()
+
+		return writeOutput(dst, result, cmd)
+	}
+
+	err = (&filesProcessor{
+		extractOptions: createExtractorOptions(cmd),
+		nThread:        nThread,
+		outputDir:      outputDir,
+		outputExt:      outputExt(cmd),
+		splitArticles:  splitArticles,
+		writeFunc:      fnWrite,
+	}).processFiles(context.Background(), files)
+
+	if err != nil {
+		log.Fatal().Msgf("process failed: %v", err)
+	}
+}
+
+func (fsp *filesProcessor) processFiles(ctx context.Context, files []inputFile) error {
+	// Reserve the output path of each file
+	fsp.usedPaths = make(map[string]struct{}, len(files))
+	for _, file := range files {
+		dstPath := outputPath(fsp.outputDir, file.relPath, fsp.outputExt)
+		fsp.usedPaths[strings.ToLower(dstPath)] = struct{}{}
+	}
+
+	// Limit the number of concurrent extraction
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(fsp.nThread, 1))
+
+	for _, file := range files {
+		if ctx.Err() != nil {
+			break
+		}
+
+		g.Go(func() error {
+			// Process file
+			var err error
+			var results []*trafilatura.ExtractResult
+			if fsp.splitArticles {
+				results, err = processFileArticles(file.path, fsp.extractOptions)
+			} else {
+				results, err = singleResult(processFile(file.path, fsp.extractOptions))
+			}
+
+			if err != nil {
+				log.Warn().Msgf("failed to process %s: %v", file.path, err)
+				return nil
+			}
+
+			// Write to the mirrored path in output dir. If the page is split into
+			// several articles, each of them is numbered.
+			dstPath := outputPath(fsp.outputDir, file.relPath, fsp.outputExt)
+			number := 0
+			for _, result := range results {
+				articlePath := dstPath
+				if len(results) > 1 {
+					articlePath, number = fsp.reserveArticlePath(dstPath, number+1)
+				}
+
+				err = fsp.writeFunc(result, articlePath)
+				if err != nil {
+					log.Warn().Msgf("failed to write %s: %v", articlePath, err)
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// reserveArticlePath returns the numbered output path for an article, starting from
+// the specified number. Number that used by the output of other files is skipped, so
+// e.g. the second article of "news" doesn't overwrite the output of "news-2".
+func (fsp *filesProcessor) reserveArticlePath(dstPath string, number int) (string, int) {
+	fsp.pathMutex.Lock()
+	defer fsp.pathMutex.Unlock()
+
+	for ; ; number++ {
+		articlePath := articleOutputPath(dstPath, number)
+		key := strings.ToLower(articlePath)
+		if _, used := fsp.usedPaths[key]; !used {
+			fsp.usedPaths[key] = struct{}{}
+			return articlePath, number
+		}
+	}
+}
+
+func collectInputFiles(sources []string, includePatterns []string) ([]inputFile, error) {
+	var files []inputFile
+	tracker := make(map[string]struct{})
+	usedRelPaths := make(map[string]struct{})
+
+	addFile := func(path, relPath string) {
+		if _, exist := tracker[path]; exist {
+			return
+		}
+		tracker[path] = struct{}{}
+
+		// Files from different sources might share the same relative path, e.g.
+		// "a/index.html" and "b/index.html", so number them to prevent overwrite.
+		uniqueRelPath := relPath
+		for i := 2; ; i++ {
+			key := strings.ToLower(outputPath("", uniqueRelPath, ""))
+			if _, used := usedRelPaths[key]; !used {
+				usedRelPaths[key] = struct{}{}
+				break
+			}
+			uniqueRelPath = numberedPath(relPath, i)
+		}
+
+		if uniqueRelPath != relPath {
+			log.Warn().Msgf("output for %s is renamed into %s to avoid conflict", path, uniqueRelPath)
+		}
+
+		files = append(files, inputFile{path: path, relPath: uniqueRelPath})
+	}
+
+	for _, source := range sources {
+		switch {
+		case dirExists(source):
+			// Walk the directory recursively
+			err := fp.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+
+				if d.IsDir() || !matchAnyPattern(d.Name(), includePatterns) {
+					return nil
+				}
+
+				relPath, err := fp.Rel(source, path)
+				if err != nil {
+					return err
+				}
+
+				addFile(path, relPath)
+				return nil
+			})
+
+			if err != nil {
+				return nil, err
+			}
+
+		case fileExists(source):
+			addFile(source, fp.Base(source))
+
+		case isGlobPattern(source):
+			matches, err := fp.Glob(source)
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob pattern %q: %v", source, err)
+			}
+
+			baseDir := globBaseDir(source)
+			for _, match := range matches {
+				if dirExists(match) {
+					continue
+				}
+
+				relPath, err := fp.Rel(baseDir, match)
+				if err != nil {
+					relPath = fp.Base(match)
+				}
+
+				addFile(match, relPath)
+			}
+
+		default:
+			return nil, fmt.Errorf("%s is not a valid file, directory, glob pattern or url", source)
+		}
+	}
+
+	return files, nil
+}
+
+func matchAnyPattern(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if matched, _ := fp.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isGlobPattern(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// globBaseDir returns the deepest directory of the pattern which doesn't
+// contain any glob meta characters. It's used as root for mirroring the
+// matched files into output directory.
+func globBaseDir(pattern string) string {
+	dir := fp.Dir(pattern)
+	for isGlobPattern(dir) {
+		dir = fp.Dir(dir)
+	}
+	return dir
+}
+
+func outputPath(outputDir string, relPath string, ext string) string {
+	relPath = strings.TrimSuffix(relPath, fp.Ext(relPath))
+	if strings.EqualFold(fp.Ext(relPath), ".html") || strings.EqualFold(fp.Ext(relPath), ".htm") {
+		relPath = strings.TrimSuffix(relPath, fp.Ext(relPath))
+	}
+
+	return fp.Join(outputDir, relPath+ext)
+}
+
+// articleOutputPath adds the article number into the output path, e.g.
+// "news.txt" into "news-2.txt".
+func articleOutputPath(dstPath string, number int) string {
+	ext := fp.Ext(dstPath)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(dstPath, ext), number, ext)
+}
+
+// numberedPath adds the number into the input path before its extensions, which
+// stripped the same way as in `outputPath`, e.g. "news.html.gz" into "news-2.html.gz".
+func numberedPath(path string, number int) string {
+	base := strings.TrimSuffix(path, fp.Ext(path))
+	if strings.EqualFold(fp.Ext(base), ".html") || strings.EqualFold(fp.Ext(base), ".htm") {
+		base = strings.TrimSuffix(base, fp.Ext(base))
+	}
+	return fmt.Sprintf("%s-%d%s", base, number, path[len(base):])
+}
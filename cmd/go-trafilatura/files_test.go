@@ -0,0 +1,99 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	fp "path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CollectInputFiles_BasenameCollision(t *testing.T) {
+	// Prepare files that share the same base name in different directories
+	root := t.TempDir()
+	paths := []string{
+		fp.Join(root, "a", "index.html"),
+		fp.Join(root, "b", "index.html"),
+		fp.Join(root, "c", "index.htm"),
+		fp.Join(root, "d", "index.html.gz"),
+	}
+
+	for _, path := range paths {
+		assert.NoError(t, os.MkdirAll(fp.Dir(path), os.ModePerm))
+		assert.NoError(t, os.WriteFile(path, []byte("<html></html>"), 0644))
+	}
+
+	files, err := collectInputFiles(paths, defaultIncludePatterns)
+	assert.NoError(t, err)
+	assert.Len(t, files, len(paths))
+
+	// Each file has its own output path
+	outputs := make(map[string]string)
+	for _, file := range files {
+		dstPath := outputPath("out", file.relPath, ".txt")
+		assert.NotContains(t, outputs, dstPath, "%s overwrites %s", file.path, outputs[dstPath])
+		outputs[dstPath] = file.path
+	}
+
+	assert.Equal(t, map[string]string{
+		fp.Join("out", "index.txt"):   paths[0],
+		fp.Join("out", "index-2.txt"): paths[1],
+		fp.Join("out", "index-3.txt"): paths[2],
+		fp.Join("out", "index-4.txt"): paths[3],
+	}, outputs)
+
+	// Directories with the same structure are numbered as well
+	files, err = collectInputFiles([]string{fp.Join(root, "a"), fp.Join(root, "b")}, defaultIncludePatterns)
+	assert.NoError(t, err)
+	assert.Equal(t, []inputFile{
+		{path: paths[0], relPath: "index.html"},
+		{path: paths[1], relPath: "index-2.html"},
+	}, files)
+}
+
+func Test_NumberedPath(t *testing.T) {
+	assert.Equal(t, "news-2.html", numberedPath("news.html", 2))
+	assert.Equal(t, "news-2.html.gz", numberedPath("news.html.gz", 2))
+	assert.Equal(t, fp.Join("dir", "news-3.txt"), numberedPath(fp.Join("dir", "news.txt"), 3))
+	assert.Equal(t, "news-2", numberedPath("news", 2))
+}
+
+func Test_ReserveArticlePath(t *testing.T) {
+	// Output of a real input file named "news-2" is already planned
+	fsp := &filesProcessor{usedPaths: map[string]struct{}{
+		strings.ToLower(fp.Join("out", "news.txt")):   {},
+		strings.ToLower(fp.Join("out", "News-2.txt")): {},
+	}}
+
+	dstPath := fp.Join("out", "news.txt")
+	path, number := fsp.reserveArticlePath(dstPath, 1)
+	assert.Equal(t, fp.Join("out", "news-1.txt"), path)
+	assert.Equal(t, 1, number)
+
+	path, number = fsp.reserveArticlePath(dstPath, number+1)
+	assert.Equal(t, fp.Join("out", "news-3.txt"), path)
+	assert.Equal(t, 3, number)
+
+	// Reserved path is not given twice
+	path, _ = fsp.reserveArticlePath(dstPath, 1)
+	assert.Equal(t, fp.Join("out", "news-4.txt"), path)
+}
@@ -0,0 +1,171 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/markusmobius/go-trafilatura"
+	"github.com/spf13/cobra"
+)
+
+func learnCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "learn [flags] [source...]",
+		Short: "Learn boilerplate profile from several pages of the same site",
+		Long: "Learn the boilerplate of a website from several of its pages. The source can be url,\n" +
+			"HTML file, directory or glob pattern. Text blocks and link-heavy sections that recur\n" +
+			"across the pages are saved as JSON profile, which can be used with --site-profile to\n" +
+			"remove them when extracting other pages of the same site.",
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			learnCmdHandler(cmd, args)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringP("output", "o", "", "path to save the profile (default stdout)")
+	flags.String("host", "", "host name of the site, taken from the first url if not specified")
+	flags.Float64("min-ratio", 0.6, "minimum ratio of pages that must contain a block to be boilerplate")
+	flags.StringArray("include", defaultIncludePatterns, "file name patterns to process when walking directory")
+
+	return cmd
+}
+
+func learnCmdHandler(cmd *cobra.Command, args []string) {
+	// Parse flags
+	flags := cmd.Flags()
+	outputPath, _ := flags.GetString("output")
+	host, _ := flags.GetString("host")
+	minRatio, _ := flags.GetFloat64("min-ratio")
+	includePatterns, _ := flags.GetStringArray("include")
+	userAgent, _ := flags.GetString("user-agent")
+
+	// Separate the urls from local files
+	var urls, fileSources []string
+	for _, source := range args {
+		if url, valid := validateURL(source); valid {
+			urls = append(urls, source)
+			if host == "" {
+				host = url.Hostname()
+			}
+		} else {
+			fileSources = append(fileSources, source)
+		}
+	}
+
+	var files []inputFile
+	if len(fileSources) > 0 {
+		var err error
+		files, err = collectInputFiles(fileSources, includePatterns)
+		if err != nil {
+			log.Fatal().Msgf("failed to collect input: %v", err)
+		}
+	}
+
+	// Learn each page
+	learner := trafilatura.NewSiteLearner(host)
+	learner.MinRatio = minRatio
+
+	httpClient := createHttpClient(cmd)
+	for _, url := range urls {
+		resp, err := download(httpClient, userAgent, url)
+		if err != nil {
+			log.Warn().Msgf("failed to download %s: %v", url, err)
+			continue
+		}
+
+		// Error pages don't share the boilerplate of the site, so skip them
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			log.Warn().Msgf("failed to download %s: status %d", url, resp.StatusCode)
+			resp.Body.Close()
+			continue
+		}
+
+		err = learnPage(learner, resp.Body, resp.Header.Get("Content-Type"))
+		resp.Body.Close()
+		if err != nil {
+			log.Warn().Msgf("failed to learn %s: %v", url, err)
+		}
+	}
+
+	for _, file := range files {
+		f, err := os.Open(file.path)
+		if err != nil {
+			log.Warn().Msgf("failed to open %s: %v", file.path, err)
+			continue
+		}
+
+		err = learnPage(learner, f, "")
+		f.Close()
+		if err != nil {
+			log.Warn().Msgf("failed to learn %s: %v", file.path, err)
+		}
+	}
+
+	// Save the profile
+	profile := learner.Profile()
+	if profile.Pages < 2 {
+		log.Fatal().Msgf("at least two pages are required, only %d learned", profile.Pages)
+	}
+
+	output := os.Stdout
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			log.Fatal().Msgf("failed to create profile: %v", err)
+		}
+		defer f.Close()
+		output = f
+	}
+
+	if err := profile.Save(output); err != nil {
+		log.Fatal().Msgf("failed to save profile: %v", err)
+	}
+
+	log.Info().Msgf("learned %d paths and %d texts from %d pages",
+		len(profile.Paths), len(profile.Texts), profile.Pages)
+}
+
+func learnPage(learner *trafilatura.SiteLearner, r io.Reader, contentType string) error {
+	r, err := decompressReader(r)
+	if err != nil {
+		return err
+	}
+
+	doc, _, err := trafilatura.ParseHTML(r, trafilatura.Options{ContentType: contentType})
+	if err != nil {
+		return fmt.Errorf("not a valid html: %v", err)
+	}
+
+	learner.Add(doc)
+	return nil
+}
+
+func loadSiteProfile(path string) (*trafilatura.SiteProfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return trafilatura.LoadSiteProfile(f)
+}
@@ -19,7 +19,9 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"crypto/tls"
 	"fmt"
 	"io"
@@ -52,33 +54,56 @@ const defaultUserAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:88.0) Gecko/2010010
 func main() {
 	// Create root command
 	rootCmd := &cobra.Command{
-		Use:   "go-trafilatura [flags] [source]",
+		Use:   "go-trafilatura [flags] [source...]",
 		Run:   rootCmdHandler,
 		Short: "extract readable content from a HTML file or url",
-		Long: "Extract readable content from a specified source which can be either a HTML file or url.\n" +
-			"It also has supports for batch download url either from a file which contains list of url,\n" +
-			"RSS feeds and sitemap.",
-		Args: cobra.ExactArgs(1),
+		Long: "Extract readable content from a specified source which can be either a HTML file, url,\n" +
+			"directory, glob pattern or \"-\" to read HTML from stdin. Directory is processed recursively\n" +
+			"and the result is saved into output directory, mirroring the source tree. Compressed\n" +
+			"\".gz\" files are decompressed automatically. It also has supports for batch download url\n" +
+			"either from a file which contains list of url, RSS feeds and sitemap.",
+		Args: cobra.MinimumNArgs(1),
 	}
 
+	// Register local flags
+	localFlags := rootCmd.Flags()
+	localFlags.String("url", "", "original url of the document when reading from stdin or a single file")
+	localFlags.StringP("output", "o", ".", "output directory when processing directory or glob pattern (default current work dir)")
+	localFlags.StringArray("include", defaultIncludePatterns, "file name patterns to process when walking directory")
+	localFlags.Bool("split-articles", false, "split page with several independent articles, e.g. home page or live page, into separate outputs")
+	localFlags.Int("parallel", 10, "number of concurrent extraction when processing directory or glob pattern (default 10)")
+
 	// Register persistent flags
 	flags := rootCmd.PersistentFlags()
-	flags.StringP("format", "f", "", "output format for the extract result, either 'html' (default), 'txt' or 'json'")
-	flags.StringP("language", "l", "", "target language (ISO 639-1 codes)")
+	flags.StringP("format", "f", "", "output format for the extract result, either 'html' (default), 'txt', 'json', 'bibtex', 'ris' or 'csl-json'")
+	flags.StringSliceP("language", "l", nil, "target languages (ISO 639-1 codes), separated by comma")
+	flags.StringSlice("lang-candidates", nil, "restrict language detection to these languages (ISO 639-1 codes)")
+	flags.Bool("block-languages", false, "detect language of each block and mark it with lang attribute")
+	flags.String("encoding", "", "character encoding of the source, detected automatically if not specified")
 	flags.Bool("no-fallback", false, "disable fallback extraction using readability and dom-distiller")
+	flags.Bool("fallback-hydration", false, "also use article from hydration state of JavaScript framework as fallback")
+	flags.Bool("fallback-justext", false, "also use jusText as fallback extractor after readability and dom-distiller")
 	flags.Bool("no-comments", false, "exclude comments  extraction result")
 	flags.Bool("no-tables", false, "include tables in extraction result")
 	flags.Bool("images", false, "include images in extraction result (experimental)")
 	flags.Bool("links", false, "keep links in extraction result (experimental)")
 	flags.Bool("deduplicate", false, "filter out duplicate segments and sections")
+	flags.Bool("keep-hidden", false, "keep content that hidden from readers, e.g. by display:none or hidden attribute")
+	flags.StringSlice("hidden-classes", nil, "additional CSS classes that used to hide element, separated by comma")
 	flags.Bool("has-metadata", false, "only output documents with title, URL and date")
+	flags.StringSlice("skip-types", nil, "skip pages with specified types: listing, homepage, product, forum, video, gallery or search")
+	flags.StringSlice("reject-pages", nil, "skip pages with specified states: paywalled, consent-wall, login-wall or not-found")
+	flags.StringSlice("reject-rights", nil, "skip pages with specified rights: all-rights-reserved, noai, noimageai or tdm-reserved")
+	flags.StringSlice("licenses", nil, "only output pages with these SPDX licenses, e.g. CC0-1.0 or CC-BY* as prefix")
 	flags.BoolP("verbose", "v", false, "enable log message")
 	flags.IntP("timeout", "t", 30, "timeout for downloading web page in seconds")
 	flags.Bool("skip-tls", false, "skip X.509 (TLS) certificate verification")
 	flags.StringP("user-agent", "u", defaultUserAgent, "set custom user agent")
+	flags.Int("max-pages", 1, "follow next page links of paginated article up to this number of pages")
+	flags.String("site-profile", "", "path to site profile from learn command, used to remove the site boilerplate")
 
 	// Add sub commands
-	rootCmd.AddCommand(batchCmd(), sitemapCmd(), feedCmd())
+	rootCmd.AddCommand(batchCmd(), sitemapCmd(), feedCmd(), evalCmd(), snapshotCmd(), learnCmd())
 
 	// Execute
 	err := rootCmd.Execute()
@@ -88,36 +113,79 @@ func main() {
 }
 
 func rootCmdHandler(cmd *cobra.Command, args []string) {
-	// Process source
+	// Multiple sources, directory and glob pattern are saved into output dir
 	source := args[0]
+	if len(args) > 1 || dirExists(source) || (!fileExists(source) && isGlobPattern(source)) {
+		processFiles(cmd, args)
+		return
+	}
+
+	// Process single source
 	opts := createExtractorOptions(cmd)
 	httpClient := createHttpClient(cmd)
 	userAgent, _ := cmd.Flags().GetString("user-agent")
+	maxPages, _ := cmd.Flags().GetInt("max-pages")
+
+	strOriginalURL, _ := cmd.Flags().GetString("url")
+	if strOriginalURL != "" {
+		parsedURL, valid := validateURL(strOriginalURL)
+		if !valid {
+			log.Fatal().Msgf("url %q is not valid", strOriginalURL)
+		}
+		opts.OriginalURL = parsedURL
+	}
+
+	// Next pages are downloaded, so pagination only works for url
+	if cmd.Flags().Changed("max-pages") && (source == "-" || fileExists(source)) {
+		log.Fatal().Msgf("--max-pages is only supported for url source")
+	}
 
 	var err error
-	var result *trafilatura.ExtractResult
+	var results []*trafilatura.ExtractResult
+	splitArticles, _ := cmd.Flags().GetBool("split-articles")
 
 	switch {
+	case source == "-" && splitArticles:
+		results, err = processReaderArticles(os.Stdin, "", opts)
+	case source == "-":
+		results, err = singleResult(processReader(os.Stdin, "", opts))
+	case fileExists(source) && splitArticles:
+		results, err = processFileArticles(source, opts)
 	case fileExists(source):
-		result, err = processFile(source, opts)
+		results, err = singleResult(processFile(source, opts))
+	case isValidURL(source) && splitArticles:
+		parsedURL, _ := nurl.ParseRequestURI(source)
+		results, err = processURLArticles(httpClient, userAgent, parsedURL, opts)
 	case isValidURL(source):
 		parsedURL, _ := nurl.ParseRequestURI(source)
-		result, err = processURL(httpClient, userAgent, parsedURL, opts)
+		results, err = singleResult(processURL(httpClient, userAgent, parsedURL, opts, maxPages))
+	default:
+		err = fmt.Errorf("source is not a valid file, directory, glob pattern or url")
 	}
 
 	if err != nil {
 		log.Fatal().Msgf("failed to extract %s: %v", source, err)
 	}
 
-	if result == nil {
+	if len(results) == 0 {
 		log.Fatal().Msgf("failed to extract %s: no readable content", source)
 	}
 
-	// Print result
-	err = writeOutput(os.Stdout, result, cmd)
-	if err != nil {
-		log.Fatal().Msgf("failed to write output: %v", err)
+	// Print result. Split articles are printed one after another.
+	for _, result := range results {
+		err = writeOutput(os.Stdout, result, cmd)
+		if err != nil {
+			log.Fatal().Msgf("failed to write output: %v", err)
+		}
+	}
+}
+
+// singleResult wraps the result of single extraction into slice.
+func singleResult(result *trafilatura.ExtractResult, err error) ([]*trafilatura.ExtractResult, error) {
+	if err != nil || result == nil {
+		return nil, err
 	}
+	return []*trafilatura.ExtractResult{result}, nil
 }
 
 func processFile(path string, opts trafilatura.Options) (*trafilatura.ExtractResult, error) {
@@ -128,21 +196,19 @@ func processFile(path string, opts trafilatura.Options) (*trafilatura.ExtractRes
 	}
 	defer f.Close()
 
-	// Make sure it's html
-	var fReader io.Reader
-	mimeType := mime.TypeByExtension(fp.Ext(path))
-	if strings.Contains(mimeType, "text/html") {
-		fReader = f
-	} else {
-		buffer := bytes.NewBuffer(nil)
-		tee := io.TeeReader(f, buffer)
+	// Use the name without ".gz" to guess the mime type
+	if strings.EqualFold(fp.Ext(path), ".gz") {
+		path = strings.TrimSuffix(path, fp.Ext(path))
+	}
 
-		_, err := html.Parse(tee)
-		if err != nil {
-			return nil, fmt.Errorf("%s is not a valid html file: %v", path, err)
-		}
+	return processReader(f, path, opts)
+}
 
-		fReader = buffer
+func processReader(r io.Reader, name string, opts trafilatura.Options) (*trafilatura.ExtractResult, error) {
+	// Make sure it's html
+	fReader, err := htmlReader(r, name)
+	if err != nil {
+		return nil, err
 	}
 
 	// Extract
@@ -154,8 +220,116 @@ func processFile(path string, opts trafilatura.Options) (*trafilatura.ExtractRes
 	return result, nil
 }
 
-func processURL(client *http.Client, userAgent string, url *nurl.URL, opts trafilatura.Options) (*trafilatura.ExtractResult, error) {
+// processFileArticles is like processFile, but the page is split into its
+// independent articles.
+func processFileArticles(path string, opts trafilatura.Options) ([]*trafilatura.ExtractResult, error) {
+	// Open file
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Use the name without ".gz" to guess the mime type
+	if strings.EqualFold(fp.Ext(path), ".gz") {
+		path = strings.TrimSuffix(path, fp.Ext(path))
+	}
+
+	return processReaderArticles(f, path, opts)
+}
+
+// processReaderArticles is like processReader, but the page is split into its
+// independent articles.
+func processReaderArticles(r io.Reader, name string, opts trafilatura.Options) ([]*trafilatura.ExtractResult, error) {
+	fReader, err := htmlReader(r, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return trafilatura.ExtractArticles(fReader, opts)
+}
+
+// htmlReader decompresses the reader if needed, then makes sure its content
+// is a valid HTML document.
+func htmlReader(r io.Reader, name string) (io.Reader, error) {
+	// Decompress the input if it's gzipped
+	r, err := decompressReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType := mime.TypeByExtension(fp.Ext(name))
+	if strings.Contains(mimeType, "text/html") {
+		return r, nil
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	tee := io.TeeReader(r, buffer)
+
+	_, err = html.Parse(tee)
+	if err != nil {
+		return nil, fmt.Errorf("not a valid html file: %v", err)
+	}
+
+	return buffer, nil
+}
+
+// decompressReader checks the gzip magic number in the beginning of reader,
+// and if it exists wrap the reader with gzip reader.
+func decompressReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil || magic[0] != 0x1f || magic[1] != 0x8b {
+		return br, nil
+	}
+
+	return gzip.NewReader(br)
+}
+
+func processURL(client *http.Client, userAgent string, url *nurl.URL, opts trafilatura.Options, maxPages int) (*trafilatura.ExtractResult, error) {
 	// Download URL
+	resp, err := downloadHTML(client, userAgent, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Extract
+	opts.OriginalURL = url
+	opts.ContentType = resp.Header.Get("Content-Type")
+	opts.ResponseHeader = resp.Header
+	if maxPages > 1 {
+		fetcher := func(pageURL string) ([]byte, error) {
+			return fetchPage(client, userAgent, pageURL)
+		}
+		return trafilatura.ExtractPages(resp.Body, opts, fetcher, maxPages)
+	}
+
+	result, err := trafilatura.Extract(resp.Body, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// processURLArticles is like processURL, but the page is split into its
+// independent articles.
+func processURLArticles(client *http.Client, userAgent string, url *nurl.URL, opts trafilatura.Options) ([]*trafilatura.ExtractResult, error) {
+	resp, err := downloadHTML(client, userAgent, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	opts.OriginalURL = url
+	opts.ContentType = resp.Header.Get("Content-Type")
+	opts.ResponseHeader = resp.Header
+	return trafilatura.ExtractArticles(resp.Body, opts)
+}
+
+// downloadHTML downloads the URL and makes sure the response is a HTML page.
+func downloadHTML(client *http.Client, userAgent string, url *nurl.URL) (*http.Response, error) {
 	strURL := url.String()
 	log.Info().Msgf("downloading %q", strURL)
 
@@ -163,22 +337,62 @@ func processURL(client *http.Client, userAgent string, url *nurl.URL, opts trafi
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	// Make sure it's html
 	contentType := resp.Header.Get("Content-Type")
 	if !strings.Contains(contentType, "text/html") {
+		resp.Body.Close()
 		return nil, fmt.Errorf("page is not html: \"%s\"", contentType)
 	}
 
-	// Extract
-	opts.OriginalURL = url
-	result, err := trafilatura.Extract(resp.Body, opts)
+	return resp, nil
+}
+
+// fetchPage downloads the subsequent page of paginated article.
+func fetchPage(client *http.Client, userAgent string, url string) ([]byte, error) {
+	log.Info().Msgf("downloading next page %q", url)
+
+	resp, err := download(client, userAgent, url)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	return result, nil
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed with status %d", resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.Contains(contentType, "text/html") {
+		return nil, fmt.Errorf("page is not html: \"%s\"", contentType)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+var pageStates = map[string]trafilatura.PageState{
+	trafilatura.PagePaywalled.String():   trafilatura.PagePaywalled,
+	trafilatura.PageConsentWall.String(): trafilatura.PageConsentWall,
+	trafilatura.PageLoginWall.String():   trafilatura.PageLoginWall,
+	trafilatura.PageNotFound.String():    trafilatura.PageNotFound,
+}
+
+var rightsRestrictions = map[string]trafilatura.RightsRestriction{
+	trafilatura.AllRightsReserved.String(): trafilatura.AllRightsReserved,
+	trafilatura.NoAI.String():              trafilatura.NoAI,
+	trafilatura.NoImageAI.String():         trafilatura.NoImageAI,
+	trafilatura.TDMReserved.String():       trafilatura.TDMReserved,
+}
+
+var pageTypes = map[string]trafilatura.PageType{
+	trafilatura.ArticlePage.String(): trafilatura.ArticlePage,
+	trafilatura.ListingPage.String(): trafilatura.ListingPage,
+	trafilatura.HomePage.String():    trafilatura.HomePage,
+	trafilatura.ProductPage.String(): trafilatura.ProductPage,
+	trafilatura.ForumPage.String():   trafilatura.ForumPage,
+	trafilatura.VideoPage.String():   trafilatura.VideoPage,
+	trafilatura.GalleryPage.String(): trafilatura.GalleryPage,
+	trafilatura.SearchPage.String():  trafilatura.SearchPage,
 }
 
 func createExtractorOptions(cmd *cobra.Command) trafilatura.Options {
@@ -188,14 +402,72 @@ func createExtractorOptions(cmd *cobra.Command) trafilatura.Options {
 	NoFallback, _ := flags.GetBool("no-fallback")
 
 	opts.EnableFallback = !NoFallback
-	opts.TargetLanguage, _ = flags.GetString("language")
+	useHydration, _ := flags.GetBool("fallback-hydration")
+	useJusText, _ := flags.GetBool("fallback-justext")
+	if useHydration || useJusText {
+		opts.FallbackExtractors = trafilatura.DefaultFallbackExtractors()
+		if useHydration {
+			opts.FallbackExtractors = append(opts.FallbackExtractors, trafilatura.HydrationExtractor{})
+		}
+		if useJusText {
+			opts.FallbackExtractors = append(opts.FallbackExtractors, trafilatura.JusTextExtractor{})
+		}
+	}
+
+	opts.TargetLanguages, _ = flags.GetStringSlice("language")
+	opts.LanguageCandidates, _ = flags.GetStringSlice("lang-candidates")
+	opts.DetectBlockLanguages, _ = flags.GetBool("block-languages")
+	opts.Encoding, _ = flags.GetString("encoding")
 	opts.ExcludeComments, _ = flags.GetBool("no-comments")
 	opts.ExcludeTables, _ = flags.GetBool("no-tables")
 	opts.IncludeImages, _ = flags.GetBool("images")
 	opts.IncludeLinks, _ = flags.GetBool("links")
 	opts.Deduplicate, _ = flags.GetBool("deduplicate")
+	opts.KeepHidden, _ = flags.GetBool("keep-hidden")
 	opts.HasEssentialMetadata, _ = flags.GetBool("has-metadata")
 	opts.EnableLog, _ = flags.GetBool("verbose")
+
+	rejectedPages, _ := flags.GetStringSlice("reject-pages")
+	for _, name := range rejectedPages {
+		state, ok := pageStates[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			log.Fatal().Msgf("unknown page state: %s", name)
+		}
+		opts.RejectedPageStates = append(opts.RejectedPageStates, state)
+	}
+
+	skippedTypes, _ := flags.GetStringSlice("skip-types")
+	for _, name := range skippedTypes {
+		pageType, ok := pageTypes[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			log.Fatal().Msgf("unknown page type: %s", name)
+		}
+		opts.SkippedPageTypes = append(opts.SkippedPageTypes, pageType)
+	}
+
+	rejectedRights, _ := flags.GetStringSlice("reject-rights")
+	for _, name := range rejectedRights {
+		restriction, ok := rightsRestrictions[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			log.Fatal().Msgf("unknown rights restriction: %s", name)
+		}
+		opts.RejectedRights = append(opts.RejectedRights, restriction)
+	}
+
+	opts.AllowedLicenses, _ = flags.GetStringSlice("licenses")
+
+	if hiddenClasses, _ := flags.GetStringSlice("hidden-classes"); len(hiddenClasses) > 0 {
+		opts.HiddenClasses = append(trafilatura.DefaultHiddenClasses(), hiddenClasses...)
+	}
+
+	if profilePath, _ := flags.GetString("site-profile"); profilePath != "" {
+		profile, err := loadSiteProfile(profilePath)
+		if err != nil {
+			log.Fatal().Msgf("failed to load site profile: %v", err)
+		}
+		opts.SiteProfile = profile
+	}
+
 	return opts
 }
 
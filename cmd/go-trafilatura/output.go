@@ -35,8 +35,12 @@ func outputExt(cmd *cobra.Command) string {
 	switch outputFormat {
 	case "txt":
 		return ".txt"
-	case "json":
+	case "json", "csl-json":
 		return ".json"
+	case "bibtex":
+		return ".bib"
+	case "ris":
+		return ".ris"
 	default:
 		return ".html"
 	}
@@ -50,6 +54,8 @@ func writeOutput(w io.Writer, result *trafilatura.ExtractResult, cmd *cobra.Comm
 		return writeText(w, result)
 	case "json":
 		return writeJSON(w, result)
+	case "bibtex", "ris", "csl-json":
+		return writeCitation(w, result, outputFormat)
 	default:
 		return writeHTML(w, result)
 	}
@@ -79,6 +85,30 @@ func writeJSON(w io.Writer, result *trafilatura.ExtractResult) error {
 	return json.NewEncoder(w).Encode(data)
 }
 
+func writeCitation(w io.Writer, result *trafilatura.ExtractResult, format string) error {
+	record := result.Metadata.Scholarly
+	if record == nil {
+		return fmt.Errorf("no bibliographic metadata in %q", result.Metadata.URL)
+	}
+
+	var citation []byte
+	switch format {
+	case "bibtex":
+		citation = []byte(record.BibTeX())
+	case "ris":
+		citation = []byte(record.RIS())
+	default:
+		data, err := record.CSLJSON()
+		if err != nil {
+			return err
+		}
+		citation = append(data, '\n')
+	}
+
+	_, err := w.Write(citation)
+	return err
+}
+
 func writeHTML(w io.Writer, result *trafilatura.ExtractResult) error {
 	doc := trafilatura.CreateReadableDocument(result)
 	_, err := fmt.Fprintln(w, dom.OuterHTML(doc))
@@ -102,6 +132,42 @@ func (r jsonExtractResult) MarshalJSON() ([]byte, error) {
 		"license":     r.Metadata.License,
 	}
 
+	if rights := r.Metadata.Rights; rights != (trafilatura.Rights{}) {
+		item := map[string]any{
+			"allRightsReserved": rights.AllRightsReserved,
+			"noAI":              rights.NoAI,
+			"noImageAI":         rights.NoImageAI,
+			"tdmReserved":       rights.TDMReserved,
+		}
+
+		if rights.License != "" {
+			item["license"] = rights.License
+			item["licenseSource"] = rights.LicenseSource
+		}
+
+		if rights.LicenseURL != "" {
+			item["licenseUrl"] = rights.LicenseURL
+		}
+
+		if rights.CopyrightHolder != "" {
+			item["copyrightHolder"] = rights.CopyrightHolder
+		}
+
+		if rights.CopyrightYear != 0 {
+			item["copyrightYear"] = rights.CopyrightYear
+		}
+
+		if rights.TDMPolicy != "" {
+			item["tdmPolicy"] = rights.TDMPolicy
+		}
+
+		metadata["rights"] = item
+	}
+
+	if r.Metadata.Scholarly != nil {
+		metadata["scholarly"] = r.Metadata.Scholarly.CSL()
+	}
+
 	// Convert result to map
 	result := map[string]any{
 		"contentHTML": dom.OuterHTML(r.ContentNode),
@@ -109,10 +175,105 @@ func (r jsonExtractResult) MarshalJSON() ([]byte, error) {
 		"metadata":    metadata,
 	}
 
+	if r.Encoding != "" {
+		result["encoding"] = r.Encoding
+	}
+
+	if len(r.Pages) > 1 {
+		var pages []map[string]any
+		for _, page := range r.Pages {
+			pages = append(pages, map[string]any{
+				"number":    page.Number,
+				"url":       page.URL,
+				"textStart": page.TextStart,
+				"textEnd":   page.TextEnd,
+			})
+		}
+		result["pages"] = pages
+	}
+
+	if r.Language.Language != "" {
+		result["language"] = map[string]any{
+			"code":       r.Language.Language,
+			"script":     r.Language.Script,
+			"confidence": r.Language.Confidence,
+		}
+	}
+
+	if r.PageClass.Type != trafilatura.UnknownPage {
+		result["pageType"] = r.PageClass.Type.String()
+		result["pageTypeConfidence"] = r.PageClass.Confidence
+	}
+
+	if r.PageStatus.State != trafilatura.PageAccessible {
+		result["pageState"] = r.PageStatus.State.String()
+		result["pageStateEvidence"] = r.PageStatus.Evidence
+	}
+
 	if r.CommentsNode != nil {
 		result["commentsText"] = r.CommentsText
 		result["commentsHTML"] = dom.OuterHTML(r.CommentsNode)
 	}
 
+	if len(r.Comments) > 0 {
+		var comments []map[string]any
+		for _, comment := range r.Comments {
+			item := map[string]any{
+				"id":         comment.ID,
+				"depth":      comment.Depth,
+				"text":       comment.Text,
+				"confidence": comment.Confidence,
+			}
+
+			if comment.ParentID != "" {
+				item["parentId"] = comment.ParentID
+			}
+
+			if comment.Author != "" {
+				item["author"] = comment.Author
+			}
+
+			if !comment.Date.IsZero() {
+				item["date"] = comment.Date
+			}
+
+			if comment.Permalink != "" {
+				item["permalink"] = comment.Permalink
+			}
+
+			comments = append(comments, item)
+		}
+		result["comments"] = comments
+	}
+
+	if len(r.LiveUpdates) > 0 {
+		var updates []map[string]any
+		for _, update := range r.LiveUpdates {
+			item := map[string]any{
+				"id":   update.ID,
+				"text": update.Text,
+			}
+
+			if !update.Date.IsZero() {
+				item["date"] = update.Date
+			}
+
+			if update.Headline != "" {
+				item["headline"] = update.Headline
+			}
+
+			if update.Author != "" {
+				item["author"] = update.Author
+			}
+
+			if update.URL != "" {
+				item["url"] = update.URL
+			}
+
+			updates = append(updates, item)
+		}
+		result["liveUpdates"] = updates
+	}
+
 	return json.Marshal(&result)
 }
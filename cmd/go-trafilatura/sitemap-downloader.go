@@ -27,7 +27,7 @@ import (
 	"sync"
 	"time"
 
-	betree "github.com/beevik/etree"
+	"github.com/markusmobius/go-trafilatura/sitemap"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 )
@@ -43,8 +43,8 @@ type sitemapDownloader struct {
 	filterFunc func(*nurl.URL) bool
 }
 
-func (sd *sitemapDownloader) downloadURLs(ctx context.Context, urls []*nurl.URL) []*nurl.URL {
-	pageURLs := []*nurl.URL{}
+func (sd *sitemapDownloader) downloadURLs(ctx context.Context, urls []*nurl.URL) []sitemap.Entry {
+	pageEntries := []sitemap.Entry{}
 	g, ctx := errgroup.WithContext(context.Background())
 
 	for _, url := range urls {
@@ -63,7 +63,7 @@ func (sd *sitemapDownloader) downloadURLs(ctx context.Context, urls []*nurl.URL)
 			}
 
 			// Download and parse url
-			newSitemapURLs, newPageURLs, err := sd.downloadURL(url)
+			newSitemapURLs, newPageEntries, err := sd.downloadURL(url)
 			sd.markAsDownloaded(url)
 			sd.semaphore.Release(1)
 
@@ -73,12 +73,12 @@ func (sd *sitemapDownloader) downloadURLs(ctx context.Context, urls []*nurl.URL)
 			}
 
 			// Process the additional urls
-			additionalPageURLs := sd.downloadURLs(ctx, newSitemapURLs)
+			additionalPageEntries := sd.downloadURLs(ctx, newSitemapURLs)
 
-			// Save all page URLs
+			// Save all page entries
 			sd.Lock()
-			pageURLs = append(pageURLs, newPageURLs...)
-			pageURLs = append(pageURLs, additionalPageURLs...)
+			pageEntries = append(pageEntries, newPageEntries...)
+			pageEntries = append(pageEntries, additionalPageEntries...)
 			sd.Unlock()
 
 			return nil
@@ -88,27 +88,32 @@ func (sd *sitemapDownloader) downloadURLs(ctx context.Context, urls []*nurl.URL)
 	g.Wait()
 
 	// Make sure page URLs are unique
-	uniquePageURLs := []*nurl.URL{}
+	uniquePageEntries := []sitemap.Entry{}
 	uniqueTracker := make(map[string]struct{})
 
-	for _, url := range pageURLs {
-		if sd.filterFunc != nil && !sd.filterFunc(url) {
+	for _, entry := range pageEntries {
+		parsedURL, valid := validateURL(entry.URL)
+		if !valid {
+			continue
+		}
+
+		if sd.filterFunc != nil && !sd.filterFunc(parsedURL) {
 			continue
 		}
 
-		strURL := url.String()
+		strURL := parsedURL.String()
 		if _, exist := uniqueTracker[strURL]; exist {
 			continue
 		}
 
 		uniqueTracker[strURL] = struct{}{}
-		uniquePageURLs = append(uniquePageURLs, url)
+		uniquePageEntries = append(uniquePageEntries, entry)
 	}
 
-	return uniquePageURLs
+	return uniquePageEntries
 }
 
-func (sd *sitemapDownloader) downloadURL(url *nurl.URL) ([]*nurl.URL, []*nurl.URL, error) {
+func (sd *sitemapDownloader) downloadURL(url *nurl.URL) ([]*nurl.URL, []sitemap.Entry, error) {
 	// Download URL
 	strURL := url.String()
 	log.Info().Msgf("downloading sitemap %q", strURL)
@@ -119,35 +124,21 @@ func (sd *sitemapDownloader) downloadURL(url *nurl.URL) ([]*nurl.URL, []*nurl.UR
 	}
 	defer resp.Body.Close()
 
-	// Make sure it's XML
+	// Make sure it's not HTML, which usually means the sitemap is not found
 	contentType := resp.Header.Get("Content-Type")
-	if !sd.contentIsXML(contentType) {
-		return nil, nil, fmt.Errorf("%s is not xml: \"%s\"", strURL, contentType)
+	if strings.Contains(contentType, "text/html") {
+		return nil, nil, fmt.Errorf("%s is not sitemap: \"%s\"", strURL, contentType)
 	}
 
-	// Parse
-	doc := betree.NewDocument()
-	if _, err := doc.ReadFrom(resp.Body); err != nil {
+	// Parse, the format (XML, plain text or gzipped) is detected from its content
+	parsed, err := sitemap.Parse(resp.Body)
+	if err != nil {
 		return nil, nil, err
 	}
 
-	pageURLs := []*nurl.URL{}
 	sitemapURLs := []*nurl.URL{}
-	for _, loc := range doc.FindElements("//loc") {
-		parsedURL, valid := validateURL(loc.Text())
-		if !valid {
-			continue
-		}
-
-		parent := loc.Parent()
-		if parent == nil {
-			continue
-		}
-
-		switch parent.Tag {
-		case "url":
-			pageURLs = append(pageURLs, parsedURL)
-		case "sitemap":
+	for _, entry := range parsed.Sitemaps {
+		if parsedURL, valid := validateURL(entry.URL); valid {
 			sitemapURLs = append(sitemapURLs, parsedURL)
 		}
 	}
@@ -155,7 +146,7 @@ func (sd *sitemapDownloader) downloadURL(url *nurl.URL) ([]*nurl.URL, []*nurl.UR
 	// Add delay (to prevent too many request to target server)
 	time.Sleep(sd.delay)
 
-	return sitemapURLs, pageURLs, nil
+	return sitemapURLs, parsed.Entries, nil
 }
 
 func (sd *sitemapDownloader) isDownloaded(url *nurl.URL) bool {
@@ -171,14 +162,3 @@ func (sd *sitemapDownloader) markAsDownloaded(url *nurl.URL) {
 	sd.cache[url.String()] = struct{}{}
 	sd.Unlock()
 }
-
-func (sd *sitemapDownloader) contentIsXML(contentType string) bool {
-	switch {
-	case strings.Contains(contentType, "text/xml"),
-		strings.Contains(contentType, "application/xml"):
-		return true
-
-	default:
-		return false
-	}
-}
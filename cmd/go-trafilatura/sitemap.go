@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/markusmobius/go-trafilatura"
+	"github.com/markusmobius/go-trafilatura/sitemap"
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/semaphore"
@@ -41,7 +42,10 @@ func sitemapCmd() *cobra.Command {
 		Short: "Download and extract pages from a sitemap",
 		Long: "Download and extract pages from list of urls that specified in a sitemap.\n" +
 			"Trafilatura supports simple sitemap finder, so you can point the url into\n" +
-			"an ordinary web page then Trafilatura will attempt to find the sitemap.",
+			"an ordinary web page then Trafilatura will attempt to find the sitemap.\n" +
+			"XML, plain text and gzipped sitemaps are supported, and the pages will be\n" +
+			"processed from the most recent one according to their last modified date\n" +
+			"or Google News publication date.",
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			newSitemapCmdHandler(cmd).run(args)
@@ -57,6 +61,8 @@ func sitemapCmd() *cobra.Command {
 	flags.StringArray("domains", nil, "list of allowed domains")
 	flags.StringArray("no-domains", nil, "list of excluded domains")
 	flags.Bool("url-only", false, "only print page urls without downloading or processing them")
+	flags.String("since", "", "only process pages modified or published since this date (YYYY-MM-DD or RFC3339)")
+	flags.String("until", "", "only process pages modified or published until this date (YYYY-MM-DD or RFC3339)")
 
 	return cmd
 }
@@ -67,6 +73,8 @@ type sitemapCmdHandler struct {
 	sitemapDownloader *sitemapDownloader
 	pagesDownloader   *batchDownloader
 	urlOnly           bool
+	since             time.Time
+	until             time.Time
 }
 
 func newSitemapCmdHandler(cmd *cobra.Command) *sitemapCmdHandler {
@@ -80,7 +88,21 @@ func newSitemapCmdHandler(cmd *cobra.Command) *sitemapCmdHandler {
 	excludedDomains, _ := flags.GetStringArray("no-domains")
 	outputDir, _ := flags.GetString("output")
 	urlOnly, _ := flags.GetBool("url-only")
+	strSince, _ := flags.GetString("since")
+	strUntil, _ := flags.GetString("until")
 	userAgent, _ := cmd.Flags().GetString("user-agent")
+	maxPages, _ := cmd.Flags().GetInt("max-pages")
+
+	// Parse date filter
+	since, err := parseDateFlag(strSince, false)
+	if err != nil {
+		log.Fatal().Msgf("since date is not valid: %v", err)
+	}
+
+	until, err := parseDateFlag(strUntil, true)
+	if err != nil {
+		log.Fatal().Msgf("until date is not valid: %v", err)
+	}
 
 	// Prepare http client
 	httpClient := createHttpClient(cmd)
@@ -153,6 +175,7 @@ func newSitemapCmdHandler(cmd *cobra.Command) *sitemapCmdHandler {
 		delay:          time.Duration(delay) * time.Second,
 		cancelOnError:  false,
 		writeFunc:      fnWrite,
+		maxPages:       maxPages,
 	}
 
 	// Make sure output dir exist
@@ -165,6 +188,8 @@ func newSitemapCmdHandler(cmd *cobra.Command) *sitemapCmdHandler {
 		sitemapDownloader: sDownloader,
 		pagesDownloader:   pagesDownloader,
 		urlOnly:           urlOnly,
+		since:             since,
+		until:             until,
 	}
 }
 
@@ -177,8 +202,21 @@ func (sch *sitemapCmdHandler) run(args []string) {
 
 	// Download all sitemaps recursively, concurrently
 	ctx := context.Background()
-	pageURLs := sch.sitemapDownloader.downloadURLs(ctx, sitemapURLs)
-	log.Info().Msgf("found %d page URLs", len(pageURLs))
+	pageEntries := sch.sitemapDownloader.downloadURLs(ctx, sitemapURLs)
+	log.Info().Msgf("found %d page URLs", len(pageEntries))
+
+	// Filter by date, then process the most recent pages first
+	if !sch.since.IsZero() || !sch.until.IsZero() {
+		pageEntries = sitemap.Filter(pageEntries, sch.since, sch.until)
+		log.Info().Msgf("%d page URLs left after date filter", len(pageEntries))
+	}
+
+	sitemap.SortByRecency(pageEntries)
+	pageURLs := make([]*nurl.URL, 0, len(pageEntries))
+	for _, entry := range pageEntries {
+		parsedURL, _ := validateURL(entry.URL)
+		pageURLs = append(pageURLs, parsedURL)
+	}
 
 	// If user only want to print URLs, stop
 	if sch.urlOnly {
@@ -205,8 +243,10 @@ func (sch *sitemapCmdHandler) findSitemapURLs(baseURL string) ([]*nurl.URL, erro
 	parsedURL, _ := nurl.ParseRequestURI(baseURL)
 
 	// If it already looks like a sitemap URL, return
-	if strings.HasSuffix(baseURL, ".xml") ||
-		strings.HasSuffix(baseURL, "sitemap") {
+	if strings.HasSuffix(parsedURL.Path, ".xml") ||
+		strings.HasSuffix(parsedURL.Path, ".xml.gz") ||
+		strings.HasSuffix(parsedURL.Path, ".txt") ||
+		strings.HasSuffix(parsedURL.Path, "sitemap") {
 		return []*nurl.URL{parsedURL}, nil
 	}
 
@@ -272,3 +312,30 @@ func (sch *sitemapCmdHandler) findSitemapURLsInRobots(robotsURL string) ([]*nurl
 
 	return sitemapURLs, nil
 }
+
+// parseDateFlag parses date from command flag. If the date doesn't have time and
+// it's used as end of range, it will be moved to the end of the period.
+func parseDateFlag(str string, endOfRange bool) (time.Time, error) {
+	if str == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := sitemap.ParseDate(str)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if endOfRange && !strings.Contains(str, "T") {
+		switch len(str) {
+		case 4: // YYYY
+			date = date.AddDate(1, 0, 0)
+		case 7: // YYYY-MM
+			date = date.AddDate(0, 1, 0)
+		default:
+			date = date.AddDate(0, 0, 1)
+		}
+		date = date.Add(-time.Nanosecond)
+	}
+
+	return date, nil
+}
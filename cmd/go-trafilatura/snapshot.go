@@ -0,0 +1,387 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"os"
+	fp "path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"github.com/markusmobius/go-trafilatura"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+// Extensions of the golden files for each page.
+var snapshotKinds = []string{".txt", ".html", ".json"}
+
+var (
+	// rxBlockTag matches the opening and closing tag of block elements, used to put
+	// each block in its own line so the diff is readable.
+	rxBlockTag = regexp.MustCompile(`(?i)(</?(?:article|blockquote|body|dd|div|dl|dt|figcaption|figure|h[1-6]|hr|li|main|ol|p|pre|section|table|tbody|td|th|thead|tr|ul)[\s/>])`)
+	rxAnyTag   = regexp.MustCompile(`<[^>]*>`)
+)
+
+func snapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot [flags] [source...]",
+		Short: "Compare extraction result with the saved golden files",
+		Long: "Extract the HTML files in source (file, directory or glob pattern), then compare the\n" +
+			"normalized text, HTML and metadata with the golden files from the previous run. The\n" +
+			"pages whose output changed are reported with unified diff and similarity ratio.\n" +
+			"Use --update to accept the changes by saving the new output as golden files. Without\n" +
+			"it, the command exits with error if any page changed.",
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			snapshotCmdHandler(cmd, args)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringP("golden", "g", "snapshots", "directory for the golden files")
+	flags.Bool("update", false, "save the current output as the new golden files")
+	flags.StringArray("include", defaultIncludePatterns, "file name patterns to process when walking directory")
+	flags.Int("parallel", 10, "number of concurrent extraction (default 10)")
+	flags.Int("context", 3, "number of context lines in diff")
+
+	return cmd
+}
+
+type snapshotStatus string
+
+const (
+	snapshotUnchanged snapshotStatus = "UNCHANGED"
+	snapshotChanged   snapshotStatus = "CHANGED"
+	snapshotNew       snapshotStatus = "NEW"
+	snapshotRemoved   snapshotStatus = "REMOVED"
+)
+
+// pageSnapshot is the normalized extraction output of a page, keyed by
+// the golden file extension.
+type pageSnapshot struct {
+	name    string
+	base    string
+	outputs map[string]string
+	status  snapshotStatus
+}
+
+func snapshotCmdHandler(cmd *cobra.Command, args []string) {
+	// Parse flags
+	flags := cmd.Flags()
+	goldenDir, _ := flags.GetString("golden")
+	update, _ := flags.GetBool("update")
+	includePatterns, _ := flags.GetStringArray("include")
+	nThread, _ := flags.GetInt("parallel")
+	nContext, _ := flags.GetInt("context")
+
+	// Collect input files
+	files, err := collectInputFiles(args, includePatterns)
+	if err != nil {
+		log.Fatal().Msgf("failed to collect input: %v", err)
+	}
+
+	if len(files) == 0 {
+		log.Fatal().Msgf("no input file found")
+	}
+	log.Info().Msgf("found %d input files", len(files))
+
+	// Extract the current snapshots
+	opts := createExtractorOptions(cmd)
+	snapshots, err := createSnapshots(context.Background(), files, goldenDir, opts, nThread)
+	if err != nil {
+		log.Fatal().Msgf("failed to create snapshots: %v", err)
+	}
+
+	// Find the golden pages whose source no longer exist
+	removed, err := findRemovedSnapshots(goldenDir, snapshots)
+	if err != nil {
+		log.Fatal().Msgf("failed to read golden files: %v", err)
+	}
+
+	// Compare and report
+	nChanged := writeSnapshotReport(os.Stdout, snapshots, removed, nContext)
+	if nChanged == 0 {
+		log.Info().Msgf("all %d pages are unchanged", len(snapshots))
+		return
+	}
+
+	// Save the changes if requested, otherwise fail
+	if !update {
+		log.Fatal().Msgf("%d pages changed, use --update to accept the changes", nChanged)
+	}
+
+	err = updateSnapshots(snapshots, removed)
+	if err != nil {
+		log.Fatal().Msgf("failed to update golden files: %v", err)
+	}
+	log.Info().Msgf("updated golden files for %d pages", nChanged)
+}
+
+func createSnapshots(ctx context.Context, files []inputFile, goldenDir string, opts trafilatura.Options, nThread int) ([]*pageSnapshot, error) {
+	snapshots := make([]*pageSnapshot, len(files))
+	sem := semaphore.NewWeighted(int64(max(nThread, 1)))
+	g, ctx := errgroup.WithContext(ctx)
+
+	for i, file := range files {
+		g.Go(func() error {
+			if err := sem.Acquire(ctx, 1); err != nil {
+				return err
+			}
+			defer sem.Release(1)
+
+			result, err := processFile(file.path, opts)
+			snapshot := &pageSnapshot{
+				name:    file.path,
+				base:    outputPath(goldenDir, file.relPath, ""),
+				outputs: normalizeSnapshot(result, err),
+			}
+
+			snapshot.status, err = snapshot.compare()
+			if err != nil {
+				return err
+			}
+
+			snapshots[i] = snapshot
+			return nil
+		})
+	}
+
+	return snapshots, g.Wait()
+}
+
+// normalizeSnapshot converts the extraction result into stable text, so it's
+// easy to compare and to review in diff.
+func normalizeSnapshot(result *trafilatura.ExtractResult, extractErr error) map[string]string {
+	outputs := make(map[string]string)
+	metadata := map[string]any{}
+
+	if extractErr != nil {
+		metadata["error"] = extractErr.Error()
+	}
+
+	if result != nil {
+		var rawHTML string
+		if result.ContentNode != nil {
+			rawHTML = dom.OuterHTML(result.ContentNode)
+		}
+		if result.CommentsNode != nil {
+			rawHTML += "\n" + dom.OuterHTML(result.CommentsNode)
+		}
+
+		// Put each block in its own line, then use it to create the text as well
+		// since the plain text result is written in a single line.
+		rawHTML = normalizeLines(rxBlockTag.ReplaceAllString(rawHTML, "\n$1"))
+		outputs[".html"] = rawHTML
+
+		var textLines []string
+		for _, line := range strings.Split(rawHTML, "\n") {
+			line = html.UnescapeString(rxAnyTag.ReplaceAllString(line, " "))
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				textLines = append(textLines, line)
+			}
+		}
+		outputs[".txt"] = normalizeLines(strings.Join(textLines, "\n"))
+
+		m := result.Metadata
+		metadata["title"] = m.Title
+		metadata["author"] = m.Author
+		metadata["url"] = m.URL
+		metadata["hostname"] = m.Hostname
+		metadata["description"] = m.Description
+		metadata["sitename"] = m.Sitename
+		metadata["categories"] = m.Categories
+		metadata["tags"] = m.Tags
+		metadata["license"] = m.License
+		metadata["language"] = m.Language
+		metadata["image"] = m.Image
+		metadata["pageType"] = m.PageType
+		metadata["date"] = ""
+		if !m.Date.IsZero() {
+			metadata["date"] = m.Date.Format(time.RFC3339)
+		}
+	}
+
+	// Map keys are sorted by encoder, so the output is stable
+	bt, _ := json.MarshalIndent(metadata, "", "  ")
+	outputs[".json"] = string(bt) + "\n"
+	return outputs
+}
+
+// normalizeLines trims the trailing spaces of each line and removes the empty
+// lines in the beginning and end of text.
+func normalizeLines(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	text = strings.Trim(strings.Join(lines, "\n"), "\n")
+	if text == "" {
+		return ""
+	}
+	return text + "\n"
+}
+
+// compare checks the snapshot against its golden files.
+func (ps *pageSnapshot) compare() (snapshotStatus, error) {
+	status := snapshotUnchanged
+	nExist := 0
+
+	for _, ext := range snapshotKinds {
+		golden, err := os.ReadFile(ps.base + ext)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		nExist++
+		if string(golden) != ps.outputs[ext] {
+			status = snapshotChanged
+		}
+	}
+
+	if nExist == 0 {
+		return snapshotNew, nil
+	}
+
+	if nExist < len(snapshotKinds) {
+		return snapshotChanged, nil
+	}
+
+	return status, nil
+}
+
+// findRemovedSnapshots returns base path of the golden files whose page is not
+// in the current snapshots.
+func findRemovedSnapshots(goldenDir string, snapshots []*pageSnapshot) ([]string, error) {
+	if !dirExists(goldenDir) {
+		return nil, nil
+	}
+
+	current := make(map[string]struct{})
+	for _, snapshot := range snapshots {
+		current[snapshot.base] = struct{}{}
+	}
+
+	var removed []string
+	err := fp.WalkDir(goldenDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !slices.Contains(snapshotKinds, fp.Ext(path)) {
+			return err
+		}
+
+		base := strings.TrimSuffix(path, fp.Ext(path))
+		if _, exist := current[base]; !exist && !slices.Contains(removed, base) {
+			removed = append(removed, base)
+		}
+		return nil
+	})
+
+	return removed, err
+}
+
+// writeSnapshotReport prints the changed pages with their diff, then returns
+// the number of changed pages.
+func writeSnapshotReport(w io.Writer, snapshots []*pageSnapshot, removed []string, nContext int) int {
+	var nChanged, nNew int
+	for _, snapshot := range snapshots {
+		switch snapshot.status {
+		case snapshotNew:
+			nNew++
+			fmt.Fprintf(w, "%s %s\n", snapshot.status, snapshot.name)
+
+		case snapshotChanged:
+			nChanged++
+			fmt.Fprintf(w, "%s %s\n", snapshot.status, snapshot.name)
+
+			for _, ext := range snapshotKinds {
+				goldenPath := snapshot.base + ext
+				golden, _ := os.ReadFile(goldenPath)
+				oldLines, newLines := splitLines(string(golden)), splitLines(snapshot.outputs[ext])
+				if slices.Equal(oldLines, newLines) {
+					continue
+				}
+
+				ratio := similarityRatio(strings.Fields(string(golden)), strings.Fields(snapshot.outputs[ext]))
+				fmt.Fprintf(w, "  %s similarity %.3f\n", ext[1:], ratio)
+				fmt.Fprint(w, unifiedDiff(goldenPath, goldenPath+" (current)", oldLines, newLines, nContext))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	for _, base := range removed {
+		fmt.Fprintf(w, "%s %s\n", snapshotRemoved, base)
+	}
+
+	fmt.Fprintf(w, "%d pages: %d unchanged, %d changed, %d new, %d removed\n",
+		len(snapshots), len(snapshots)-nChanged-nNew, nChanged, nNew, len(removed))
+	return nChanged + nNew + len(removed)
+}
+
+func updateSnapshots(snapshots []*pageSnapshot, removed []string) error {
+	for _, snapshot := range snapshots {
+		if snapshot.status == snapshotUnchanged {
+			continue
+		}
+
+		err := os.MkdirAll(fp.Dir(snapshot.base), os.ModePerm)
+		if err != nil {
+			return err
+		}
+
+		for _, ext := range snapshotKinds {
+			err = os.WriteFile(snapshot.base+ext, []byte(snapshot.outputs[ext]), 0644)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, base := range removed {
+		for _, ext := range snapshotKinds {
+			err := os.Remove(base + ext)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
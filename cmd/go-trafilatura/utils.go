@@ -121,3 +121,8 @@ func createAbsoluteURL(url string, base *nurl.URL) string {
 
 	return base.ResolveReference(tmp).String()
 }
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,564 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/go-shiori/dom"
+	"github.com/markusmobius/go-trafilatura/internal/selector"
+	"golang.org/x/net/html"
+)
+
+// Comment is a single comment that found in the comment section of a web page.
+type Comment struct {
+	// ID is the identifier of the comment, taken from the id attribute of its
+	// outermost element. If the element doesn't have any id, it will be the 1-based
+	// position of the comment within the thread.
+	ID string
+
+	// ParentID is the ID of the comment that replied by this comment.
+	// Will be empty for top level comments.
+	ParentID string
+
+	// Depth is the nesting level of the comment, 0 for top level comments.
+	Depth int
+
+	Author    string
+	Date      time.Time
+	Permalink string
+	Text      string
+
+	// Confidence is how likely this is an actual comment, between 0 and 1.
+	// Comments that marked up by schema.org or well-known comment systems
+	// have higher confidence than the ones detected from generic markup.
+	Confidence float64
+}
+
+// Comment sections of well-known comment systems, in case they are not matched
+// by the common comment selectors.
+var commentSystemRegions = []string{
+	"#dsq-comments, #dsq-content",
+	"#discourse-comments, .discourse-comments",
+}
+
+var commentSystemSelectors = func() []cascadia.Selector {
+	var selectors []cascadia.Selector
+	for _, query := range commentSystemRegions {
+		selectors = append(selectors, cascadia.MustCompile(query))
+	}
+	return selectors
+}()
+
+// Elements that used for the comment text, author and date by common comment systems
+// e.g. WordPress, Disqus static markup and Discourse embeds.
+const (
+	commentTextSelector = ".comment-content, .comment-text, .comment_text, .comment-body-text, " +
+		"[itemprop=text], .post-message, .dsq-comment-message, .cooked, .comment-entry"
+	commentAuthorSelector = "[itemprop=author] [itemprop=name], [itemprop=author], .comment-author .fn, " +
+		".comment-author, .dsq-comment-header cite, .post-byline .author, .username, .author, cite"
+	commentDateSelector = "time, [itemprop=dateCreated], [itemprop=datePublished], [data-time], " +
+		"[data-timestamp], abbr[title]"
+	commentMetaSelector = ".comment-meta, .comment-metadata, .reply, .comment-reply-link, .avatar, " +
+		".post-meta, .dsq-comment-header, .post-menu-area, .topic-meta-data, .comment-awaiting-moderation"
+)
+
+var (
+	rxCommentItemID = regexp.MustCompile(`(?i)^(?:li-|div-|dsq-)?(?:comment|post|reply)[-_]?\d+$`)
+	rxCommentDepth  = regexp.MustCompile(`(?:^|\s)depth-(\d+)(?:\s|$)`)
+	rxCommentSays   = regexp.MustCompile(`(?i)\s*(?:says|said|wrote|sagt|schreibt|dit|dice|zegt)\s*:?\s*$`)
+)
+
+var commentDateFormats = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	time.DateOnly,
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// commentCandidate is element that possibly contains a single comment.
+type commentCandidate struct {
+	node       *html.Node
+	confidence float64
+	isWrapper  bool
+	item       *commentCandidate
+
+	// parent is the closest candidate that contains this one, while end is the
+	// index after the last nested candidate. Since candidates are in document
+	// order, the nested candidates are the ones between this and end.
+	parent *commentCandidate
+	index  int
+	end    int
+}
+
+// commentCandidates is the lookup of candidates by their element.
+type commentCandidates map[*html.Node]*commentCandidate
+
+// extractCommentThreads detects the boundary of each comment within the comment
+// section, then extracts them along with the reply structure between them.
+func extractCommentThreads(doc *html.Node, opts Options) []Comment {
+	// Use the first region that has comments in it
+	for _, region := range findCommentRegions(doc) {
+		if comments := extractRegionComments(region, opts); len(comments) > 0 {
+			return comments
+		}
+	}
+
+	return nil
+}
+
+// findCommentRegions returns the first match of each well-known comment systems,
+// followed by the first match of each generic comment sections. All of them are
+// looked up in a single pass over the document.
+func findCommentRegions(doc *html.Node) []*html.Node {
+	rules := make([]selector.Rule, 0, len(commentSystemSelectors)+len(selector.Comments))
+	for _, sel := range commentSystemSelectors {
+		rules = append(rules, sel.Match)
+	}
+	rules = append(rules, selector.Comments...)
+
+	matches := make([]*html.Node, len(rules))
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+
+			// All comment selectors look at id or class, so skip the element without them
+			if dom.HasAttribute(child, "id") || dom.HasAttribute(child, "class") {
+				for i, rule := range rules {
+					if matches[i] == nil && rule(child) {
+						matches[i] = child
+					}
+				}
+			}
+
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	var regions []*html.Node
+	for _, match := range matches {
+		if match != nil {
+			regions = append(regions, match)
+		}
+	}
+	return regions
+}
+
+func extractRegionComments(region *html.Node, opts Options) []Comment {
+	candidates := findCommentCandidates(region)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	index := make(commentCandidates, len(candidates))
+	for _, c := range candidates {
+		index[c.node] = c
+	}
+
+	// Wrappers are elements that only hold a comment without much text of their own,
+	// e.g. <li> in WordPress that wraps the comment <article> and its replies. They
+	// are represented by the first comment inside them.
+	var items []*commentCandidate
+	for _, c := range candidates {
+		hasChild := c.end > c.index+1
+		ownText := strings.TrimSpace(commentOwnText(c.node, index))
+		c.isWrapper = hasChild && len([]rune(ownText)) < 30
+		if !c.isWrapper && ownText != "" {
+			c.item = c
+			items = append(items, c)
+		}
+	}
+
+	for _, c := range candidates {
+		if !c.isWrapper {
+			continue
+		}
+
+		for _, nested := range candidates[c.index+1 : c.end] {
+			if nested.item == nested {
+				c.item = nested
+				nested.confidence = max(nested.confidence, c.confidence)
+				break
+			}
+		}
+	}
+
+	// Extract each comment
+	var comments []Comment
+	commentByItem := make(map[*commentCandidate]int)
+	for _, item := range items {
+		comment := extractComment(item, index, opts)
+		if comment.Text == "" {
+			continue
+		}
+
+		if comment.ID == "" {
+			comment.ID = strconv.Itoa(len(comments) + 1)
+		}
+
+		// Find parent and depth from the distinct comments that contain this one,
+		// starting from the closest one
+		var ancestors []*commentCandidate
+		for c := item.parent; c != nil; c = c.parent {
+			if c.item == nil || c.item == item {
+				continue
+			}
+
+			if len(ancestors) == 0 || ancestors[len(ancestors)-1] != c.item {
+				ancestors = append(ancestors, c.item)
+			}
+		}
+
+		if len(ancestors) > 0 {
+			if idx, exist := commentByItem[ancestors[0]]; exist {
+				comment.ParentID = comments[idx].ID
+			}
+		}
+
+		comment.Depth = len(ancestors)
+		if depth, ok := commentDepthClass(item, index); ok {
+			comment.Depth = depth
+		}
+
+		commentByItem[item] = len(comments)
+		comments = append(comments, comment)
+	}
+
+	return comments
+}
+
+// findCommentCandidates returns elements inside the region that look like a single
+// comment, in document order. The nesting between candidates is recorded as well,
+// so it doesn't need to be looked up again for each of them.
+func findCommentCandidates(region *html.Node) []*commentCandidate {
+	candidates := collectCommentCandidates(region, commentItemScore)
+
+	// As last resort, use the items of list in the region
+	if len(candidates) == 0 {
+		candidates = collectCommentCandidates(region, func(node *html.Node) float64 {
+			if dom.TagName(node) == "li" && len([]rune(trim(dom.TextContent(node)))) >= 20 {
+				return 0.5
+			}
+			return 0
+		})
+	}
+
+	return candidates
+}
+
+func collectCommentCandidates(region *html.Node, score func(*html.Node) float64) []*commentCandidate {
+	var candidates []*commentCandidate
+	var walk func(*html.Node, *commentCandidate)
+	walk = func(node *html.Node, parent *commentCandidate) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+
+			c := parent
+			if confidence := score(child); confidence > 0 {
+				c = &commentCandidate{
+					node:       child,
+					confidence: confidence,
+					parent:     parent,
+					index:      len(candidates),
+				}
+				candidates = append(candidates, c)
+			}
+
+			walk(child, c)
+			if c != parent {
+				c.end = len(candidates)
+			}
+		}
+	}
+	walk(region, nil)
+
+	return candidates
+}
+
+func commentItemScore(node *html.Node) float64 {
+	switch dom.TagName(node) {
+	case "a", "p", "span", "time", "img", "form", "input", "textarea", "button", "script", "style":
+		return 0
+	}
+
+	itemType := dom.GetAttribute(node, "itemtype")
+	itemProp := dom.GetAttribute(node, "itemprop")
+	switch {
+	case strings.Contains(itemType, "schema.org/Comment"),
+		itemProp == "comment":
+		return 0.9
+	case rxCommentItemID.MatchString(dom.ID(node)):
+		return 0.85
+	case hasClassToken(node, "comment", "comment-item", "dsq-comment", "topic-post", "crawler-post", "post"):
+		return 0.7
+	default:
+		return 0
+	}
+}
+
+func hasClassToken(node *html.Node, tokens ...string) bool {
+	for _, class := range strings.Fields(dom.ClassName(node)) {
+		if strIn(class, tokens...) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAncestorOf(ancestor, node *html.Node) bool {
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		if parent == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsNode checks if node belongs to the item, i.e. it's not part of the other
+// comments that nested inside the item.
+func ownsNode(item *commentCandidate, node *html.Node, candidates commentCandidates) bool {
+	for parent := node; parent != nil && parent != item.node; parent = parent.Parent {
+		if c, exist := candidates[parent]; exist && c.item != item {
+			return false
+		}
+	}
+	return true
+}
+
+func queryOwn(item *commentCandidate, query string, candidates commentCandidates) *html.Node {
+	// Selectors in query are checked by their priority, not by document order
+	for subQuery := range strings.SplitSeq(query, ",") {
+		for _, node := range dom.QuerySelectorAll(item.node, strings.TrimSpace(subQuery)) {
+			if ownsNode(item, node, candidates) {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+// commentOwnText returns the text of node, excluding the text of nested candidates.
+func commentOwnText(node *html.Node, candidates commentCandidates) string {
+	return commentText(node, func(n *html.Node) bool {
+		_, isCandidate := candidates[n]
+		return isCandidate
+	})
+}
+
+// commentText returns the text of node, with a line break between each block
+// element. The element that marked by skip is excluded.
+func commentText(node *html.Node, skip func(*html.Node) bool) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			switch child.Type {
+			case html.TextNode:
+				sb.WriteString(child.Data)
+			case html.ElementNode:
+				if skip(child) {
+					continue
+				}
+
+				switch dom.TagName(child) {
+				case "script", "style", "noscript", "template", "form", "button":
+					continue
+				case "br":
+					sb.WriteString("\n")
+				case "a", "abbr", "b", "cite", "code", "em", "i", "kbd", "mark",
+					"q", "s", "small", "span", "strong", "sub", "sup", "time", "u":
+					walk(child)
+				default:
+					sb.WriteString("\n")
+					walk(child)
+					sb.WriteString("\n")
+				}
+			}
+		}
+	}
+	walk(node)
+
+	var lines []string
+	for line := range strings.SplitSeq(sb.String(), "\n") {
+		if line = trim(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func extractComment(item *commentCandidate, candidates commentCandidates, opts Options) Comment {
+	comment := Comment{Confidence: item.confidence}
+
+	// Use the id of the outermost element of the comment, since that's usually
+	// the one that used as anchor in permalink
+	wrappers := commentWrappers(item)
+	for i := len(wrappers) - 1; i >= 0 && comment.ID == ""; i-- {
+		comment.ID = dom.ID(wrappers[i].node)
+	}
+
+	if comment.ID == "" {
+		comment.ID = dom.ID(item.node)
+	}
+
+	// Author
+	authorNode := queryOwn(item, commentAuthorSelector, candidates)
+	if authorNode != nil {
+		comment.Author = trim(dom.TextContent(authorNode))
+		comment.Author = rxCommentSays.ReplaceAllString(comment.Author, "")
+		if comment.Author != "" {
+			comment.Confidence += 0.05
+		}
+	}
+
+	// Date
+	dateNode := queryOwn(item, commentDateSelector, candidates)
+	if dateNode != nil {
+		comment.Date = parseCommentDate(dateNode)
+		if !comment.Date.IsZero() {
+			comment.Confidence += 0.05
+		}
+	}
+
+	// Permalink
+	comment.Permalink = commentPermalink(item, comment.ID, dateNode, candidates)
+	comment.Permalink = createAbsoluteURL(comment.Permalink, opts.OriginalURL)
+	if strings.HasPrefix(comment.Permalink, "#") && opts.OriginalURL != nil {
+		pageURL := *opts.OriginalURL
+		pageURL.Fragment = comment.Permalink[1:]
+		comment.Permalink = pageURL.String()
+	}
+
+	// Text, either from the dedicated content element or from the whole item
+	// excluding its metadata
+	if textNode := queryOwn(item, commentTextSelector, candidates); textNode != nil {
+		comment.Text = commentOwnText(textNode, candidates)
+		comment.Confidence += 0.05
+	} else {
+		metaNodes := make(map[*html.Node]struct{})
+		for _, node := range dom.QuerySelectorAll(item.node, commentMetaSelector) {
+			metaNodes[node] = struct{}{}
+		}
+
+		comment.Text = commentText(item.node, func(n *html.Node) bool {
+			_, isMeta := metaNodes[n]
+			_, isCandidate := candidates[n]
+			return isMeta || isCandidate || n == authorNode || n == dateNode
+		})
+	}
+
+	comment.Confidence = min(comment.Confidence, 1)
+	return comment
+}
+
+func parseCommentDate(node *html.Node) time.Time {
+	// Check epoch timestamp, used by Discourse in milliseconds
+	for _, attr := range []string{"data-time", "data-timestamp"} {
+		if value := dom.GetAttribute(node, attr); value != "" {
+			if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+				if epoch > 1e11 {
+					return time.UnixMilli(epoch).UTC()
+				}
+				return time.Unix(epoch, 0).UTC()
+			}
+		}
+	}
+
+	for _, value := range []string{
+		dom.GetAttribute(node, "datetime"),
+		dom.GetAttribute(node, "content"),
+		dom.GetAttribute(node, "title"),
+		trim(dom.TextContent(node)),
+	} {
+		if value == "" {
+			continue
+		}
+
+		for _, format := range commentDateFormats {
+			if date, err := time.Parse(format, value); err == nil {
+				return date
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+func commentPermalink(item *commentCandidate, id string, dateNode *html.Node, candidates commentCandidates) string {
+	// Look for link that points to the comment itself
+	for _, a := range dom.QuerySelectorAll(item.node, "a[href]") {
+		if !ownsNode(item, a, candidates) {
+			continue
+		}
+
+		href := strings.TrimSpace(dom.GetAttribute(a, "href"))
+		_, fragment, _ := strings.Cut(href, "#")
+		if fragment != "" && (fragment == id || fragment == dom.ID(item.node)) {
+			return href
+		}
+
+		if dateNode != nil && (a == dateNode.Parent || isAncestorOf(a, dateNode) || a == dateNode) {
+			return href
+		}
+	}
+
+	// Use the id of comment as anchor
+	if id != "" {
+		return "#" + id
+	}
+
+	return ""
+}
+
+// commentDepthClass returns the comment depth that specified by WordPress
+// in `depth-N` class of the comment or its wrapper.
+func commentDepthClass(item *commentCandidate, candidates commentCandidates) (int, bool) {
+	for _, c := range append([]*commentCandidate{item}, commentWrappers(item)...) {
+		if match := rxCommentDepth.FindStringSubmatch(dom.ClassName(c.node)); match != nil {
+			depth, _ := strconv.Atoi(match[1])
+			return max(depth-1, 0), true
+		}
+	}
+	return 0, false
+}
+
+// commentWrappers returns the wrappers of the item, from the closest one.
+func commentWrappers(item *commentCandidate) []*commentCandidate {
+	var wrappers []*commentCandidate
+	for c := item.parent; c != nil; c = c.parent {
+		if c.item == item {
+			wrappers = append(wrappers, c)
+		}
+	}
+	return wrappers
+}
@@ -0,0 +1,208 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	nurl "net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Comments_WordPress(t *testing.T) {
+	rawHTML := `<html><body>
+	<article><p>` + strings.Repeat("Main article text. ", 20) + `</p></article>
+	<div id="comments" class="comments-area">
+		<h2 class="comments-title">2 thoughts on "Hello"</h2>
+		<ol class="comment-list">
+			<li id="comment-12" class="comment even thread-even depth-1 parent">
+				<article id="div-comment-12" class="comment-body">
+					<footer class="comment-meta">
+						<div class="comment-author vcard">
+							<img class="avatar" src="a.png"/>
+							<b class="fn">Jenny</b> <span class="says">says:</span>
+						</div>
+						<div class="comment-metadata">
+							<a href="https://example.org/hello/#comment-12"><time datetime="2021-05-10T08:30:00+00:00">May 10, 2021</time></a>
+						</div>
+					</footer>
+					<div class="comment-content"><p>First comment.</p><p>Second line.</p></div>
+					<div class="reply"><a class="comment-reply-link" href="#">Reply</a></div>
+				</article>
+				<ol class="children">
+					<li id="comment-13" class="comment odd alt depth-2">
+						<article id="div-comment-13" class="comment-body">
+							<footer class="comment-meta">
+								<div class="comment-author vcard"><b class="fn">John</b> <span class="says">says:</span></div>
+								<div class="comment-metadata"><a href="https://example.org/hello/#comment-13"><time datetime="2021-05-11T10:00:00+00:00">May 11, 2021</time></a></div>
+							</footer>
+							<div class="comment-content"><p>A reply.</p></div>
+						</article>
+					</li>
+				</ol>
+			</li>
+		</ol>
+	</div>
+	</body></html>`
+
+	result, err := ExtractDocument(docFromStr(rawHTML), zeroOpts)
+	assert.NoError(t, err)
+	assert.Len(t, result.Comments, 2)
+
+	first := result.Comments[0]
+	assert.Equal(t, "comment-12", first.ID)
+	assert.Equal(t, "", first.ParentID)
+	assert.Equal(t, 0, first.Depth)
+	assert.Equal(t, "Jenny", first.Author)
+	assert.Equal(t, time.Date(2021, 5, 10, 8, 30, 0, 0, time.UTC), first.Date.UTC())
+	assert.Equal(t, "https://example.org/hello/#comment-12", first.Permalink)
+	assert.Equal(t, "First comment.\nSecond line.", first.Text)
+	assert.Greater(t, first.Confidence, 0.9)
+
+	reply := result.Comments[1]
+	assert.Equal(t, "comment-13", reply.ID)
+	assert.Equal(t, "comment-12", reply.ParentID)
+	assert.Equal(t, 1, reply.Depth)
+	assert.Equal(t, "John", reply.Author)
+	assert.Equal(t, "A reply.", reply.Text)
+
+	// Structured comments are skipped along with the other comments
+	opts := zeroOpts
+	opts.ExcludeComments = true
+	result, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Comments)
+}
+
+func Test_Comments_Disqus(t *testing.T) {
+	rawHTML := `<html><body>
+	<div id="disqus_thread">
+		<ul id="dsq-comments">
+			<li class="post" id="post-100">
+				<div class="post-content">
+					<div class="post-byline"><span class="author">alice</span></div>
+					<div class="post-meta"><a class="time-ago" href="#comment-100" title="Monday, May 10, 2021">3 years ago</a></div>
+					<div class="post-message"><p>Nice write up, thanks!</p></div>
+				</div>
+				<ul class="children">
+					<li class="post" id="post-101">
+						<div class="post-content">
+							<div class="post-byline"><span class="author">bob</span></div>
+							<div class="post-message"><p>Agreed.</p></div>
+						</div>
+					</li>
+				</ul>
+			</li>
+			<li class="post" id="post-102">
+				<div class="post-content">
+					<div class="post-byline"><span class="author">carol</span></div>
+					<div class="post-message"><p>Second thread.</p></div>
+				</div>
+			</li>
+		</ul>
+	</div>
+	</body></html>`
+
+	opts := zeroOpts
+	opts.OriginalURL, _ = nurl.Parse("https://example.org/post")
+	comments := extractCommentThreads(docFromStr(rawHTML), opts)
+	assert.Len(t, comments, 3)
+
+	assert.Equal(t, "post-100", comments[0].ID)
+	assert.Equal(t, "alice", comments[0].Author)
+	assert.Equal(t, "Nice write up, thanks!", comments[0].Text)
+	assert.Equal(t, "https://example.org/post#post-100", comments[0].Permalink)
+
+	assert.Equal(t, "post-101", comments[1].ID)
+	assert.Equal(t, "post-100", comments[1].ParentID)
+	assert.Equal(t, 1, comments[1].Depth)
+	assert.Equal(t, "bob", comments[1].Author)
+	assert.Equal(t, "Agreed.", comments[1].Text)
+
+	assert.Equal(t, "", comments[2].ParentID)
+	assert.Equal(t, 0, comments[2].Depth)
+	assert.Equal(t, "carol", comments[2].Author)
+}
+
+func Test_Comments_Discourse(t *testing.T) {
+	rawHTML := `<html><body>
+	<div id="discourse-comments">
+		<article class="post" id="post-1">
+			<div class="author"><a class="username" href="/u/dave">dave</a></div>
+			<div class="cooked"><p>Posted from the forum.</p></div>
+			<div class="post-date"><span data-time="1620635400000">May 10</span></div>
+		</article>
+		<article class="post" id="post-2">
+			<div class="author"><a class="username" href="/u/erin">erin</a></div>
+			<div class="cooked"><p>Another reply.</p></div>
+		</article>
+	</div>
+	</body></html>`
+
+	comments := extractCommentThreads(docFromStr(rawHTML), zeroOpts)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "dave", comments[0].Author)
+	assert.Equal(t, "Posted from the forum.", comments[0].Text)
+	assert.Equal(t, time.Date(2021, 5, 10, 8, 30, 0, 0, time.UTC), comments[0].Date)
+	assert.Equal(t, "erin", comments[1].Author)
+	assert.Equal(t, 0, comments[1].Depth)
+}
+
+func Test_Comments_Generic(t *testing.T) {
+	// Comments without any known markup are detected from list items,
+	// with lower confidence
+	rawHTML := `<html><body>
+	<section class="article-comments">
+		<ul>
+			<li>Just a plain comment without any markup.</li>
+			<li>Another plain comment that has some text.</li>
+		</ul>
+	</section>
+	</body></html>`
+
+	comments := extractCommentThreads(docFromStr(rawHTML), zeroOpts)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "1", comments[0].ID)
+	assert.Equal(t, "", comments[0].Permalink)
+	assert.Equal(t, "Just a plain comment without any markup.", comments[0].Text)
+	assert.Less(t, comments[0].Confidence, 0.7)
+
+	// Fallback IDs only count the emitted comments
+	rawHTML = `<html><body>
+	<section class="article-comments">
+		<ul>
+			<li><span class="reply">Reply to start the discussion</span></li>
+			<li>Just a plain comment without any markup.</li>
+			<li>Another plain comment that has some text.</li>
+		</ul>
+	</section>
+	</body></html>`
+
+	comments = extractCommentThreads(docFromStr(rawHTML), zeroOpts)
+	if assert.Len(t, comments, 2) {
+		assert.Equal(t, "1", comments[0].ID)
+		assert.Equal(t, "2", comments[1].ID)
+	}
+
+	// No comment section at all
+	comments = extractCommentThreads(docFromStr(`<html><body><p>Nothing</p></body></html>`), zeroOpts)
+	assert.Empty(t, comments)
+}
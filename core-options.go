@@ -22,6 +22,7 @@
 package trafilatura
 
 import (
+	"net/http"
 	nurl "net/url"
 
 	"github.com/markusmobius/go-htmldate"
@@ -78,15 +79,63 @@ type Options struct {
 	// OriginalURL is the original URL of the page. Might be overwritten by URL in metadata.
 	OriginalURL *nurl.URL
 
+	// Encoding is the character encoding of the page, e.g. "shift_jis" or "windows-1251".
+	// If specified, automatic encoding detection in `Extract` will be skipped.
+	Encoding string
+
+	// ContentType is the value of `Content-Type` header from HTTP response of the page.
+	// It's used by `Extract` to detect the character encoding of the page.
+	ContentType string
+
+	// ResponseHeader is the header from HTTP response of the page. It's used to find
+	// the reuse reservations in `X-Robots-Tag`, `TDM-Reservation` and `TDM-Policy`.
+	ResponseHeader http.Header
+
 	// TargetLanguage is ISO 639-1 language code to make the extractor only process web page that
 	// uses the specified language.
 	TargetLanguage string
 
+	// TargetLanguages is list of ISO 639-1 language codes to make the extractor only process web
+	// page that uses one of the specified languages, e.g. for bilingual websites. It's merged with
+	// `TargetLanguage`. The page is only rejected when the detected language is not in this list
+	// and the detection confidence is at least `Config.MinLanguageConfidence`.
+	TargetLanguages []string
+
+	// LanguageCandidates is list of ISO 639-1 language codes that restrict the language detection,
+	// i.e. the detected language will always be one of these languages. Useful when the possible
+	// languages are already known, since it makes the detection more accurate.
+	LanguageCandidates []string
+
+	// DetectBlockLanguages specify whether to detect the language of each block in the extracted
+	// content. The detected language is put in `lang` attribute of the block and listed in
+	// `ExtractResult.BlockLanguages`, so mixed-language article can be split or filtered.
+	DetectBlockLanguages bool
+
 	// If EnableFallback is true, then whenever Trafilatura failed to extract a document,
 	// it will use algorithm from another package, i.e. Readability and Dom Distiller.
 	// This will make the extraction result more precise, but also a bit slower.
 	EnableFallback bool
 
+	// FallbackExtractors is the ordered list of external extractors that will be used
+	// to generate fallback candidates when `EnableFallback` is true. If nil, it will use
+	// `DefaultFallbackExtractors` i.e. Readability then Dom Distiller. `HydrationExtractor`
+	// and `JusTextExtractor` are opt-in, e.g. by appending them to `DefaultFallbackExtractors`.
+	// To disable all extractors and only use `FallbackCandidates`, set it into an empty slice.
+	FallbackExtractors []FallbackExtractor
+
+	// FallbackScorer is user specified function to decide whether a fallback candidate
+	// is better than the current extraction result. If nil, `DefaultFallbackScorer`
+	// will be used.
+	FallbackScorer FallbackScorer
+
+	// FallbackConcurrency is the max number of fallback extractors that run concurrently.
+	// If it's zero or one, the extractors will be run sequentially. Regardless of this
+	// value, the candidates are still evaluated in the same order as the extractors,
+	// so the final result is identical with the sequential run. Since the later
+	// extractors are started before the earlier candidates are evaluated, this
+	// trades extra CPU time for lower latency on multi core machine.
+	FallbackConcurrency int
+
 	// FallbackCandidates is user specified candidates that will be checked by Trafilatura
 	// when EnableFallback set to True. This is useful if user already use Readability
 	// and Dom Distiller before, or if user want to provide his own candidates. As mentioned
@@ -141,8 +190,65 @@ type Options struct {
 	// will be ignored.
 	HtmlDateOverride *htmldate.Result
 
+	// MetadataHints is user provided metadata that used to fill the fields that
+	// can't be found in the web page, e.g. title, author and date from a feed entry.
+	// Metadata that found in the page always takes precedence over the hints.
+	MetadataHints *Metadata
+
+	// RejectedPageStates is list of page states that will be rejected. If the state
+	// of web page is in this list, the extraction will fail with `*PageStateError`.
+	// By default no pages are rejected, and the state is only reported in result.
+	RejectedPageStates []PageState
+
+	// SkippedPageTypes is list of page types whose content won't be extracted. If the
+	// page is classified as one of these types with enough confidence (as specified in
+	// `Config.MinPageTypeConfidence`), the extraction will fail with `*PageTypeError`.
+	// Useful to only extract articles, e.g. by skipping listing and home pages.
+	SkippedPageTypes []PageType
+
+	// RejectedRights is list of reuse restrictions that will be rejected, e.g. to skip
+	// pages that opt out from AI training or reserve the TDM rights. If the page states
+	// any of them, the extraction will fail with `*RightsError`.
+	RejectedRights []RightsRestriction
+
+	// AllowedLicenses is list of SPDX license identifiers that allowed, e.g. "CC0-1.0"
+	// or "CC-BY-4.0". Identifier that ends with "*" is used as prefix, e.g. "CC-BY*".
+	// If specified, pages without allowed license will fail with `*RightsError`.
+	AllowedLicenses []string
+
 	// PruneSelector is the CSS selector to select nodes to be pruned before extraction.
 	PruneSelector string
+
+	// SiteProfile is the boilerplate profile of the website that learned using
+	// `SiteLearner`. The boilerplate listed in it will be pruned before extraction.
+	SiteProfile *SiteProfile
+
+	// KeepHidden specify whether to keep the content that never shown to readers,
+	// e.g. element with `hidden` attribute, `display:none` style or hidden utility
+	// classes. By default it's removed before extraction, since it's often used for
+	// SEO keyword stuffing and modal dialogs. Enable it to favor recall.
+	KeepHidden bool
+
+	// HiddenClasses is the utility classes that used to hide element, e.g. "sr-only"
+	// and "visually-hidden". If nil, the classes from `DefaultHiddenClasses` will be
+	// used. Element whose classes also show it on certain screen size (e.g. "hidden
+	// md:block") is not considered as hidden.
+	HiddenClasses []string
+
+	// LanguagePacks is the language specific phrases for finding boilerplate and
+	// cleaning the bylines, keyed by ISO 639-1 code. If nil, the built-in packs from
+	// `DefaultLanguagePacks` will be used, but only for removing the related content
+	// and read more links and for cleaning the bylines. The standalone phrases (e.g.
+	// "share" or "comments") are only filtered from the text when the packs are
+	// specified, e.g. by using `DefaultLanguagePacks()`. Each set of packs is compiled
+	// once then reused, so the packs must not be modified after they're used.
+	LanguagePacks map[string]*LanguagePack
+
+	// langMatcher is the compiled language pack for the page that being extracted.
+	langMatcher *languageMatcher
+
+	// jsonLd is the decoded JSON+LD of the page that being extracted.
+	jsonLd *_JsonLd
 }
 
 // Config is advanced setting to fine tune the extraction result.
@@ -160,6 +266,29 @@ type Config struct {
 	MinExtractedCommentSize int
 	MinOutputSize           int
 	MinOutputCommentSize    int
+
+	// Page classification setting
+	MinPageTypeConfidence float64
+
+	// Language detection setting. The detected language whose confidence is below
+	// MinLanguageConfidence won't be used to reject the page, nor put in metadata.
+	MinLanguageConfidence float64
+
+	// Languages is the size setting for specific languages, keyed by ISO 639-1 code.
+	// It's used when the page declares its language in HTML, or when there is only
+	// one target language. The non-zero values in it override the setting above.
+	Languages map[string]LanguageConfig
+}
+
+// LanguageConfig is the size setting that only used for pages in a specific language.
+// Zero value means the setting is taken from the main `Config`. Since the size of CJK
+// text is already weighted, usually it's only needed for fine tuning.
+type LanguageConfig struct {
+	MinDuplicateCheckSize   int
+	MinExtractedSize        int
+	MinExtractedCommentSize int
+	MinOutputSize           int
+	MinOutputCommentSize    int
 }
 
 // DefaultConfig returns the default configuration value.
@@ -173,18 +302,44 @@ func DefaultConfig() *Config {
 		MinExtractedCommentSize: 1,
 		MinOutputSize:           1,
 		MinOutputCommentSize:    1,
+
+		MinPageTypeConfidence: 0.5,
+	}
+}
+
+// forLanguage returns copy of the config whose size setting is adjusted for the
+// specified language. If there is no setting for the language, config is returned
+// as it is.
+func (cfg *Config) forLanguage(lang string) *Config {
+	langCfg, exist := cfg.Languages[lang]
+	if lang == "" || !exist {
+		return cfg
 	}
+
+	override := func(dst *int, value int) {
+		if value != 0 {
+			*dst = value
+		}
+	}
+
+	newCfg := *cfg
+	override(&newCfg.MinDuplicateCheckSize, langCfg.MinDuplicateCheckSize)
+	override(&newCfg.MinExtractedSize, langCfg.MinExtractedSize)
+	override(&newCfg.MinExtractedCommentSize, langCfg.MinExtractedCommentSize)
+	override(&newCfg.MinOutputSize, langCfg.MinOutputSize)
+	override(&newCfg.MinOutputCommentSize, langCfg.MinOutputCommentSize)
+	return &newCfg
 }
 
 // FallbackCandidates allows to specify a list of fallback candidates
 // in particular: Readability and Dom Distiller.
 type FallbackCandidates struct {
 	// Readability is the user specified extraction result from Go-Readability
-	// that will be used as fallback candidate.
+	// that will be used as fallback candidate, replacing `ReadabilityExtractor`.
 	Readability *html.Node
 
 	// Distiller is the user specified extraction result from Go-DomDistiller
-	// that will be used as fallback candidate.
+	// that will be used as fallback candidate, replacing `DistillerExtractor`.
 	Distiller *html.Node
 
 	// Others is list of the user specified extraction results taht will be used as
@@ -26,7 +26,8 @@ import (
 	"io"
 	nurl "net/url"
 	"os"
-	"unicode/utf8"
+	"slices"
+	"strings"
 
 	"github.com/andybalholm/cascadia"
 	"github.com/go-shiori/dom"
@@ -62,20 +63,67 @@ type ExtractResult struct {
 	// Will be empty if `ExcludeComments` in `Options` is set to true.
 	CommentsText string
 
+	// Comments is the comments that extracted as structured thread, with reply
+	// relation between them. Will be empty if `ExcludeComments` in `Options` is
+	// set to true.
+	Comments []Comment
+
+	// LiveUpdates is the timestamped updates of live blog, in the same order as in
+	// the page. Will be empty if the page is not a live blog.
+	LiveUpdates []LiveUpdate
+
+	// Pages is the boundaries of each page in the content, only available when
+	// the extraction is done using `ExtractPages` or `ExtractDocumentPages`.
+	Pages []PageSegment
+
+	// PageClass is the type of web page, e.g. article, listing or product page,
+	// along with how confident the classifier about it.
+	PageClass PageClass
+
+	// PageStatus is the state of web page, e.g. whether the content is hidden behind
+	// paywall or the page is actually an error page.
+	PageStatus PageStatus
+
+	// Language is the detected language of the content (or comments if it's longer),
+	// along with its script and detection confidence.
+	Language LanguageInfo
+
+	// BlockLanguages is the detected language of each block in the content. Only
+	// available when `DetectBlockLanguages` in `Options` is set to true.
+	BlockLanguages []BlockLanguage
+
 	// Metadata is the extracted metadata which taken from several sources i.e.
 	// <meta> tags, JSON+LD and OpenGraph scheme.
 	Metadata Metadata
+
+	// Encoding is the name of character encoding that used to decode the document.
+	// Only available when extraction is done using `Extract`, since document that
+	// passed to `ExtractDocument` is already decoded.
+	Encoding string
 }
 
-// Extract parses a reader and find the main readable content.
+// Extract parses a reader and find the main readable content. The content will be
+// converted into UTF-8 using the detected character encoding.
 func Extract(r io.Reader, opts Options) (*ExtractResult, error) {
+	// Read the content
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse HTML
-	doc, err := dom.Parse(r)
+	doc, encoding, err := parseHTML(content, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return ExtractDocument(doc, opts)
+	result, err := ExtractDocument(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Encoding = encoding
+	return result, nil
 }
 
 // ExtractDocument parses the specified document and find the main readable content.
@@ -85,12 +133,25 @@ func ExtractDocument(doc *html.Node, opts Options) (*ExtractResult, error) {
 		opts.Config = DefaultConfig()
 	}
 
+	// Adjust the size setting for the page language
+	pageLanguage := declaredLanguage(doc, opts)
+	if len(opts.Config.Languages) > 0 {
+		opts.Config = opts.Config.forLanguage(pageLanguage)
+	}
+
+	// Select the language pack for the page
+	opts.langMatcher = selectLanguageMatcher(doc, pageLanguage, opts)
+
+	// Decode JSON+LD once, since it's used by several extraction steps
+	opts.jsonLd = decodeJsonLd(doc, opts)
+
 	// Prepare cache for detecting text duplicate
 	cache := lru.NewCache(opts.Config.CacheSize)
 
 	// HTML language check
-	if opts.TargetLanguage != "" && !checkHtmlLanguage(doc, opts, false) {
-		return nil, fmt.Errorf("web page language is not %s", opts.TargetLanguage)
+	targets := targetLanguages(opts)
+	if len(targets) > 0 && !checkHtmlLanguage(doc, opts, false) {
+		return nil, fmt.Errorf("web page language is not %s", strings.Join(targets, ", "))
 	}
 
 	// Fetch metadata
@@ -111,6 +172,19 @@ func ExtractDocument(doc *html.Node, opts Options) (*ExtractResult, error) {
 		}
 	}
 
+	// Check if the page can be reused as user wants
+	if err := checkRights(metadata.Rights, opts); err != nil {
+		return nil, err
+	}
+
+	// Classify the page, and skip the extraction if user doesn't want it. It's done
+	// before the URL from metadata is used, since that URL is less trusted.
+	pageClass := ClassifyPage(doc, opts)
+	if slices.Contains(opts.SkippedPageTypes, pageClass.Type) &&
+		pageClass.Confidence >= opts.Config.MinPageTypeConfidence {
+		return nil, &PageTypeError{Class: pageClass}
+	}
+
 	// ADDITIONAL: If original URL never specified, and it found in metadata,
 	// use the one from metadata.
 	if opts.OriginalURL == nil && metadata.URL != "" {
@@ -120,21 +194,34 @@ func ExtractDocument(doc *html.Node, opts Options) (*ExtractResult, error) {
 		}
 	}
 
-	// Prune using selectors that user specified.
-	// No backup as this is completely full control of the user.
+	// Prune using selectors that user specified. The pruning is done on a copy,
+	// so the original document is kept untouched.
+	source := doc
 	if opts.PruneSelector != "" {
 		cssSelector, err := cascadia.ParseGroup(opts.PruneSelector)
 		if err == nil {
-			doc = pruneUnwantedNodes(doc, []selector.Rule{cssSelector.Match})
+			source = pruneUnwantedNodes(doc, []selector.Rule{cssSelector.Match})
 		}
 	}
 
-	// Backup document to make sure the original kept untouched
-	doc = dom.Clone(doc, true)
-	docBackup1 := dom.Clone(doc, true)
-	docBackup2 := dom.Clone(doc, true)
+	// Prune the boilerplate that learned from other pages of the same site
+	if opts.SiteProfile != nil {
+		source = pruneSiteBoilerplate(source, opts.SiteProfile)
+	}
+
+	// Remove the content that never shown to readers. The original is still used to
+	// detect the page state, since paywall often hides the rest of article.
+	originalSource := source
+	if !opts.KeepHidden {
+		source = pruneHiddenNodes(source, opts)
+	}
+
+	// Create working copy of the document. The source is never modified, so backup
+	// for fallback and baseline only created from it when they are actually needed.
+	doc = dom.Clone(source, true)
 
 	// Clean and convert HTML tags
+	pruneLocalizedSections(doc, opts.langMatcher)
 	docCleaning(doc, opts)
 	convertTags(doc, opts)
 
@@ -142,26 +229,31 @@ func ExtractDocument(doc *html.Node, opts Options) (*ExtractResult, error) {
 	var tmpComments string
 	var lenComments int
 	var commentsBody *html.Node
+	var comments []Comment
 
 	if !opts.ExcludeComments { // Comment is included
+		comments = extractCommentThreads(source, opts)
 		commentsBody, tmpComments = extractComments(doc, cache, opts)
-		lenComments = utf8.RuneCountInString(tmpComments)
+		lenComments = strLength(tmpComments)
 	} else if opts.Focus == FavorPrecision {
-		doc = pruneUnwantedNodes(doc, selector.RemovedComments)
+		pruneUnwantedNodesInPlace(doc, selector.RemovedComments)
 	}
 
+	// Extract updates of live blog
+	liveUpdates := extractLiveUpdates(source, opts)
+
 	// Extract content
 	postBody, tmpBodyText := extractContent(doc, cache, opts)
 
 	// Use fallback if necessary
 	if opts.EnableFallback {
-		postBody, tmpBodyText = compareExternalExtraction(docBackup1, postBody, opts)
+		postBody, tmpBodyText = compareExternalExtraction(source, postBody, opts)
 	}
 
 	// Rescue: try to use original/dirty tree
-	lenText := utf8.RuneCountInString(tmpBodyText)
+	lenText := strLength(tmpBodyText)
 	if lenText < opts.Config.MinExtractedSize && opts.Focus != FavorPrecision {
-		postBody, tmpBodyText = baseline(docBackup2)
+		postBody, tmpBodyText = baseline(dom.Clone(source, true))
 	}
 
 	// Tree size sanity check
@@ -177,12 +269,21 @@ func ExtractDocument(doc *html.Node, opts Options) (*ExtractResult, error) {
 		}
 	}
 
+	// Check whether the content is actually accessible
+	pageStatus := detectPageState(originalSource, metadata, tmpBodyText, opts)
+	if pageStatus.State != PageAccessible {
+		logDebug(opts, "page is %s: %s", pageStatus.State, opts.OriginalURL)
+		if slices.Contains(opts.RejectedPageStates, pageStatus.State) {
+			return nil, &PageStateError{Status: pageStatus}
+		}
+	}
+
 	// Size checks
 	if lenComments < opts.Config.MinExtractedCommentSize {
 		logDebug(opts, "not enough comments: %s", opts.OriginalURL)
 	}
 
-	lenText = utf8.RuneCountInString(tmpBodyText)
+	lenText = strLength(tmpBodyText)
 	if lenText < opts.Config.MinOutputSize && lenComments < opts.Config.MinOutputCommentSize {
 		return nil, fmt.Errorf("text and comments are not long enough: %d %d", lenText, lenComments)
 	}
@@ -193,27 +294,38 @@ func ExtractDocument(doc *html.Node, opts Options) (*ExtractResult, error) {
 	}
 
 	// Sanity check on language
-	lang := languageClassifier(tmpBodyText, tmpComments)
-	if opts.TargetLanguage != "" {
-		if lang != opts.TargetLanguage {
-			return nil, fmt.Errorf("wrong language, want %s got %s", opts.TargetLanguage, lang)
-		}
+	langInfo := languageClassifier(tmpBodyText, tmpComments, opts)
+	isConfident := langInfo.Confidence >= opts.Config.MinLanguageConfidence
+	if len(targets) > 0 && isConfident && !slices.Contains(targets, langInfo.Language) {
+		return nil, fmt.Errorf("wrong language, want %s got %s", strings.Join(targets, ", "), langInfo.Language)
 	}
 
 	// Put the captured language to metadata
-	if lang != "" {
-		metadata.Language = lang
+	if langInfo.Language != "" && isConfident {
+		metadata.Language = langInfo.Language
 	}
 
 	// Post cleaning
 	postCleaning(postBody)
 	postCleaning(commentsBody)
 
+	// Detect language of each block
+	var blockLanguages []BlockLanguage
+	if opts.DetectBlockLanguages {
+		blockLanguages = detectBlockLanguages(postBody, opts)
+	}
+
 	return &ExtractResult{
-		ContentNode:  postBody,
-		ContentText:  tmpBodyText,
-		CommentsNode: commentsBody,
-		CommentsText: tmpComments,
-		Metadata:     metadata,
+		ContentNode:    postBody,
+		ContentText:    tmpBodyText,
+		CommentsNode:   commentsBody,
+		CommentsText:   tmpComments,
+		Comments:       comments,
+		LiveUpdates:    liveUpdates,
+		PageClass:      pageClass,
+		PageStatus:     pageStatus,
+		Language:       langInfo,
+		BlockLanguages: blockLanguages,
+		Metadata:       metadata,
 	}, nil
 }
@@ -0,0 +1,212 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gogs/chardet"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Max number of bytes that scanned while looking for <meta> charset.
+const maxPrescanSize = 16 * 1024
+
+var byteOrderMarks = []struct {
+	bom     []byte
+	charset string
+}{
+	{[]byte{0xEF, 0xBB, 0xBF}, "utf-8"},
+	{[]byte{0xFE, 0xFF}, "utf-16be"},
+	{[]byte{0xFF, 0xFE}, "utf-16le"},
+}
+
+// ParseHTML reads the web page then parses it into HTML document. The content is
+// converted into UTF-8 using the same encoding detection as `Extract`, which uses
+// `Encoding` and `ContentType` in the options. It returns the parsed document and
+// the name of the character encoding that used to decode the content.
+func ParseHTML(r io.Reader, opts Options) (*html.Node, string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+	return parseHTML(content, opts)
+}
+
+// parseHTML decodes the raw content into UTF-8 then parse it into HTML document.
+// It returns the parsed document and the name of the character encoding that
+// used to decode the content.
+func parseHTML(content []byte, opts Options) (*html.Node, string, error) {
+	// Detect the encoding
+	enc, encName, err := detectEncoding(content, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Decode the content, then normalize it into NFC and remove soft hyphen
+	// since apparently it's useless in web.
+	softHyphen := runes.Predicate(func(r rune) bool { return r == '\u00AD' })
+	decoder := transform.Chain(enc.NewDecoder(), runes.Remove(softHyphen), norm.NFC)
+	r := transform.NewReader(bytes.NewReader(content), decoder)
+
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return doc, encName, nil
+}
+
+// detectEncoding looks for the character encoding of the raw content. The priority is
+// user specified encoding, byte order mark, charset in HTTP `Content-Type` header,
+// charset in <meta> tag then statistical sniffing as the last resort.
+func detectEncoding(content []byte, opts Options) (encoding.Encoding, string, error) {
+	// User specified encoding always win
+	if opts.Encoding != "" {
+		enc, name := charset.Lookup(opts.Encoding)
+		if enc == nil {
+			return nil, "", fmt.Errorf("unknown encoding %q", opts.Encoding)
+		}
+		return enc, name, nil
+	}
+
+	// Check the byte order mark
+	for _, bom := range byteOrderMarks {
+		if bytes.HasPrefix(content, bom.bom) {
+			enc, name := charset.Lookup(bom.charset)
+			logDebug(opts, "encoding %s detected from byte order mark", name)
+			return enc, name, nil
+		}
+	}
+
+	// Check the declared charset in HTTP header, then in <meta> tag. Web page is
+	// often served with wrong declaration (especially the default "iso-8859-1" from
+	// server), so if the content is valid UTF-8 we will ignore the declaration.
+	isUTF8 := utf8.Valid(content)
+
+	if name := charsetFromContentType(opts.ContentType); name != "" {
+		if enc, name := charset.Lookup(name); enc != nil && (!isUTF8 || name == "utf-8") {
+			logDebug(opts, "encoding %s detected from content type", name)
+			return enc, name, nil
+		}
+	}
+
+	if name := charsetFromMeta(content); name != "" {
+		// Since the meta is readable as ASCII, the document can't be UTF-16
+		if strings.HasPrefix(strings.ToLower(name), "utf-16") {
+			name = "utf-8"
+		}
+
+		if enc, name := charset.Lookup(name); enc != nil && (!isUTF8 || name == "utf-8") {
+			logDebug(opts, "encoding %s detected from meta tag", name)
+			return enc, name, nil
+		}
+	}
+
+	// Valid UTF-8 doesn't need any sniffing
+	if isUTF8 {
+		return encoding.Nop, "utf-8", nil
+	}
+
+	// Last resort, use statistical sniffing
+	res, err := chardet.NewHtmlDetector().DetectBest(content)
+	if err == nil {
+		if enc, name := charset.Lookup(res.Charset); enc != nil {
+			logDebug(opts, "encoding %s detected by sniffing (confidence %d)", name, res.Confidence)
+			return enc, name, nil
+		}
+	}
+
+	// Nothing found, fallback to Windows-1252 like browsers do
+	enc, name := charset.Lookup("windows-1252")
+	return enc, name, nil
+}
+
+func charsetFromContentType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(params["charset"])
+}
+
+// charsetFromMeta scans the beginning of document to find the charset that declared
+// in `<meta charset>` or `<meta http-equiv="content-type">`.
+func charsetFromMeta(content []byte) string {
+	if len(content) > maxPrescanSize {
+		content = content[:maxPrescanSize]
+	}
+
+	z := html.NewTokenizer(bytes.NewReader(content))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tagName, hasAttr := z.TagName()
+			switch string(tagName) {
+			case "body":
+				return ""
+			case "meta":
+			default:
+				continue
+			}
+
+			var httpEquiv, metaContent, metaCharset string
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				switch strings.ToLower(string(key)) {
+				case "charset":
+					metaCharset = string(val)
+				case "http-equiv":
+					httpEquiv = string(val)
+				case "content":
+					metaContent = string(val)
+				}
+			}
+
+			if metaCharset = strings.TrimSpace(metaCharset); metaCharset != "" {
+				return metaCharset
+			}
+
+			if strings.EqualFold(httpEquiv, "content-type") {
+				if name := charsetFromContentType(metaContent); name != "" {
+					return name
+				}
+			}
+		}
+	}
+}
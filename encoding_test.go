@@ -0,0 +1,115 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func Test_Encoding(t *testing.T) {
+	encode := func(enc encoding.Encoding, s string) []byte {
+		b, err := enc.NewEncoder().Bytes([]byte(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	extract := func(content []byte, opts Options) *ExtractResult {
+		result, err := Extract(bytes.NewReader(content), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	var opts Options
+	var result *ExtractResult
+	japaneseText := strings.Repeat("吾輩は猫である。名前はまだ無い。", 5)
+	chineseText := strings.Repeat("这是一个用于测试字符编码检测的中文段落。", 5)
+	russianText := "Москва - столица России, город федерального значения, административный центр " +
+		"Центрального федерального округа и центр Московской области, в состав которой не входит. " +
+		"Крупнейший по численности населения город России и её субъект - 13 миллионов жителей. " +
+		"Самый населённый из городов, полностью расположенных в Европе."
+
+	// Charset from <meta charset>
+	html := `<html><head><meta charset="Shift_JIS"></head><body><p>` + japaneseText + `</p></body></html>`
+	result = extract(encode(japanese.ShiftJIS, html), zeroOpts)
+	assert.Equal(t, "shift_jis", result.Encoding)
+	assert.Equal(t, japaneseText, result.ContentText)
+
+	// Charset from <meta http-equiv>
+	html = `<html><head><meta http-equiv="Content-Type" content="text/html; charset=gbk"></head>` +
+		`<body><p>` + chineseText + `</p></body></html>`
+	result = extract(encode(simplifiedchinese.GBK, html), zeroOpts)
+	assert.Equal(t, "gbk", result.Encoding)
+	assert.Equal(t, chineseText, result.ContentText)
+
+	// Charset from HTTP header
+	html = `<html><body><p>` + russianText + `</p></body></html>`
+	opts = zeroOpts
+	opts.ContentType = "text/html; charset=windows-1251"
+	result = extract(encode(charmap.Windows1251, html), opts)
+	assert.Equal(t, "windows-1251", result.Encoding)
+	assert.Equal(t, russianText, result.ContentText)
+
+	// Wrong declaration on valid UTF-8 content is ignored
+	opts = zeroOpts
+	opts.ContentType = "text/html; charset=iso-8859-1"
+	result = extract([]byte(html), opts)
+	assert.Equal(t, "utf-8", result.Encoding)
+	assert.Equal(t, russianText, result.ContentText)
+
+	// Byte order mark
+	result = extract(encode(unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), html), zeroOpts)
+	assert.Equal(t, "utf-16le", result.Encoding)
+	assert.Equal(t, russianText, result.ContentText)
+
+	// Statistical sniffing
+	result = extract(encode(charmap.Windows1251, html), zeroOpts)
+	assert.Equal(t, "windows-1251", result.Encoding)
+	assert.Equal(t, russianText, result.ContentText)
+
+	html = `<html><body><p>吾輩は猫である。名前はまだ無い。どこで生れたかとんと見当がつかぬ。` +
+		`何でも薄暗いじめじめした所でニャーニャー泣いていた事だけは記憶している。</p></body></html>`
+	result = extract(encode(japanese.ShiftJIS, html), zeroOpts)
+	assert.Equal(t, "shift_jis", result.Encoding)
+	assert.Contains(t, result.ContentText, "吾輩は猫である。")
+
+	// User override
+	html = `<html><body><p>` + russianText + `</p></body></html>`
+	opts = zeroOpts
+	opts.Encoding = "iso-8859-5"
+	result = extract(encode(charmap.ISO8859_5, html), opts)
+	assert.Equal(t, "iso-8859-5", result.Encoding)
+	assert.Equal(t, russianText, result.ContentText)
+
+	opts.Encoding = "not-an-encoding"
+	_, err := Extract(strings.NewReader(html), opts)
+	assert.Error(t, err)
+}
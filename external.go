@@ -22,19 +22,23 @@
 package trafilatura
 
 import (
+	"context"
 	"fmt"
-	"unicode/utf8"
 
 	"github.com/go-shiori/dom"
-	"github.com/go-shiori/go-readability"
-	distiller "github.com/markusmobius/go-domdistiller"
 	"github.com/markusmobius/go-trafilatura/internal/etree"
 	"github.com/markusmobius/go-trafilatura/internal/selector"
 	"golang.org/x/net/html"
+	"golang.org/x/sync/semaphore"
 )
 
 type _FallbackGenerator func() (string, *html.Node)
 
+type _FallbackCandidate struct {
+	title string
+	doc   *html.Node
+}
+
 var tagsToSanitize = sliceToMap(
 	"aside", "audio", "button", "fieldset", "figure", "footer", "iframe",
 	"input", "label", "link", "nav", "noindex", "noscript",
@@ -43,14 +47,15 @@ var tagsToSanitize = sliceToMap(
 
 // compareExternalExtraction decide whether to choose own or external extraction based on
 // a series of heuristics. In original Trafilatura, they use python-readability and justext,
-// while here we use go-readability and go-domdistiller. Since there are difference in
-// implementation between them, here we do it a bit differently compared to the original code.
+// while here we use go-readability, go-domdistiller and our own port of jusText. Since there
+// are difference in implementation between them, here we do it a bit differently compared
+// to the original code.
 //
 // In original Trafilatura, this function is named `compare_extraction`.
 func compareExternalExtraction(originalDoc, extractedDoc *html.Node, opts Options) (*html.Node, string) {
 	// Bypass for favor recall
 	extractedText := trim(etree.IterText(extractedDoc, " "))
-	lenExtracted := utf8.RuneCountInString(extractedText)
+	lenExtracted := strLength(extractedText)
 	if opts.Focus == FavorRecall && lenExtracted > opts.Config.MinExtractedSize*10 {
 		return extractedDoc, extractedText
 	}
@@ -62,34 +67,55 @@ func compareExternalExtraction(originalDoc, extractedDoc *html.Node, opts Option
 	}
 	logInfo(opts, "trying external extractor for url %q", originalUrl)
 
-	// Prior cleaning
-	cleanedDoc := dom.Clone(originalDoc, true)
-	if opts.Focus == FavorPrecision {
-		cleanedDoc = pruneUnwantedNodes(cleanedDoc, selector.OverallDiscardedContent)
+	// Prior cleaning. Since extractors must not modify the document, in most case
+	// it can be used as it is without cloning.
+	cleanedDoc := originalDoc
+	switch {
+	case opts.Focus == FavorPrecision:
+		cleanedDoc = pruneUnwantedNodes(originalDoc, selector.OverallDiscardedContent)
+	case opts.FallbackConcurrency > 1:
+		// Extractors might still run in background after this function returned,
+		// so give them their own copy.
+		cleanedDoc = dom.Clone(originalDoc, true)
 	}
 
-	// Process each candidate
-	for _, generator := range createFallbackGenerators(cleanedDoc, opts) {
+	// Prepare scorer
+	isUsable := opts.FallbackScorer
+	if isUsable == nil {
+		isUsable = candidateIsUsable
+	}
+
+	// Prepare candidate generators. Once a candidate is good enough the context
+	// will be cancelled, so the remaining generators can stop early.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	generators := createFallbackGenerators(ctx, cleanedDoc, opts)
+	candidateAt := runFallbackGenerators(ctx, generators, opts.FallbackConcurrency)
+
+	// Process each candidate, in the same order as generators
+	for i := range generators {
 		// Generate candidate, skip if empty
-		candidateTitle, candidateDoc := generator()
+		candidateTitle, candidateDoc := candidateAt(i)
 		if candidateDoc == nil {
 			continue
 		}
 
 		// Extract text from candidate
 		candidateText := trim(etree.IterText(candidateDoc, " "))
-		lenCandidate := utf8.RuneCountInString(candidateText)
+		lenCandidate := strLength(candidateText)
 		logInfo(opts, "comparison for %q: candidate %d vs extracted %d",
 			candidateTitle, lenCandidate, lenExtracted)
 
 		// Check if candidate is usable
-		if candidateIsUsable(candidateDoc, extractedDoc, lenCandidate, lenExtracted, opts) {
+		if isUsable(candidateDoc, extractedDoc, lenCandidate, lenExtracted, opts) {
 			extractedDoc, lenExtracted = candidateDoc, lenCandidate
 			logDebug(opts, "candidate %s is usable", candidateTitle)
 		}
 
 		if lenExtracted >= opts.Config.MinExtractedSize {
 			logDebug(opts, "candidate %s is used", candidateTitle)
+			cancel()
 			break
 		}
 	}
@@ -100,7 +126,7 @@ func compareExternalExtraction(originalDoc, extractedDoc *html.Node, opts Option
 	return extractedDoc, extractedText
 }
 
-func createFallbackGenerators(doc *html.Node, opts Options) []_FallbackGenerator {
+func createFallbackGenerators(ctx context.Context, doc *html.Node, opts Options) []_FallbackGenerator {
 	// Initial variables
 	var generators []_FallbackGenerator
 	var customCandidates []*html.Node
@@ -123,44 +149,100 @@ func createFallbackGenerators(doc *html.Node, opts Options) []_FallbackGenerator
 		})
 	}
 
-	// Next is Readability
-	readabilityTitle := "Readability"
-
-	if readabilityCandidate != nil {
-		generators = append(generators, func() (string, *html.Node) {
-			return readabilityTitle, readabilityCandidate
-		})
-	} else {
-		generators = append(generators, func() (string, *html.Node) {
-			result, _ := readability.FromDocument(doc, opts.OriginalURL)
-			return readabilityTitle, result.Node
-		})
+	// Next are the fallback extractors, by default Readability then Dom Distiller
+	extractors := opts.FallbackExtractors
+	if extractors == nil {
+		extractors = DefaultFallbackExtractors()
 	}
 
-	// Last is Dom Distiller
-	distillerTitle := "Dom Distiller"
+	for _, extractor := range extractors {
+		if extractor == nil {
+			continue
+		}
+
+		// If user already specified the result of built-in extractor, use it
+		var precomputed *html.Node
+		switch extractor.(type) {
+		case ReadabilityExtractor, *ReadabilityExtractor:
+			precomputed = readabilityCandidate
+		case DistillerExtractor, *DistillerExtractor:
+			precomputed = distillerCandidate
+		}
+
+		name := extractor.Name()
+		if precomputed != nil {
+			generators = append(generators, func() (string, *html.Node) {
+				return name, precomputed
+			})
+			continue
+		}
 
-	if distillerCandidate != nil {
-		generators = append(generators, func() (string, *html.Node) {
-			return distillerTitle, distillerCandidate
-		})
-	} else {
 		generators = append(generators, func() (string, *html.Node) {
-			clone := dom.Clone(doc, true)
-			result, _ := distiller.Apply(clone, &distiller.Options{
-				OriginalURL:    opts.OriginalURL,
-				SkipPagination: true})
-			if result == nil {
-				return "", nil
+			// Skip the extractor if a previous candidate is already good enough
+			if ctx.Err() != nil {
+				return name, nil
 			}
-			return distillerTitle, result.Node
+
+			result, err := extractor.Extract(ctx, doc, opts)
+			switch {
+			case ctx.Err() != nil:
+				logDebug(opts, "fallback extractor %s is cancelled", name)
+				return name, nil
+			case err != nil:
+				logWarn(opts, "fallback extractor %s failed: %v", name, err)
+				return name, nil
+			}
+			return name, result
 		})
 	}
 
 	return generators
 }
 
-// candidateIsUsable check if the fallback candidate is good enough to use as extraction result.
+// runFallbackGenerators returns function to fetch the candidate from generator at the
+// specified index. If concurrency is more than one, the generators will be run
+// concurrently in background, limited by the concurrency. Generators that haven't
+// been started when the context cancelled will be skipped.
+func runFallbackGenerators(ctx context.Context, generators []_FallbackGenerator, concurrency int) func(int) (string, *html.Node) {
+	// In sequential mode, generator is only run when its candidate is requested
+	if concurrency <= 1 || len(generators) <= 1 {
+		return func(i int) (string, *html.Node) {
+			return generators[i]()
+		}
+	}
+
+	// Prepare buffered channel for each generator, so the generator will never
+	// be blocked even when its result is not used.
+	results := make([]chan _FallbackCandidate, len(generators))
+	for i := range results {
+		results[i] = make(chan _FallbackCandidate, 1)
+	}
+
+	// Start the generators in order
+	go func() {
+		sem := semaphore.NewWeighted(int64(concurrency))
+		for i, generator := range generators {
+			if err := sem.Acquire(ctx, 1); err != nil {
+				for j := i; j < len(generators); j++ {
+					results[j] <- _FallbackCandidate{}
+				}
+				return
+			}
+
+			go func() {
+				defer sem.Release(1)
+				title, doc := generator()
+				results[i] <- _FallbackCandidate{title: title, doc: doc}
+			}()
+		}
+	}()
+
+	return func(i int) (string, *html.Node) {
+		candidate := <-results[i]
+		return candidate.title, candidate.doc
+	}
+}
+
 func candidateIsUsable(candidateDoc, extractedDoc *html.Node, lenCandidate, lenExtracted int, opts Options) bool {
 	var candidateUsable bool
 
@@ -182,7 +264,7 @@ func candidateIsUsable(candidateDoc, extractedDoc *html.Node, lenCandidate, lenE
 		var pTextLength int
 		for _, p := range extractedParagraphs {
 			pText := trim(etree.IterText(p, " "))
-			pTextLength += utf8.RuneCountInString(pText)
+			pTextLength += strLength(pText)
 		}
 
 		if pTextLength == 0 && lenCandidate > opts.Config.MinExtractedSize*2 {
@@ -0,0 +1,126 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"context"
+
+	"github.com/go-shiori/dom"
+	"github.com/go-shiori/go-readability"
+	distiller "github.com/markusmobius/go-domdistiller"
+	"golang.org/x/net/html"
+)
+
+// FallbackExtractor is an external content extractor that used to generate fallback
+// candidate when `EnableFallback` is true.
+type FallbackExtractor interface {
+	// Name returns the name of the extractor, used for logging.
+	Name() string
+
+	// Extract extracts the main content from the document. The document is shared
+	// between all extractors (which might run concurrently), so the implementation
+	// must not modify it and the returned node must not be part of it. If needed,
+	// clone it first using `dom.Clone`. Returns nil node if no content found. The
+	// context will be cancelled once a previous candidate is good enough.
+	Extract(ctx context.Context, doc *html.Node, opts Options) (*html.Node, error)
+}
+
+// FallbackScorer decides whether the candidate from fallback extractor is usable,
+// i.e. better than the current extraction result. The lengths are the number of
+// characters in text of the candidate and the current extraction result.
+type FallbackScorer func(candidateDoc, extractedDoc *html.Node, lenCandidate, lenExtracted int, opts Options) bool
+
+// ReadabilityExtractor is fallback extractor that uses Go-Readability.
+type ReadabilityExtractor struct{}
+
+// Name returns the name of the extractor.
+func (ReadabilityExtractor) Name() string {
+	return "Readability"
+}
+
+// Extract extracts the main content using Go-Readability. Go-Readability itself
+// can't be interrupted, so the context is only checked before and after it.
+func (ReadabilityExtractor) Extract(ctx context.Context, doc *html.Node, opts Options) (*html.Node, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := readability.FromDocument(doc, opts.OriginalURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return result.Node, nil
+}
+
+// DistillerExtractor is fallback extractor that uses Go-DomDistiller.
+type DistillerExtractor struct{}
+
+// Name returns the name of the extractor.
+func (DistillerExtractor) Name() string {
+	return "Dom Distiller"
+}
+
+// Extract extracts the main content using Go-DomDistiller. Go-DomDistiller itself
+// can't be interrupted, so the context is only checked between the stages.
+func (DistillerExtractor) Extract(ctx context.Context, doc *html.Node, opts Options) (*html.Node, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Dom Distiller modifies the document, so clone it first
+	clone := dom.Clone(doc, true)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := distiller.Apply(clone, &distiller.Options{
+		OriginalURL:    opts.OriginalURL,
+		SkipPagination: true})
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return result.Node, nil
+}
+
+// DefaultFallbackExtractors returns the fallback extractors that used when
+// `FallbackExtractors` in `Options` is nil, i.e. Readability then Dom Distiller.
+// `HydrationExtractor` and `JusTextExtractor` are not included to keep the fallback
+// result unchanged for existing callers, but they can be appended to the list.
+func DefaultFallbackExtractors() []FallbackExtractor {
+	return []FallbackExtractor{
+		ReadabilityExtractor{},
+		DistillerExtractor{},
+	}
+}
+
+// DefaultFallbackScorer is the scorer that used when `FallbackScorer` in `Options`
+// is nil. It's exported so custom scorer can use it as base decision.
+func DefaultFallbackScorer(candidateDoc, extractedDoc *html.Node, lenCandidate, lenExtracted int, opts Options) bool {
+	return candidateIsUsable(candidateDoc, extractedDoc, lenCandidate, lenExtracted, opts)
+}
@@ -0,0 +1,237 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"github.com/markusmobius/go-trafilatura/internal/etree"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/html"
+)
+
+type mockExtractor struct {
+	name   string
+	text   string
+	err    error
+	called *[]string
+}
+
+func (m mockExtractor) Name() string {
+	return m.name
+}
+
+func (m mockExtractor) Extract(ctx context.Context, doc *html.Node, opts Options) (*html.Node, error) {
+	if m.called != nil {
+		*m.called = append(*m.called, m.name)
+	}
+
+	if m.err != nil || m.text == "" {
+		return nil, m.err
+	}
+
+	body := etree.Element("body")
+	p := etree.SubElement(body, "p")
+	etree.SetText(p, m.text)
+	return body, nil
+}
+
+func Test_FallbackExtractors(t *testing.T) {
+	var called []string
+	longText := strings.Repeat("This is the text found by custom extractor. ", 20)
+	doc := docFromStr(`<html><body><p>Short text</p></body></html>`)
+
+	// Custom extractor is used, in the specified order
+	opts := Options{
+		EnableFallback: true,
+		FallbackExtractors: []FallbackExtractor{
+			mockExtractor{name: "failing", err: fmt.Errorf("failed"), called: &called},
+			mockExtractor{name: "custom", text: longText, called: &called},
+			mockExtractor{name: "never", text: "unused", called: &called},
+		},
+	}
+
+	result, err := ExtractDocument(dom.Clone(doc, true), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, trim(longText), result.ContentText)
+	assert.Equal(t, []string{"failing", "custom"}, called)
+
+	// Empty list disables all extractors
+	called = nil
+	opts.FallbackExtractors = []FallbackExtractor{}
+	opts.Config = zeroConfig
+	result, err = ExtractDocument(dom.Clone(doc, true), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "Short text", result.ContentText)
+	assert.Empty(t, called)
+
+	// Precomputed result replaces the built-in extractor
+	readabilityResult := etree.Element("body")
+	etree.SetText(etree.SubElement(readabilityResult, "p"), longText)
+
+	opts = Options{
+		EnableFallback:     true,
+		FallbackExtractors: []FallbackExtractor{ReadabilityExtractor{}},
+		FallbackCandidates: &FallbackCandidates{Readability: readabilityResult},
+	}
+
+	result, err = ExtractDocument(dom.Clone(doc, true), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, trim(longText), result.ContentText)
+
+	// Custom scorer which never accepts candidate
+	called = nil
+	opts = Options{
+		EnableFallback: true,
+		Config:         zeroConfig,
+		Focus:          FavorPrecision,
+		FallbackExtractors: []FallbackExtractor{
+			mockExtractor{name: "custom", text: longText, called: &called},
+		},
+		FallbackScorer: func(_, _ *html.Node, _, _ int, _ Options) bool {
+			return false
+		},
+	}
+
+	result, err = ExtractDocument(dom.Clone(doc, true), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "Short text", result.ContentText)
+	assert.Equal(t, []string{"custom"}, called)
+}
+
+type slowExtractor struct {
+	name      string
+	text      string
+	delay     time.Duration
+	running   *atomic.Int32
+	maxRunner *atomic.Int32
+	cancelled *atomic.Bool
+}
+
+func (s slowExtractor) Name() string {
+	return s.name
+}
+
+func (s slowExtractor) Extract(ctx context.Context, doc *html.Node, opts Options) (*html.Node, error) {
+	// Track the number of concurrent runners
+	n := s.running.Add(1)
+	defer s.running.Add(-1)
+	for {
+		max := s.maxRunner.Load()
+		if n <= max || s.maxRunner.CompareAndSwap(max, n) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		s.cancelled.Store(true)
+		return nil, ctx.Err()
+	}
+
+	body := etree.Element("body")
+	etree.SetText(etree.SubElement(body, "p"), s.text)
+	return body, nil
+}
+
+func Test_FallbackConcurrency(t *testing.T) {
+	var running, maxRunner atomic.Int32
+	var cancelled atomic.Bool
+	doc := docFromStr(`<html><body><p>Short text</p></body></html>`)
+
+	newExtractor := func(name, text string, delay time.Duration) FallbackExtractor {
+		return slowExtractor{
+			name:      name,
+			text:      text,
+			delay:     delay,
+			running:   &running,
+			maxRunner: &maxRunner,
+			cancelled: &cancelled,
+		}
+	}
+
+	// The slower first candidate is still the one selected, like in sequential run
+	firstText := strings.Repeat("First candidate text. ", 20)
+	secondText := strings.Repeat("Second candidate text which is even longer. ", 20)
+	opts := Options{
+		EnableFallback:      true,
+		FallbackConcurrency: 2,
+		FallbackExtractors: []FallbackExtractor{
+			newExtractor("first", firstText, 100*time.Millisecond),
+			newExtractor("second", secondText, 10*time.Millisecond),
+			newExtractor("third", "unused", 5*time.Second),
+		},
+	}
+
+	start := time.Now()
+	result, err := ExtractDocument(dom.Clone(doc, true), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, trim(firstText), result.ContentText)
+	assert.Equal(t, int32(2), maxRunner.Load())
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// Result is identical with sequential run
+	opts.FallbackConcurrency = 1
+	opts.FallbackExtractors = opts.FallbackExtractors[:2]
+	seqResult, err := ExtractDocument(dom.Clone(doc, true), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, seqResult.ContentText, result.ContentText)
+
+	// Remaining running candidate is cancelled once a candidate is good enough
+	cancelled.Store(false)
+	opts.FallbackConcurrency = 2
+	opts.FallbackExtractors = []FallbackExtractor{
+		newExtractor("first", firstText, 10*time.Millisecond),
+		newExtractor("slow", "unused", 5*time.Second),
+	}
+
+	start = time.Now()
+	result, err = ExtractDocument(dom.Clone(doc, true), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, trim(firstText), result.ContentText)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Eventually(t, cancelled.Load, time.Second, 10*time.Millisecond)
+}
+
+func Test_FallbackExtractorsCancelled(t *testing.T) {
+	doc := docFromStr(`<html><body><article><p>` + strings.Repeat("Some article text. ", 50) +
+		`</p></article></body></html>`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Built-in extractors stop early once the context is cancelled
+	for _, extractor := range []FallbackExtractor{
+		ReadabilityExtractor{},
+		DistillerExtractor{},
+		HydrationExtractor{},
+		JusTextExtractor{},
+	} {
+		result, err := extractor.Extract(ctx, doc, zeroOpts)
+		assert.Nil(t, result, extractor.Name())
+		assert.ErrorIs(t, err, context.Canceled, extractor.Name())
+	}
+}
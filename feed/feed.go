@@ -0,0 +1,395 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package feed parses web feeds in RSS (0.9x, 1.0 and 2.0), Atom and JSON Feed
+// (1.0 and 1.1) format into a common structure.
+package feed
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	betree "github.com/beevik/etree"
+	"golang.org/x/net/html/charset"
+)
+
+// Feed is the parsed content of a web feed.
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	Language    string
+	Updated     time.Time
+	Authors     []string
+	Entries     []Entry
+}
+
+// Entry is a single item that listed in the feed.
+type Entry struct {
+	// ID is the unique identifier of the entry, i.e. `<guid>` in RSS, `<id>` in Atom
+	// and `id` in JSON Feed. Might be empty for RSS feed.
+	ID    string
+	Link  string
+	Title string
+
+	// Summary is the short description of the entry. It might contain HTML.
+	Summary string
+
+	// Content is the full content of the entry in HTML, e.g. from `<content:encoded>`
+	// in RSS, `<content>` in Atom or `content_html` in JSON Feed. For JSON Feed that
+	// only has `content_text`, the text will be HTML escaped and wrapped in paragraphs.
+	Content string
+
+	Authors    []string
+	Published  time.Time
+	Updated    time.Time
+	Categories []string
+	Enclosures []Enclosure
+}
+
+// Enclosure is media file that attached to the entry.
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+// Key returns the value that can be used to identify the entry between fetches,
+// i.e. its ID or its link if ID doesn't exist.
+func (e Entry) Key() string {
+	if e.ID != "" {
+		return e.ID
+	}
+	return e.Link
+}
+
+// Date returns the publication date of the entry, or the last update date if
+// the publication date doesn't exist.
+func (e Entry) Date() time.Time {
+	if !e.Published.IsZero() {
+		return e.Published
+	}
+	return e.Updated
+}
+
+// Date formats that used in feeds. RSS uses RFC 822 with a lot of variations in the
+// wild, while Atom and JSON Feed use RFC 3339.
+var dateFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"Mon, 2 Jan 2006 15:04 MST",
+	"Mon, 2 January 2006 15:04:05 -0700",
+	"Mon, 2 January 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+}
+
+// Parse parses feed from the reader. The format is detected automatically from
+// its content, i.e. JSON Feed if it's a JSON object and XML feed otherwise.
+func Parse(r io.Reader) (*Feed, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := bytes.TrimLeft(content, " \t\r\n\ufeff")
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return ParseJSON(bytes.NewReader(trimmed))
+	}
+
+	return ParseXML(bytes.NewReader(content))
+}
+
+// ParseDate parses date in format that commonly used in feeds.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.Join(strings.Fields(s), " ")
+	for _, format := range dateFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// ParseXML parses RSS, RDF or Atom feed.
+func ParseXML(r io.Reader) (*Feed, error) {
+	doc := betree.NewDocument()
+	doc.ReadSettings.CharsetReader = charset.NewReaderLabel
+	if _, err := doc.ReadFrom(r); err != nil {
+		return nil, err
+	}
+
+	root := doc.Root()
+	if root == nil {
+		return nil, fmt.Errorf("feed is empty")
+	}
+
+	switch root.Tag {
+	case "feed":
+		return parseAtom(root), nil
+	case "rss", "RDF":
+		return parseRSS(root), nil
+	default:
+		return nil, fmt.Errorf("unknown feed root <%s>", root.FullTag())
+	}
+}
+
+// parseRSS parses RSS 2.0 and RDF (RSS 0.9 and 1.0) feed. In RSS 2.0 the items are
+// inside `<channel>`, while in RDF they are the siblings of `<channel>`.
+func parseRSS(root *betree.Element) *Feed {
+	feed := &Feed{}
+
+	var itemElements []*betree.Element
+	for _, elem := range root.ChildElements() {
+		switch elem.Tag {
+		case "channel":
+			parseRSSChannel(elem, feed)
+			itemElements = append(itemElements, elem.SelectElements("item")...)
+		case "item":
+			itemElements = append(itemElements, elem)
+		}
+	}
+
+	for _, elem := range itemElements {
+		if entry, ok := parseRSSItem(elem); ok {
+			feed.Entries = append(feed.Entries, entry)
+		}
+	}
+
+	return feed
+}
+
+func parseRSSChannel(elem *betree.Element, feed *Feed) {
+	for _, child := range elem.ChildElements() {
+		text := strings.TrimSpace(child.Text())
+		switch child.FullTag() {
+		case "title":
+			feed.Title = text
+		case "link":
+			feed.Link = text
+		case "description":
+			feed.Description = text
+		case "language", "dc:language":
+			feed.Language = text
+		case "lastBuildDate", "pubDate", "dc:date":
+			if feed.Updated.IsZero() {
+				feed.Updated, _ = ParseDate(text)
+			}
+		case "managingEditor", "dc:creator":
+			feed.Authors = appendUnique(feed.Authors, text)
+		}
+	}
+}
+
+func parseRSSItem(elem *betree.Element) (Entry, bool) {
+	var entry Entry
+	for _, child := range elem.ChildElements() {
+		text := strings.TrimSpace(child.Text())
+		switch child.FullTag() {
+		case "guid":
+			entry.ID = text
+			isPermalink := child.SelectAttrValue("isPermaLink", "true") != "false"
+			if entry.Link == "" && isPermalink && isHttpURL(text) {
+				entry.Link = text
+			}
+		case "link":
+			if text != "" {
+				entry.Link = text
+			}
+		case "title":
+			entry.Title = text
+		case "description":
+			entry.Summary = text
+		case "content:encoded":
+			entry.Content = text
+		case "author", "dc:creator":
+			entry.Authors = appendUnique(entry.Authors, text)
+		case "pubDate", "dc:date":
+			entry.Published, _ = ParseDate(text)
+		case "dc:modified", "atom:updated":
+			entry.Updated, _ = ParseDate(text)
+		case "category", "dc:subject":
+			entry.Categories = appendUnique(entry.Categories, text)
+		case "enclosure", "media:content":
+			if enclosure, ok := parseEnclosure(child, "url", "length", "fileSize"); ok {
+				entry.Enclosures = append(entry.Enclosures, enclosure)
+			}
+		}
+	}
+
+	// RDF items use `rdf:about` as identifier
+	if entry.ID == "" {
+		entry.ID = elem.SelectAttrValue("rdf:about", "")
+	}
+
+	return entry, entry.Link != "" || entry.ID != ""
+}
+
+func parseAtom(root *betree.Element) *Feed {
+	feed := &Feed{}
+	feed.Language = root.SelectAttrValue("xml:lang", "")
+
+	for _, child := range root.ChildElements() {
+		text := strings.TrimSpace(child.Text())
+		switch child.Tag {
+		case "title":
+			feed.Title = text
+		case "subtitle":
+			feed.Description = text
+		case "link":
+			if href, rel := atomLink(child); href != "" && rel == "alternate" {
+				feed.Link = href
+			}
+		case "updated":
+			feed.Updated, _ = ParseDate(text)
+		case "author":
+			feed.Authors = appendUnique(feed.Authors, atomPersonName(child))
+		case "entry":
+			if entry, ok := parseAtomEntry(child); ok {
+				feed.Entries = append(feed.Entries, entry)
+			}
+		}
+	}
+
+	return feed
+}
+
+func parseAtomEntry(elem *betree.Element) (Entry, bool) {
+	var entry Entry
+	for _, child := range elem.ChildElements() {
+		text := strings.TrimSpace(child.Text())
+		switch child.Tag {
+		case "id":
+			entry.ID = text
+		case "link":
+			href, rel := atomLink(child)
+			switch {
+			case href == "":
+			case rel == "alternate" && entry.Link == "":
+				entry.Link = href
+			case rel == "enclosure":
+				if enclosure, ok := parseEnclosure(child, "href", "length"); ok {
+					entry.Enclosures = append(entry.Enclosures, enclosure)
+				}
+			}
+		case "title":
+			entry.Title = text
+		case "summary":
+			entry.Summary = atomText(child)
+		case "content":
+			entry.Content = atomText(child)
+		case "author", "contributor":
+			entry.Authors = appendUnique(entry.Authors, atomPersonName(child))
+		case "published", "issued":
+			entry.Published, _ = ParseDate(text)
+		case "updated", "modified":
+			entry.Updated, _ = ParseDate(text)
+		case "category":
+			term := child.SelectAttrValue("label", child.SelectAttrValue("term", ""))
+			entry.Categories = appendUnique(entry.Categories, strings.TrimSpace(term))
+		}
+	}
+
+	return entry, entry.Link != "" || entry.ID != ""
+}
+
+// atomLink returns the href and relation type of Atom's `<link>`.
+func atomLink(elem *betree.Element) (string, string) {
+	href := strings.TrimSpace(elem.SelectAttrValue("href", ""))
+	rel := strings.TrimSpace(elem.SelectAttrValue("rel", "alternate"))
+	return href, rel
+}
+
+func atomPersonName(elem *betree.Element) string {
+	if name := elem.SelectElement("name"); name != nil {
+		return strings.TrimSpace(name.Text())
+	}
+	return strings.TrimSpace(elem.Text())
+}
+
+// atomText returns the content of Atom's text construct. For XHTML content, the
+// markup inside the wrapper `<div>` is serialized back into string.
+func atomText(elem *betree.Element) string {
+	if elem.SelectAttrValue("type", "text") != "xhtml" {
+		return strings.TrimSpace(elem.Text())
+	}
+
+	container := elem
+	if div := elem.SelectElement("div"); div != nil {
+		container = div
+	}
+
+	// Move the copied tokens into new document, so they can be serialized
+	doc := betree.NewDocument()
+	copied := container.Copy()
+	for _, token := range slices.Clone(copied.Child) {
+		doc.AddChild(token)
+	}
+
+	str, _ := doc.WriteToString()
+	return strings.TrimSpace(str)
+}
+
+func parseEnclosure(elem *betree.Element, urlAttr string, lengthAttrs ...string) (Enclosure, bool) {
+	enclosure := Enclosure{
+		URL:  strings.TrimSpace(elem.SelectAttrValue(urlAttr, "")),
+		Type: strings.TrimSpace(elem.SelectAttrValue("type", "")),
+	}
+
+	for _, attr := range lengthAttrs {
+		if value := elem.SelectAttrValue(attr, ""); value != "" {
+			enclosure.Length, _ = strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			break
+		}
+	}
+
+	return enclosure, enclosure.URL != ""
+}
+
+func appendUnique(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
+
+func isHttpURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -0,0 +1,247 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feed
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const rssFeedSample = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/">
+	<channel>
+		<title>Example Blog</title>
+		<link>https://example.org/</link>
+		<description>Just an example</description>
+		<language>en-us</language>
+		<lastBuildDate>Tue, 11 May 2021 09:00:00 +0000</lastBuildDate>
+		<item>
+			<title>First &amp; Foremost</title>
+			<link>https://example.org/first</link>
+			<guid isPermaLink="false">post-1</guid>
+			<pubDate>Mon, 10 May 2021 08:30:00 GMT</pubDate>
+			<dc:creator><![CDATA[Jenny Smith]]></dc:creator>
+			<category>News</category>
+			<category>Tech</category>
+			<description>Short summary</description>
+			<content:encoded><![CDATA[<p>Full <b>content</b></p>]]></content:encoded>
+			<enclosure url="https://example.org/first.mp3" type="audio/mpeg" length="1234"/>
+		</item>
+		<item>
+			<guid>https://example.org/second</guid>
+			<pubDate>Sun, 9 May 2021 7:00:00 +0200</pubDate>
+		</item>
+		<item>
+			<title>No link</title>
+		</item>
+	</channel>
+</rss>`
+
+const rdfFeedSample = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlns="http://purl.org/rss/1.0/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/">
+	<channel rdf:about="https://example.org/">
+		<title>Example RDF</title>
+	</channel>
+	<item rdf:about="https://example.org/rdf-item">
+		<title>RDF Item</title>
+		<link>https://example.org/rdf-item</link>
+		<dc:date>2021-05-10T08:30:00Z</dc:date>
+	</item>
+</rdf:RDF>`
+
+const atomFeedSample = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="de">
+	<title>Example Atom</title>
+	<link href="https://example.org/atom" rel="self"/>
+	<link href="https://example.org/"/>
+	<updated>2021-05-11T09:00:00Z</updated>
+	<author><name>Max Mustermann</name></author>
+	<entry>
+		<title>Atom Entry</title>
+		<link rel="alternate" href="https://example.org/atom-entry"/>
+		<link rel="enclosure" href="https://example.org/video.mp4" type="video/mp4" length="42"/>
+		<id>urn:uuid:1225c695</id>
+		<published>2021-05-10T08:30:00+02:00</published>
+		<updated>2021-05-10T10:00:00+02:00</updated>
+		<category term="tech" label="Technology"/>
+		<summary>Plain summary</summary>
+		<content type="html">&lt;p&gt;Escaped &lt;i&gt;HTML&lt;/i&gt;&lt;/p&gt;</content>
+	</entry>
+	<entry>
+		<title>XHTML Entry</title>
+		<link href="https://example.org/xhtml-entry"/>
+		<id>urn:uuid:1225c696</id>
+		<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Inline <b>markup</b></p></div></content>
+	</entry>
+</feed>`
+
+const jsonFeedSample = `{
+	"version": "https://jsonfeed.org/version/1.1",
+	"title": "Example JSON",
+	"home_page_url": "https://example.org/",
+	"language": "en",
+	"authors": [{"name": "Jenny Smith"}],
+	"items": [
+		{
+			"id": "1",
+			"url": "https://example.org/json-1",
+			"title": "JSON Item",
+			"content_html": "<p>Hello</p>",
+			"date_published": "2021-05-10T08:30:00Z",
+			"tags": ["news"],
+			"authors": [{"name": "John Doe"}],
+			"attachments": [{"url": "https://example.org/a.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 99}]
+		},
+		{
+			"id": 2,
+			"external_url": "https://example.com/json-2",
+			"content_text": "First line\nsecond line\n\nA <second> paragraph"
+		}
+	]
+}`
+
+func Test_Parse_RSS(t *testing.T) {
+	feed, err := Parse(strings.NewReader(rssFeedSample))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Example Blog", feed.Title)
+	assert.Equal(t, "https://example.org/", feed.Link)
+	assert.Equal(t, "Just an example", feed.Description)
+	assert.Equal(t, "en-us", feed.Language)
+	assert.Equal(t, "2021-05-11", feed.Updated.Format(time.DateOnly))
+	assert.Len(t, feed.Entries, 2)
+
+	first := feed.Entries[0]
+	assert.Equal(t, "post-1", first.ID)
+	assert.Equal(t, "post-1", first.Key())
+	assert.Equal(t, "https://example.org/first", first.Link)
+	assert.Equal(t, "First & Foremost", first.Title)
+	assert.Equal(t, "Short summary", first.Summary)
+	assert.Equal(t, "<p>Full <b>content</b></p>", first.Content)
+	assert.Equal(t, []string{"Jenny Smith"}, first.Authors)
+	assert.Equal(t, []string{"News", "Tech"}, first.Categories)
+	assert.Equal(t, time.Date(2021, 5, 10, 8, 30, 0, 0, time.UTC), first.Published.UTC())
+	assert.Equal(t, []Enclosure{{URL: "https://example.org/first.mp3", Type: "audio/mpeg", Length: 1234}}, first.Enclosures)
+
+	// Permalink GUID is used as link
+	second := feed.Entries[1]
+	assert.Equal(t, "https://example.org/second", second.Link)
+	assert.Equal(t, time.Date(2021, 5, 9, 5, 0, 0, 0, time.UTC), second.Date().UTC())
+}
+
+func Test_Parse_RDF(t *testing.T) {
+	feed, err := Parse(strings.NewReader(rdfFeedSample))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Example RDF", feed.Title)
+	assert.Len(t, feed.Entries, 1)
+	assert.Equal(t, "https://example.org/rdf-item", feed.Entries[0].ID)
+	assert.Equal(t, "https://example.org/rdf-item", feed.Entries[0].Link)
+	assert.Equal(t, "2021-05-10", feed.Entries[0].Published.Format(time.DateOnly))
+}
+
+func Test_Parse_Atom(t *testing.T) {
+	feed, err := Parse(strings.NewReader(atomFeedSample))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Example Atom", feed.Title)
+	assert.Equal(t, "https://example.org/", feed.Link)
+	assert.Equal(t, "de", feed.Language)
+	assert.Equal(t, []string{"Max Mustermann"}, feed.Authors)
+	assert.Len(t, feed.Entries, 2)
+
+	entry := feed.Entries[0]
+	assert.Equal(t, "urn:uuid:1225c695", entry.ID)
+	assert.Equal(t, "https://example.org/atom-entry", entry.Link)
+	assert.Equal(t, "Plain summary", entry.Summary)
+	assert.Equal(t, "<p>Escaped <i>HTML</i></p>", entry.Content)
+	assert.Equal(t, []string{"Technology"}, entry.Categories)
+	assert.Equal(t, time.Date(2021, 5, 10, 6, 30, 0, 0, time.UTC), entry.Published.UTC())
+	assert.Equal(t, time.Date(2021, 5, 10, 8, 0, 0, 0, time.UTC), entry.Updated.UTC())
+	assert.Equal(t, []Enclosure{{URL: "https://example.org/video.mp4", Type: "video/mp4", Length: 42}}, entry.Enclosures)
+
+	entry = feed.Entries[1]
+	assert.Equal(t, "https://example.org/xhtml-entry", entry.Link)
+	assert.Equal(t, "<p>Inline <b>markup</b></p>", entry.Content)
+}
+
+func Test_Parse_JSON(t *testing.T) {
+	feed, err := Parse(strings.NewReader(jsonFeedSample))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Example JSON", feed.Title)
+	assert.Equal(t, "https://example.org/", feed.Link)
+	assert.Equal(t, "en", feed.Language)
+	assert.Equal(t, []string{"Jenny Smith"}, feed.Authors)
+	assert.Len(t, feed.Entries, 2)
+
+	first := feed.Entries[0]
+	assert.Equal(t, "1", first.ID)
+	assert.Equal(t, "https://example.org/json-1", first.Link)
+	assert.Equal(t, "JSON Item", first.Title)
+	assert.Equal(t, "<p>Hello</p>", first.Content)
+	assert.Equal(t, []string{"John Doe"}, first.Authors)
+	assert.Equal(t, []string{"news"}, first.Categories)
+	assert.Equal(t, "2021-05-10", first.Published.Format(time.DateOnly))
+	assert.Equal(t, []Enclosure{{URL: "https://example.org/a.mp3", Type: "audio/mpeg", Length: 99}}, first.Enclosures)
+
+	// Numeric ID, external URL and plain text content
+	second := feed.Entries[1]
+	assert.Equal(t, "2", second.ID)
+	assert.Equal(t, "https://example.com/json-2", second.Link)
+	assert.Equal(t, "<p>First line<br/>second line</p><p>A &lt;second&gt; paragraph</p>", second.Content)
+}
+
+func Test_Parse_Invalid(t *testing.T) {
+	_, err := Parse(strings.NewReader(`<html><body>Not a feed</body></html>`))
+	assert.Error(t, err)
+
+	_, err = Parse(strings.NewReader(`{"title": "no version"}`))
+	assert.Error(t, err)
+
+	_, err = Parse(strings.NewReader(``))
+	assert.Error(t, err)
+}
+
+func Test_ParseDate(t *testing.T) {
+	expected := time.Date(2021, 5, 10, 8, 30, 0, 0, time.UTC)
+	for _, str := range []string{
+		"2021-05-10T08:30:00Z",
+		"2021-05-10T08:30:00.000+00:00",
+		"Mon, 10 May 2021 08:30:00 +0000",
+		"Mon, 10 May 2021 08:30:00 GMT",
+		"Mon,  10 May 2021 08:30:00 +0000",
+		"10 May 2021 08:30:00 +0000",
+		"Mon, 10 May 2021 08:30 +0000",
+	} {
+		date, err := ParseDate(str)
+		assert.NoError(t, err, str)
+		assert.Equal(t, expected, date.UTC(), str)
+	}
+
+	_, err := ParseDate("yesterday")
+	assert.Error(t, err)
+}
@@ -0,0 +1,174 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// jsonFeed is the structure of JSON Feed as described in <https://jsonfeed.org/version/1.1>.
+// Fields from version 1.0 (e.g. the singular `author`) are kept for compatibility.
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url"`
+	Description string       `json:"description"`
+	Language    string       `json:"language"`
+	Author      *jsonAuthor  `json:"author"`
+	Authors     []jsonAuthor `json:"authors"`
+	Items       []jsonItem   `json:"items"`
+}
+
+type jsonItem struct {
+	ID            jsonString       `json:"id"`
+	URL           string           `json:"url"`
+	ExternalURL   string           `json:"external_url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html"`
+	ContentText   string           `json:"content_text"`
+	Summary       string           `json:"summary"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Author        *jsonAuthor      `json:"author"`
+	Authors       []jsonAuthor     `json:"authors"`
+	Tags          []string         `json:"tags"`
+	Attachments   []jsonAttachment `json:"attachments"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes"`
+}
+
+// jsonString is string that also accepts number, since some feeds in the wild
+// use numeric item ID even though the spec requires string.
+type jsonString string
+
+func (s *jsonString) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = jsonString(str)
+		return nil
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return err
+	}
+
+	*s = jsonString(num.String())
+	return nil
+}
+
+// ParseJSON parses feed in JSON Feed format.
+func ParseJSON(r io.Reader) (*Feed, error) {
+	var jf jsonFeed
+	if err := json.NewDecoder(r).Decode(&jf); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(jf.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("unknown json feed version %q", jf.Version)
+	}
+
+	feed := &Feed{
+		Title:       strings.TrimSpace(jf.Title),
+		Link:        strings.TrimSpace(jf.HomePageURL),
+		Description: strings.TrimSpace(jf.Description),
+		Language:    strings.TrimSpace(jf.Language),
+		Authors:     jsonAuthorNames(jf.Author, jf.Authors),
+	}
+
+	for _, item := range jf.Items {
+		entry := Entry{
+			ID:         strings.TrimSpace(string(item.ID)),
+			Link:       strings.TrimSpace(item.URL),
+			Title:      strings.TrimSpace(item.Title),
+			Summary:    strings.TrimSpace(item.Summary),
+			Content:    strings.TrimSpace(item.ContentHTML),
+			Authors:    jsonAuthorNames(item.Author, item.Authors),
+			Categories: item.Tags,
+		}
+
+		if entry.Link == "" {
+			entry.Link = strings.TrimSpace(item.ExternalURL)
+		}
+
+		if entry.Content == "" && item.ContentText != "" {
+			entry.Content = textToHTML(item.ContentText)
+		}
+
+		entry.Published, _ = ParseDate(item.DatePublished)
+		entry.Updated, _ = ParseDate(item.DateModified)
+
+		for _, attachment := range item.Attachments {
+			if attachment.URL != "" {
+				entry.Enclosures = append(entry.Enclosures, Enclosure{
+					URL:    attachment.URL,
+					Type:   attachment.MimeType,
+					Length: attachment.SizeInBytes,
+				})
+			}
+		}
+
+		if entry.Link != "" || entry.ID != "" {
+			feed.Entries = append(feed.Entries, entry)
+		}
+	}
+
+	return feed, nil
+}
+
+func jsonAuthorNames(author *jsonAuthor, authors []jsonAuthor) []string {
+	var names []string
+	if author != nil {
+		names = appendUnique(names, strings.TrimSpace(author.Name))
+	}
+
+	for _, a := range authors {
+		names = appendUnique(names, strings.TrimSpace(a.Name))
+	}
+
+	return names
+}
+
+// textToHTML converts plain text into HTML paragraphs, separated by blank lines.
+func textToHTML(text string) string {
+	var sb strings.Builder
+	for paragraph := range strings.SplitSeq(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		paragraph = html.EscapeString(paragraph)
+		paragraph = strings.ReplaceAll(paragraph, "\n", "<br/>")
+		sb.WriteString("<p>" + paragraph + "</p>")
+	}
+	return sb.String()
+}
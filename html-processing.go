@@ -137,18 +137,33 @@ func pruneHTML(doc *html.Node, opts Options) {
 	}
 }
 
-// pruneUnwantedNodes prune the HTML tree by removing unwanted sections.
+// pruneUnwantedNodes prune a copy of the HTML tree by removing unwanted sections.
+// The original tree is kept untouched. If backup is enabled and the pruning removes
+// too much text, the copy of the original tree will be returned instead.
 func pruneUnwantedNodes(tree *html.Node, queries []selector.Rule, withBackup ...bool) *html.Node {
 	var oldLen int
-	var backup *html.Node
+	original := tree
 	backupEnabled := len(withBackup) > 0 && withBackup[0]
 
 	tree = dom.Clone(tree, true)
 	if backupEnabled {
-		backup = dom.Clone(tree, true)
 		oldLen = utf8.RuneCountInString(dom.TextContent(tree))
 	}
 
+	pruneUnwantedNodesInPlace(tree, queries)
+
+	if backupEnabled {
+		newLen := utf8.RuneCountInString(dom.TextContent(tree))
+		if newLen <= oldLen/7 {
+			return dom.Clone(original, true)
+		}
+	}
+
+	return tree
+}
+
+// pruneUnwantedNodesInPlace is like `pruneUnwantedNodes` but modifies the tree directly.
+func pruneUnwantedNodesInPlace(tree *html.Node, queries []selector.Rule) {
 	for _, query := range queries {
 		subElements := selector.QueryAll(tree, query)
 		for i := len(subElements) - 1; i >= 0; i-- {
@@ -176,15 +191,6 @@ func pruneUnwantedNodes(tree *html.Node, queries []selector.Rule, withBackup ...
 			etree.Remove(subElement)
 		}
 	}
-
-	if backupEnabled {
-		newLen := utf8.RuneCountInString(dom.TextContent(tree))
-		if newLen <= oldLen/7 {
-			return backup
-		}
-	}
-
-	return tree
 }
 
 // handleTextNode converts, formats and probes potential text elements.
@@ -230,7 +236,7 @@ func handleTextNode(node *html.Node, cache *lru.Cache, fixComments, preserveSpac
 		etree.SetTail(node, tail)
 	}
 
-	if text == "" && textFilter(node) {
+	if text == "" && textFilter(node, opts) {
 		return nil
 	}
 
@@ -253,7 +259,7 @@ func linkDensityTest(element *html.Node, opts Options) ([]*html.Node, bool) {
 
 	// Get element text
 	text := trim(dom.TextContent(element))
-	textLength := utf8.RuneCountInString(text)
+	textLength := strLength(text)
 
 	// Shortcut
 	if nLinks == 1 {
@@ -263,7 +269,7 @@ func linkDensityTest(element *html.Node, opts Options) ([]*html.Node, bool) {
 		}
 
 		linkText := trim(dom.TextContent(links[0]))
-		linkTextLength := utf8.RuneCountInString(linkText)
+		linkTextLength := strLength(linkText)
 		if linkTextLength > int(threshold) && float64(linkTextLength) > float64(textLength)*0.9 {
 			return nil, true
 		}
@@ -318,7 +324,7 @@ func linkDensityTestTables(table *html.Node, opts Options) bool {
 
 	// Check text length
 	text := trim(dom.TextContent(table))
-	textLength := utf8.RuneCountInString(text)
+	textLength := strLength(text)
 	if textLength < 200 {
 		return false
 	}
@@ -343,7 +349,7 @@ func linkDensityTestTables(table *html.Node, opts Options) bool {
 func collectLinkInfo(links []*html.Node) (linkLength, nShortLinks int, nonEmptyLinks []*html.Node) {
 	for _, link := range links {
 		text := trim(dom.TextContent(link))
-		textLength := utf8.RuneCountInString(text)
+		textLength := strLength(text)
 		if textLength == 0 {
 			continue
 		}
@@ -383,7 +389,7 @@ func processNode(element *html.Node, cache *lru.Cache, opts Options) *html.Node
 
 	// Content checks
 	if text != "" || tail != "" {
-		if textFilter(element) {
+		if textFilter(element, opts) {
 			return nil
 		}
 
@@ -466,7 +472,7 @@ func deleteByLinkDensity(subTree *html.Node, opts Options, backtracking bool, ta
 			nodesToDelete = append(nodesToDelete, elem)
 		} else if backtracking && len(nonEmptyLinks) > 0 {
 			text := trim(dom.TextContent(elem))
-			textLength := utf8.RuneCountInString(text)
+			textLength := strLength(text)
 			if textLength > 0 && textLength < threshold && len(dom.Children(elem)) >= nChildLimit {
 				nodesToDelete = append(nodesToDelete, elem)
 			}
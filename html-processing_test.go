@@ -26,6 +26,7 @@ import (
 
 	"github.com/go-shiori/dom"
 	"github.com/markusmobius/go-trafilatura/internal/etree"
+	"github.com/markusmobius/go-trafilatura/internal/lru"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/net/html"
 )
@@ -57,3 +58,29 @@ func Test_processNode(t *testing.T) {
 	assert.Equal(t, "some text", etree.Text(node))
 	assert.Equal(t, "tail", etree.Tail(node))
 }
+
+func Test_PruningKeepsOriginal(t *testing.T) {
+	// Content is short, so the wild text is recovered from the same document after
+	// its subtrees has been pruned
+	rawHTML := `<html><body><article><p>Short intro.</p><div class="teaser">Teaser text</div>
+		<img src="image.jpg"/><ul><li><a href="/a">Link A</a></li><li><a href="/b">Link B</a></li></ul>
+		</article><p>Wild paragraph outside the article.</p></body></html>`
+
+	for _, focus := range []ExtractionFocus{Balanced, FavorPrecision, FavorRecall} {
+		opts := defaultOpts
+		opts.Focus = focus
+
+		doc := docFromStr(rawHTML)
+		before := dom.OuterHTML(doc)
+		_, text := extractContent(doc, lru.NewCache(opts.Config.CacheSize), opts)
+		assert.Equal(t, before, dom.OuterHTML(doc))
+		assert.Contains(t, text, "Wild paragraph")
+	}
+
+	// Pruning with backup returns a copy as well
+	doc := docFromStr(rawHTML)
+	before := dom.OuterHTML(doc)
+	pruned := pruneUnwantedSections(doc, tagCatalog, defaultOpts)
+	assert.NotSame(t, doc, pruned)
+	assert.Equal(t, before, dom.OuterHTML(doc))
+}
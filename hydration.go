@@ -0,0 +1,425 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"github.com/markusmobius/go-trafilatura/internal/etree"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// HydrationExtractor is fallback extractor that looks for the article in the state
+// that used by JavaScript frameworks to hydrate the page, e.g. `__NEXT_DATA__` in
+// Next.js, `__NUXT__` and `__NUXT_DATA__` in Nuxt, and `__APOLLO_STATE__` in Apollo.
+// Those sites often ship a nearly empty HTML body, so the article can only be found
+// in the state. The richest article-like field (HTML string, plain text, content
+// blocks or rich text nodes) is rendered into paragraphs, headings and lists.
+type HydrationExtractor struct{}
+
+var (
+	rxHydrationState = regexp.MustCompile(`(?:window\.|self\.|var\s+|let\s+|const\s+)?(__[A-Z][A-Z_]*(?:STATE|DATA|NUXT|PROPS)__|__NUXT__)\s*=\s*`)
+	rxHydrationHTML  = regexp.MustCompile(`(?i)<(?:p|div|br|h[1-6]|ul|ol|li|blockquote|figure|strong|em|b|i|a)[\s/>]`)
+	rxHeadingLevel   = regexp.MustCompile(`(?i)(?:^|[^a-z])h(?:eading)?[-_]?([1-6])$`)
+	rxHydrationWords = regexp.MustCompile(`[^a-z0-9]+`)
+
+	hydrationKeyReplacer = strings.NewReplacer("_", "", "-", "")
+)
+
+// Wrappers of reactive value in Nuxt 3 payload, e.g. ["Reactive", 1].
+var nuxtReactiveTypes = sliceToMap("Reactive", "ShallowReactive", "Ref", "ShallowRef")
+
+// Types of rich text node that never contain the article text.
+var hydrationSkippedTypes = []string{"image", "video", "embed", "related", "newsletter", "advert", "iframe"}
+
+// Keys in the state whose value is likely the body of article.
+var hydrationBodyKeys = sliceToMap(
+	"body", "articlebody", "content", "contents", "html", "bodyhtml", "contenthtml",
+	"text", "richtext", "fulltext", "story", "storybody", "blocks", "contentblocks",
+	"paragraphs", "components", "elements", "document",
+)
+
+// Name returns the name of the extractor.
+func (HydrationExtractor) Name() string {
+	return "Hydration State"
+}
+
+// Extract extracts the main content from the hydration state of the document.
+func (HydrationExtractor) Extract(ctx context.Context, doc *html.Node, opts Options) (*html.Node, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var bestBody *html.Node
+	var bestLength int
+
+	for _, state := range findHydrationStates(doc) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		for _, candidate := range findHydrationCandidates(state) {
+			body := etree.Element("body")
+			renderHydrationValue(body, candidate, 0)
+
+			text := trim(etree.IterText(body, " "))
+			length := strLength(text)
+			if length > bestLength && linkDensity(body) < minBoilerplateLinkDensity {
+				bestBody, bestLength = body, length
+			}
+		}
+	}
+
+	return bestBody, nil
+}
+
+// findHydrationStates returns the decoded hydration states that found in the scripts
+// of the document.
+func findHydrationStates(doc *html.Node) []any {
+	var states []any
+	for _, script := range dom.GetElementsByTagName(doc, "script") {
+		scriptType := strings.ToLower(dom.GetAttribute(script, "type"))
+		if scriptType == "application/ld+json" {
+			continue
+		}
+
+		content := strings.TrimSpace(dom.TextContent(script))
+		if content == "" {
+			continue
+		}
+
+		// State that stored as pure JSON
+		switch id := dom.ID(script); {
+		case id == "__NUXT_DATA__":
+			var payload []any
+			if json.Unmarshal([]byte(content), &payload) == nil {
+				states = append(states, reviveNuxtPayload(payload))
+			}
+			continue
+
+		case id == "__NEXT_DATA__", scriptType == "application/json" && strings.HasPrefix(id, "__"):
+			var state any
+			if json.Unmarshal([]byte(content), &state) == nil {
+				states = append(states, state)
+			}
+			continue
+		}
+
+		// State that assigned into global variable. The value must be valid JSON,
+		// so state that generated as JavaScript function is skipped.
+		for _, idx := range rxHydrationState.FindAllStringIndex(content, -1) {
+			var state any
+			decoder := json.NewDecoder(strings.NewReader(content[idx[1]:]))
+			if decoder.Decode(&state) == nil {
+				states = append(states, state)
+			}
+		}
+	}
+
+	return states
+}
+
+// reviveNuxtPayload converts the flattened payload of Nuxt 3, where each value
+// refers to other values using their index in the payload array.
+func reviveNuxtPayload(payload []any) any {
+	revived := make(map[int]any)
+
+	var revive func(idx int, depth int) any
+	revive = func(idx int, depth int) any {
+		if idx < 0 || idx >= len(payload) || depth > 100 {
+			return nil
+		}
+
+		if value, exist := revived[idx]; exist {
+			return value
+		}
+		revived[idx] = nil // Prevent infinite loop on cyclic reference
+
+		var result any
+		switch v := payload[idx].(type) {
+		case map[string]any:
+			obj := make(map[string]any, len(v))
+			for key, ref := range v {
+				if refIdx, isNumber := ref.(float64); isNumber {
+					obj[key] = revive(int(refIdx), depth+1)
+				}
+			}
+			result = obj
+
+		case []any:
+			// Special types are stored as array whose first item is its name, e.g.
+			// ["Date", "2024-01-01"]. Only the reactive wrappers are unpacked.
+			if len(v) > 0 {
+				if name, isString := v[0].(string); isString {
+					_, isReactive := nuxtReactiveTypes[name]
+					if refIdx, isNumber := v[len(v)-1].(float64); isReactive && len(v) == 2 && isNumber {
+						result = revive(int(refIdx), depth+1)
+					}
+					break
+				}
+			}
+
+			arr := make([]any, 0, len(v))
+			for _, ref := range v {
+				if refIdx, isNumber := ref.(float64); isNumber {
+					arr = append(arr, revive(int(refIdx), depth+1))
+				}
+			}
+			result = arr
+
+		default:
+			result = v
+		}
+
+		revived[idx] = result
+		return result
+	}
+
+	return revive(0, 0)
+}
+
+// findHydrationCandidates walks through the state and returns the values that stored
+// under the body-like keys.
+func findHydrationCandidates(state any) []any {
+	var candidates []any
+	var walk func(value any, depth int)
+	walk = func(value any, depth int) {
+		if depth > 50 {
+			return
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			// Keys are sorted, so the result is deterministic
+			for _, key := range slices.Sorted(maps.Keys(v)) {
+				child := v[key]
+				normalizedKey := strings.ToLower(hydrationKeyReplacer.Replace(key))
+				if _, isBody := hydrationBodyKeys[normalizedKey]; isBody && child != nil {
+					// Short string will never be the richest, so skip it early
+					if str, isString := child.(string); !isString || len(str) >= 100 {
+						candidates = append(candidates, child)
+					}
+				}
+				walk(child, depth+1)
+			}
+
+		case []any:
+			for _, child := range v {
+				walk(child, depth+1)
+			}
+		}
+	}
+
+	walk(state, 0)
+	return candidates
+}
+
+// renderHydrationValue renders the value from hydration state into the parent. String
+// is rendered as HTML or plain text paragraphs, while object is rendered as rich text
+// node, e.g. Contentful rich text, Portable Text or ProseMirror document.
+func renderHydrationValue(parent *html.Node, value any, depth int) {
+	if depth > 50 {
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		renderHydrationString(parent, v)
+
+	case []any:
+		for _, item := range v {
+			renderHydrationValue(parent, item, depth+1)
+		}
+
+	case map[string]any:
+		renderHydrationNode(parent, v, depth)
+	}
+}
+
+func renderHydrationString(parent *html.Node, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+
+	isBlockParent := dom.TagName(parent) == "body"
+	if rxHydrationHTML.MatchString(text) {
+		nodes, err := html.ParseFragment(strings.NewReader(text), &html.Node{
+			Type:     html.ElementNode,
+			Data:     "body",
+			DataAtom: atom.Body,
+		})
+		if err == nil {
+			for _, node := range nodes {
+				dom.AppendChild(parent, node)
+			}
+			return
+		}
+	}
+
+	if !isBlockParent {
+		dom.AppendChild(parent, dom.CreateTextNode(text))
+		return
+	}
+
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if paragraph = trim(paragraph); paragraph != "" {
+			p := etree.SubElement(parent, "p")
+			etree.SetText(p, paragraph)
+		}
+	}
+}
+
+func renderHydrationNode(parent *html.Node, node map[string]any, depth int) {
+	nodeType := strings.ToLower(strOr(
+		hydrationString(node, "nodeType"),
+		hydrationString(node, "_type"),
+		hydrationString(node, "type"),
+		hydrationString(node, "blockType"),
+		hydrationString(node, "__typename")))
+
+	var children any
+	for _, key := range []string{"content", "children", "blocks", "items", "nodes", "elements"} {
+		if child, isArray := node[key].([]any); isArray {
+			children = child
+			break
+		}
+	}
+
+	var text string
+	for _, key := range []string{"text", "value", "html", "content", "body"} {
+		if text = hydrationString(node, key); text != "" {
+			break
+		}
+	}
+
+	tagName := hydrationTagName(nodeType, node)
+	switch {
+	case tagName == "-":
+		return
+	case tagName == "br":
+		etree.SubElement(parent, "br")
+		return
+	case tagName == "":
+		// Node without known type, e.g. the root document or inline node. Its text
+		// becomes paragraph if it's located in top level.
+		if text != "" && children == nil && dom.TagName(parent) == "body" && !rxHydrationHTML.MatchString(text) {
+			tagName = "p"
+		} else {
+			renderHydrationValue(parent, text, depth+1)
+			renderHydrationValue(parent, children, depth+1)
+			return
+		}
+	}
+
+	// Inline text is not rendered as block inside other block
+	if tagName == "p" && dom.TagName(parent) != "body" && dom.TagName(parent) != "blockquote" &&
+		dom.TagName(parent) != "li" {
+		renderHydrationValue(parent, text, depth+1)
+		renderHydrationValue(parent, children, depth+1)
+		return
+	}
+
+	element := etree.SubElement(parent, tagName)
+	renderHydrationValue(element, text, depth+1)
+	renderHydrationValue(element, children, depth+1)
+
+	// Remove the element if nothing rendered into it
+	if element.FirstChild == nil {
+		element.Parent.RemoveChild(element)
+	}
+}
+
+// hydrationTagName returns the HTML tag for rich text node. Returns "-" for nodes
+// that should be skipped, and empty string for node with unknown type.
+func hydrationTagName(nodeType string, node map[string]any) string {
+	if nodeType == "" {
+		return ""
+	}
+
+	for _, skipped := range hydrationSkippedTypes {
+		if strings.Contains(nodeType, skipped) {
+			return "-"
+		}
+	}
+
+	for _, word := range rxHydrationWords.Split(nodeType, -1) {
+		if word == "ad" || word == "ads" || word == "script" {
+			return "-"
+		}
+	}
+
+	switch {
+	case strings.Contains(nodeType, "break"):
+		return "br"
+	case strings.Contains(nodeType, "list-item"), strings.Contains(nodeType, "listitem"),
+		strings.Contains(nodeType, "list_item"):
+		return "li"
+	case strings.Contains(nodeType, "ordered") && !strings.Contains(nodeType, "unordered"):
+		return "ol"
+	case strings.Contains(nodeType, "list"):
+		return "ul"
+	case strings.Contains(nodeType, "quote"):
+		return "blockquote"
+	case strings.Contains(nodeType, "code"):
+		return "pre"
+	case strings.Contains(nodeType, "heading"), strings.Contains(nodeType, "header"):
+		level := "2"
+		if match := rxHeadingLevel.FindStringSubmatch(nodeType); match != nil {
+			level = match[1]
+		} else if attrs, isObject := node["attrs"].(map[string]any); isObject {
+			if lvl, isNumber := attrs["level"].(float64); isNumber && lvl >= 1 && lvl <= 6 {
+				level = fmt.Sprint(int(lvl))
+			}
+		} else if lvl, isNumber := node["level"].(float64); isNumber && lvl >= 1 && lvl <= 6 {
+			level = fmt.Sprint(int(lvl))
+		}
+		return "h" + level
+	case nodeType == "block":
+		// Portable Text block, the type is in its style
+		style := strings.ToLower(hydrationString(node, "style"))
+		switch {
+		case hydrationString(node, "listItem") != "":
+			return "li"
+		case rxHeadingLevel.MatchString(style):
+			return "h" + rxHeadingLevel.FindStringSubmatch(style)[1]
+		case style == "blockquote":
+			return "blockquote"
+		default:
+			return "p"
+		}
+	case strings.Contains(nodeType, "paragraph"), nodeType == "p":
+		return "p"
+	default:
+		return ""
+	}
+}
+
+func hydrationString(node map[string]any, key string) string {
+	str, _ := node[key].(string)
+	return str
+}
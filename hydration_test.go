@@ -0,0 +1,111 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+	"github.com/stretchr/testify/assert"
+)
+
+const hydrationParagraph = "The city council approved the new budget on Tuesday after a long debate " +
+	"about the cost of public transport and the maintenance of the old bridges."
+
+func Test_HydrationExtractor(t *testing.T) {
+	extract := func(rawHTML string) string {
+		result, err := HydrationExtractor{}.Extract(context.Background(), docFromStr(rawHTML), zeroOpts)
+		assert.NoError(t, err)
+		if result == nil {
+			return ""
+		}
+		return dom.InnerHTML(result)
+	}
+
+	// Next.js with HTML body
+	nextData := `{"props":{"pageProps":{"article":{"title":"Budget","teaser":"Short teaser",
+		"body":"<p>` + hydrationParagraph + `</p><h2>Next steps</h2><p>` + hydrationParagraph + `</p>"}}}}`
+	result := extract(`<html><body><div id="__next"></div>
+		<script id="__NEXT_DATA__" type="application/json">` + nextData + `</script></body></html>`)
+	assert.Equal(t, "<p>"+hydrationParagraph+"</p><h2>Next steps</h2><p>"+hydrationParagraph+"</p>", result)
+
+	// Apollo state with plain text
+	apollo := `{"Article:1":{"__typename":"Article","headline":"Budget","articleBody":"` +
+		hydrationParagraph + `\n\n` + hydrationParagraph + `"},"Article:2":{"articleBody":"` + hydrationParagraph + `"}}`
+	result = extract(`<html><body><script>window.__APOLLO_STATE__ = ` + apollo + `;</script></body></html>`)
+	assert.Equal(t, "<p>"+hydrationParagraph+"</p><p>"+hydrationParagraph+"</p>", result)
+
+	// Contentful rich text
+	richText := `{"nodeType":"document","content":[
+		{"nodeType":"heading-2","content":[{"nodeType":"text","value":"Next steps"}]},
+		{"nodeType":"paragraph","content":[{"nodeType":"text","value":"` + hydrationParagraph + `"},
+			{"nodeType":"hyperlink","data":{"uri":"/x"},"content":[{"nodeType":"text","value":"Source"}]}]},
+		{"nodeType":"embedded-asset-block","data":{}},
+		{"nodeType":"unordered-list","content":[{"nodeType":"list-item","content":[
+			{"nodeType":"paragraph","content":[{"nodeType":"text","value":"First item"}]}]}]}
+	]}`
+	result = extract(`<html><body><script>window.__INITIAL_STATE__={"post":{"richText":` + richText + `}}</script></body></html>`)
+	assert.Equal(t, "<h2>Next steps</h2><p>"+hydrationParagraph+"Source</p><ul><li><p>First item</p></li></ul>", result)
+
+	// Portable Text blocks
+	blocks := `[{"_type":"block","style":"h3","children":[{"_type":"span","text":"Next steps"}]},
+		{"_type":"block","style":"normal","children":[{"_type":"span","text":"` + hydrationParagraph + `"}]},
+		{"_type":"image","asset":{"_ref":"image-1"}}]`
+	result = extract(`<html><body><script id="__MY_STATE__" type="application/json">{"blocks":` + blocks + `}</script></body></html>`)
+	assert.Equal(t, "<h3>Next steps</h3><p>"+hydrationParagraph+"</p>", result)
+
+	// Nuxt 3 payload
+	nuxtData := `[["ShallowReactive",1],{"data":2},{"post":3},{"title":4,"content":5},"Budget","` + hydrationParagraph + `"]`
+	result = extract(`<html><body><script id="__NUXT_DATA__" type="application/json">` + nuxtData + `</script></body></html>`)
+	assert.Equal(t, "<p>"+hydrationParagraph+"</p>", result)
+
+	// State generated as function is skipped
+	result = extract(`<html><body><script>window.__NUXT__=(function(a){return {body:a}}("` + hydrationParagraph + `"))</script></body></html>`)
+	assert.Equal(t, "", result)
+}
+
+func Test_HydrationFallback(t *testing.T) {
+	nextData := `{"props":{"pageProps":{"post":{"content":"<p>` + strings.Repeat(hydrationParagraph+" ", 3) +
+		`</p><p>` + strings.Repeat(hydrationParagraph+" ", 3) + `</p>"}}}}`
+	rawHTML := `<html><head><title>Budget</title></head><body><div id="__next"><p>Loading…</p></div>
+		<script id="__NEXT_DATA__" type="application/json">` + nextData + `</script></body></html>`
+
+	// Without fallback, the content is not found
+	opts := defaultOpts
+	result, _ := Extract(strings.NewReader(rawHTML), opts)
+	if result != nil {
+		assert.NotContains(t, result.ContentText, "city council")
+	}
+
+	// Hydration state is not used by the default fallback extractors
+	opts.EnableFallback = true
+	result, _ = Extract(strings.NewReader(rawHTML), opts)
+	if result != nil {
+		assert.NotContains(t, result.ContentText, "city council")
+	}
+
+	// Hydration state is used as fallback candidate once it's enabled
+	opts.FallbackExtractors = append(DefaultFallbackExtractors(), HydrationExtractor{})
+	result, err := Extract(strings.NewReader(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Contains(t, result.ContentText, "The city council approved the new budget")
+	assert.Len(t, dom.GetElementsByTagName(result.ContentNode, "p"), 2)
+}
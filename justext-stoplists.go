@@ -0,0 +1,230 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// jusTextStoplists is the most common function words of each language, keyed by
+// ISO 639-1 code. It's used by jusText to measure the stop words density.
+var jusTextStoplists = map[string]string{
+	"cs": `a aby ale ani asi až bez bude budou by byl byla byli bylo být co či další do jak jako
+		jakož je jeho jej její jejich jen jenž ještě již jsem jsme jsou jste k kam kde kdo když
+		ke která které který kteří mezi mi mít mně mnou mu my na nad nám námi nás náš ne nebo
+		něco nich ním není nás o od on ona oni ono pak po pod podle pokud pouze právě pro proti
+		protože před při s se si sice své svůj ta tak také tam tato té tedy ten tento této
+		to toho tohoto tom tomto tu tuto ty tyto u už v ve však všech vy z za ze že`,
+	"da": `af alle allerede alt andre at blev blive bliver da de dem den denne der deres det
+		dette dig din disse dog du efter eller en end er et for fordi fra ham han hans har
+		havde have hende hendes her hos hun hvad hvis hvor i ikke ind jeg jer jo kan kunne
+		man mange med meget men mig min mit mod når ned noget nogle nu og også om op os over
+		på sammen selv sig sin sine sit skal skulle som så sådan thi til ud under var vi vil
+		ville vor være været`,
+	"de": `aber alle allem allen aller alles als also am an ander andere anderem anderen
+		anderer anderes auch auf aus bei bin bis bist da damit dann das dass dasselbe dazu
+		dein deine dem demselben den denn der derer derselbe des desselben dessen dich die
+		dies diese dieselbe diesem diesen dieser dieses dir doch dort du durch ein eine einem
+		einen einer eines einig einige einmal er es etwas euch euer für gegen gewesen hab habe
+		haben hat hatte hatten hier hin hinter ich ihm ihn ihnen ihr ihre ihrem ihren ihrer
+		im in indem ins ist jede jedem jeden jeder jedes jene jetzt kann kein keine können
+		könnte machen man manche mein meine mich mir mit muss musste nach nicht nichts noch
+		nun nur ob oder ohne sehr sein seine seinem seinen seiner sich sie sind so solche
+		soll sollte sondern sonst über um und uns unser unter viel vom von vor während war
+		waren warst was weg weil weiter welche welchem welchen welcher welches wenn werde
+		werden wie wieder will wir wird wirst wo wollen wollte würde würden zu zum zur zwar
+		zwischen`,
+	"en": `a about above after again against all also am an and any are as at be because been
+		before being below between both but by can could did do does doing down during each
+		even few for from further had has have having he her here hers herself him himself
+		his how however i if in into is it its itself just like may me might more most much
+		must my myself new no nor not now of off on once one only or other our ours ourselves
+		out over own said same she should since so some still such than that the their theirs
+		them themselves then there these they this those through to too under until up upon
+		us very was we well were what when where whether which while who whom why will with
+		within without would you your yours yourself yourselves`,
+	"es": `a al algo algunas algunos ante antes como con contra cual cuando de del desde donde
+		durante e el él ella ellas ellos en entre era erais eran eras es esa esas ese eso esos
+		esta está estaba estaban estado están estar este esto estos fue fueron fui ha había
+		han hasta hay la las le les lo los más me mi mis mucho muy nada ni no nos nosotros o
+		os otra otras otro otros para pero poco por porque que qué quien quienes se sea ser
+		si sí sido sin sobre son su sus también tanto te tiene tienen todo todos tu tus un
+		una uno unos usted vosotros y ya yo`,
+	"fi": `ei eivät emme en et että he heidän heille heitä hän häneen hänen hänet hänellä
+		hänelle hänestä ja joka jolla jolle jolta jonka jos jota jotka kanssa keneen kenen
+		kuin kuka kun me meidän meille meitä minä minun minut minulla minulle minusta mitä
+		mukaan mutta myös ne niiden niin niistä niitä noin nyt ole olemme olen olet oli
+		olivat olla ollut on ovat se sekä sen siellä siitä sillä sinä sinun sinut sitä
+		sitten tai te tämä tämän tässä tästä tätä vaan vai voi vuoksi yli`,
+	"fr": `a à afin ai aie aient ainsi alors après as au aucun aussi autre autres aux avait
+		avaient avant avec avez avoir ayant bien c ça car ce ceci cela celle celles celui cependant
+		ces cet cette ceux chaque chez comme comment d dans de depuis des deux donc dont du
+		elle elles en encore entre est et étaient était été être eu eux fait faire il ils
+		j je jusqu l la le les leur leurs lors lui m ma mais me même mes moi mon n ne ni nos
+		notre nous on ont ou où par parce pas peu peut plus pour pourquoi qu quand que quel
+		quelle quelles quels qui s sa sans se selon ses si sien son sont sous sur ta te tes
+		toi ton tous tout toute toutes très tu un une vers vos votre vous y`,
+	"it": `a ad agli ai al alla alle allo anche avere aveva avevano c che chi ci come con
+		contro cui da dai dal dalla dalle degli dei del dell della delle dello di dove e è ed
+		era erano essere fa fino fra gli ha hanno ho i il in io l la le lei lo loro lui ma
+		me mi mia mio molto ne nei nel nella nelle nello noi non nostro o ogni per perché più
+		poi quale quando quanto quella quelle quelli quello questa queste questi questo se
+		sei senza si sia siamo sono sopra sta stato su sua sue sui sul sulla suo tra tu tutti
+		tutto un una uno voi`,
+	"nl": `aan al alles als altijd andere ben bij daar dan dat de der deze die dit doch doen
+		door dus een eens en er ge geen geweest haar had heb hebben heeft hem het hier hij
+		hoe hun iemand iets ik in is ja je kan kon kunnen maar me meer men met mij mijn moet
+		na naar niet niets nog nu of om omdat onder ons ook op over reeds te tegen toch toen
+		tot u uit uw van veel voor want waren was wat we wel werd wezen wie wil worden wordt
+		zal ze zei zelf zich zij zijn zo zonder zou`,
+	"no": `alle at av bare blir både da de deg dei deim deira deires dem den denne der dere
+		deres det dette di din disse ditt du då eller en ende er et ett etter for fordi fra
+		før han hans har hennar henne hennes her hjå ho hoe honom hoss hossen hun hva hvem
+		hver hvilke hvis hvor hvordan hvorfor i ikke ingen inn jeg kan kom kunne man mange
+		me med meg men mi min mitt mot mye må ned noe noen nå og også om opp oss over på
+		samme seg selv si sia sidan siden sin sine sitt skal skulle slik som så til um under
+		upp ut uten var vart ved vi vil ville vore være vært`,
+	"pl": `a aby ach acz aczkolwiek aj albo ale ależ ani aż bardziej bardzo bez bo bowiem by
+		byli bym był była było były być będzie będą cali cała cały ci cię ciebie co cokolwiek
+		coś czy czyli daleko dla dlaczego dlatego do dobrze dokąd dość dużo dwa dwaj dwie
+		dzisiaj dziś gdy gdyby gdyż gdzie go i ich ile im inna inne inny innych iż ja ją jak
+		jakiś jako je jednak jednym jedynie jego jej jemu jest jestem jeszcze jeśli jeżeli już
+		każdy kiedy kilka kto która które którego której który których którym którzy ku lat
+		lecz lub ma mają mam mi między mnie mną może można mój musi my na nad nam nas nasz
+		nawet nic nich nie niej niż no o obok od około on ona one oni ono oraz owszem pan po
+		pod podczas pomimo ponad ponieważ przed przede przez przy raz również sam sama się
+		skąd są ta tak taka taki takie także tam te tego tej ten też to tobie tu tutaj twoi
+		ty tych tylko tym u w we według wiele wielu więc wszyscy wszystkich wszystko wtedy
+		z za zaś ze że żeby`,
+	"pt": `a à ao aos aquela aquelas aquele aqueles aquilo as às até com como da das de dela
+		delas dele deles depois do dos e é ela elas ele eles em entre era eram essa essas esse
+		esses esta está estão estas este estes eu foi foram há isso isto já lhe lhes mais mas
+		me mesmo meu meus minha minhas muito na não nas nem no nos nós nossa nossas nosso
+		nossos num numa o os ou para pela pelas pelo pelos por quando que quem se sem ser
+		seu seus só sua suas também te tem têm teu tu tua um uma você vocês`,
+	"ru": `а без более бы был была были было быть в вам вас весь во вот все всего всех вы
+		где да даже для до его ее ей ему если есть еще же за здесь и из или им их к как
+		какая какой когда который кто ли либо между меня мне много может мы на над надо
+		наш не него нее нет ни них но ну о об один он она они оно от очень по под после
+		потому при про раз с сам свое свой себя со так также такой там те тем то тогда
+		того тоже той только том тот ты у уже хотя чем через что чтобы эта эти это этого
+		этой этом этот я`,
+	"sv": `alla allt att av blev bli blir blivit de dem den denna deras dess dessa det detta
+		dig din dina ditt du där då efter ej eller en er era ert ett från för ha hade han
+		hans har henne hennes hon honom hur här i icke ingen inom inte jag ju kan kunde man
+		med mellan men mig min mina mitt mot mycket ni nu när någon något några och om oss
+		på samma sedan sig sin sina sitta själv skulle som så sådan till under upp ut utan
+		vad var vara varför varit varje vars vart vem vi vid vilka vilken vilket vår våra
+		vårt än är åt över`,
+	"tr": `acaba ama aslında az bazı belki biri birkaç birşey biz bu çok çünkü da daha de defa
+		diye eğer en gibi hem hep hepsi her hiç için ile ise kez ki kim mı mu mü nasıl ne
+		neden nerde nerede nereye niçin niye o sanki şey siz şu tüm ve veya ya yani bir
+		olarak olan olduğu sonra kadar ancak göre ayrıca çünkü ile`,
+
+	// Chinese and Japanese are written without spaces, so their stop words are looked
+	// up inside the text instead of matched against whole words. Korean is written
+	// with spaces but its particles are attached to the words, so it's not supported.
+	"ja": `の に は を が で と も へ や から まで より など こと もの ため よう これ それ
+		この その あの ある いる する なる ない れる られる として について による により
+		において における および また しかし さらに ので のみ です ます でした ました`,
+	"zh": `的 了 是 在 和 与 及 或 也 就 都 而 着 之 为 以 于 被 把 对 从 到 但 并 又 很 还
+		不 他 她 它 我 你 会 要 这 那 其 该 等 一个 没有 我们 你们 他们 她们 这个 那个
+		这些 那些 可以 因为 所以 如果 虽然 已经 但是 而且 以及`,
+}
+
+// Max number of characters in CJK stop word.
+const maxCJKStopwordLength = 4
+
+var (
+	jusTextStopwords     map[string]map[string]struct{}
+	jusTextAllStopwords  map[string]struct{}
+	jusTextStoplistsOnce sync.Once
+)
+
+// getJusTextStoplist returns the stop words for the specified language. If the
+// language is not supported, stop words from all languages are returned.
+func getJusTextStoplist(lang string) map[string]struct{} {
+	jusTextStoplistsOnce.Do(func() {
+		jusTextStopwords = make(map[string]map[string]struct{})
+		jusTextAllStopwords = make(map[string]struct{})
+		for language, words := range jusTextStoplists {
+			stoplist := make(map[string]struct{})
+			for _, word := range strings.Fields(words) {
+				stoplist[word] = struct{}{}
+				jusTextAllStopwords[word] = struct{}{}
+			}
+			jusTextStopwords[language] = stoplist
+		}
+	})
+
+	if stoplist, exist := jusTextStopwords[lang]; exist {
+		return stoplist
+	}
+	return jusTextAllStopwords
+}
+
+// countJusTextStopwords returns the number of stop words and the number of words in
+// the text. The words are counted using `strWordCount`, so the CJK text is measured
+// like the other scripts, and its stop words are found by matching the longest stop
+// word at each position since the text is not separated by spaces.
+func countJusTextStopwords(text string, stoplist map[string]struct{}) (nStopwords, nWords int) {
+	for _, word := range strings.Fields(text) {
+		if hasSpecialScript(word) {
+			nStopwords += countCJKStopwords([]rune(word), stoplist)
+			continue
+		}
+
+		word = strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}))
+		if _, isStopword := stoplist[word]; isStopword {
+			nStopwords++
+		}
+	}
+
+	return nStopwords, strWordCount(text)
+}
+
+func countCJKStopwords(chars []rune, stoplist map[string]struct{}) int {
+	var count int
+	for i := 0; i < len(chars); {
+		if runeScript(chars[i]) != cjkScript {
+			i++
+			continue
+		}
+
+		matchLength := 0
+		for length := min(maxCJKStopwordLength, len(chars)-i); length > 0; length-- {
+			if _, isStopword := stoplist[string(chars[i:i+length])]; isStopword {
+				matchLength = length
+				break
+			}
+		}
+
+		if matchLength > 0 {
+			count++
+			i += matchLength
+		} else {
+			i++
+		}
+	}
+	return count
+}
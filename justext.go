@@ -0,0 +1,442 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/go-shiori/dom"
+	"github.com/markusmobius/go-trafilatura/internal/etree"
+	"golang.org/x/net/html"
+)
+
+// JusTextClass is the class of paragraph that classified by jusText.
+type JusTextClass uint8
+
+const (
+	JusTextBad JusTextClass = iota
+	JusTextShort
+	JusTextNearGood
+	JusTextGood
+)
+
+func (c JusTextClass) String() string {
+	switch c {
+	case JusTextShort:
+		return "short"
+	case JusTextNearGood:
+		return "neargood"
+	case JusTextGood:
+		return "good"
+	default:
+		return "bad"
+	}
+}
+
+// JusTextParagraph is a paragraph found by jusText, along with the measurement
+// that used to classify it.
+type JusTextParagraph struct {
+	Text            string
+	Heading         bool
+	Length          int
+	LinkDensity     float64
+	StopwordDensity float64
+	Class           JusTextClass
+}
+
+// JusTextExtractor is fallback extractor that uses a native port of jusText, which
+// split the page into paragraphs then classify each of them as boilerplate or content
+// using its length, stop words density and link density. The paragraphs that can't be
+// decided on their own are then classified using their neighbours. The zero value
+// uses the same parameters as the original Trafilatura.
+type JusTextExtractor struct {
+	// Stoplist is the stop words that used to measure the stop words density. If it's
+	// empty, the built-in stoplist for the language of the page will be used. If the
+	// language is unknown or not supported, stop words from all languages are used.
+	Stoplist []string
+
+	// LengthLow and LengthHigh are the text length thresholds for short and long
+	// paragraph. Default to 50 and 150.
+	LengthLow  int
+	LengthHigh int
+
+	// StopwordsLow and StopwordsHigh are the stop words density thresholds for the
+	// paragraph to be considered as content. Default to 0.1 and 0.2.
+	StopwordsLow  float64
+	StopwordsHigh float64
+
+	// MaxLinkDensity is the maximum link density before paragraph is considered as
+	// boilerplate. Default to 0.25.
+	MaxLinkDensity float64
+
+	// UseHeadings enables the reclassification of short headings that located near
+	// good paragraph. MaxHeadingDistance is the maximum number of characters between
+	// them, default to 150.
+	UseHeadings        bool
+	MaxHeadingDistance int
+}
+
+var jusTextParagraphTags = sliceToMap(
+	"body", "blockquote", "caption", "center", "col", "colgroup", "dd", "div", "dl",
+	"dt", "fieldset", "form", "legend", "optgroup", "option", "p", "pre", "table",
+	"td", "textarea", "tfoot", "th", "thead", "tr", "ul", "li", "h1", "h2", "h3",
+	"h4", "h5", "h6",
+)
+
+var jusTextIgnoredTags = sliceToMap("head", "script", "style", "noscript", "template", "svg")
+
+// Name returns the name of the extractor.
+func (JusTextExtractor) Name() string {
+	return "jusText"
+}
+
+// Extract extracts the main content using jusText. Each good paragraph is returned
+// as `p` element inside `body`.
+func (je JusTextExtractor) Extract(ctx context.Context, doc *html.Node, opts Options) (*html.Node, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	paragraphs := je.Paragraphs(doc, opts)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	body := etree.Element("body")
+	for _, paragraph := range paragraphs {
+		if paragraph.Class == JusTextGood {
+			p := etree.SubElement(body, "p")
+			etree.SetText(p, paragraph.Text)
+		}
+	}
+
+	if body.FirstChild == nil {
+		return nil, nil
+	}
+	return body, nil
+}
+
+// Paragraphs splits the document into paragraphs and classifies them. Like in the
+// original Trafilatura, the obvious boilerplate elements (e.g. aside and footer) are
+// removed first from a copy of the document, so the document itself is not modified.
+func (je JusTextExtractor) Paragraphs(doc *html.Node, opts Options) []JusTextParagraph {
+	je.applyDefaults()
+
+	var stoplist map[string]struct{}
+	if len(je.Stoplist) > 0 {
+		stoplist = make(map[string]struct{}, len(je.Stoplist))
+		for _, word := range je.Stoplist {
+			stoplist[strings.ToLower(word)] = struct{}{}
+		}
+	} else {
+		stoplist = getJusTextStoplist(declaredLanguage(doc, opts))
+	}
+
+	cleanedDoc := basicCleaning(dom.Clone(doc, true))
+	paragraphs := makeJusTextParagraphs(cleanedDoc)
+	for i := range paragraphs {
+		je.classifyContextFree(&paragraphs[i], stoplist)
+	}
+	je.reviseClasses(paragraphs)
+
+	result := make([]JusTextParagraph, len(paragraphs))
+	for i, p := range paragraphs {
+		result[i] = p.JusTextParagraph
+	}
+	return result
+}
+
+func (je *JusTextExtractor) applyDefaults() {
+	if je.LengthLow <= 0 {
+		je.LengthLow = 50
+	}
+	if je.LengthHigh <= 0 {
+		je.LengthHigh = 150
+	}
+	if je.StopwordsLow <= 0 {
+		je.StopwordsLow = 0.1
+	}
+	if je.StopwordsHigh <= 0 {
+		je.StopwordsHigh = 0.2
+	}
+	if je.MaxLinkDensity <= 0 {
+		je.MaxLinkDensity = 0.25
+	}
+	if je.MaxHeadingDistance <= 0 {
+		je.MaxHeadingDistance = 150
+	}
+}
+
+// jusTextParagraph is the paragraph along with the data that only used internally
+// while classifying it.
+type jusTextParagraph struct {
+	JusTextParagraph
+	domPath      string
+	initialClass JusTextClass
+}
+
+// makeJusTextParagraphs splits the document into paragraphs. The paragraph is separated
+// by block elements and double line breaks.
+func makeJusTextParagraphs(doc *html.Node) []jusTextParagraph {
+	var (
+		paragraphs []jusTextParagraph
+		path       []string
+		sb         strings.Builder
+		linkChars  int
+		inLink     int
+		lastBr     bool
+		startPath  string
+	)
+
+	flush := func() {
+		text := trim(sb.String())
+		if text != "" {
+			length := strLength(text)
+			paragraphs = append(paragraphs, jusTextParagraph{
+				JusTextParagraph: JusTextParagraph{
+					Text:        text,
+					Heading:     isJusTextHeading(startPath),
+					Length:      length,
+					LinkDensity: float64(linkChars) / float64(length),
+				},
+				domPath: startPath,
+			})
+		}
+
+		sb.Reset()
+		linkChars = 0
+		startPath = strings.Join(path, ".")
+	}
+
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		switch node.Type {
+		case html.TextNode:
+			if strings.TrimSpace(node.Data) == "" {
+				return
+			}
+
+			text := strings.Join(strings.Fields(node.Data), " ")
+			if unicode.IsSpace(rune(node.Data[0])) {
+				text = " " + text
+			}
+			if unicode.IsSpace(rune(node.Data[len(node.Data)-1])) {
+				text += " "
+			}
+
+			sb.WriteString(text)
+			if inLink > 0 {
+				linkChars += strLength(strings.TrimSpace(text))
+			}
+			lastBr = false
+			return
+
+		case html.ElementNode:
+			tagName := dom.TagName(node)
+			if _, ignored := jusTextIgnoredTags[tagName]; ignored {
+				return
+			}
+
+			path = append(path, tagName)
+			defer func() { path = path[:len(path)-1] }()
+
+			_, isParagraph := jusTextParagraphTags[tagName]
+			switch {
+			case isParagraph, tagName == "br" && lastBr:
+				flush()
+				lastBr = false
+			case tagName == "br":
+				sb.WriteString(" ")
+				lastBr = true
+			case tagName == "a":
+				inLink++
+				lastBr = false
+				defer func() { inLink-- }()
+			default:
+				lastBr = false
+			}
+
+			for child := node.FirstChild; child != nil; child = child.NextSibling {
+				walk(child)
+			}
+
+			if isParagraph {
+				path = path[:len(path)-1]
+				flush()
+				path = append(path, tagName)
+			}
+			return
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+
+	walk(doc)
+	flush()
+	return paragraphs
+}
+
+// isJusTextHeading checks whether the DOM path is located inside a heading.
+func isJusTextHeading(domPath string) bool {
+	for _, tag := range strings.Split(domPath, ".") {
+		if len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6' {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyContextFree classifies the paragraph using only its own properties.
+func (je JusTextExtractor) classifyContextFree(p *jusTextParagraph, stoplist map[string]struct{}) {
+	if nStopwords, nWords := countJusTextStopwords(p.Text, stoplist); nWords > 0 {
+		p.StopwordDensity = float64(nStopwords) / float64(nWords)
+	}
+
+	switch {
+	case p.LinkDensity > je.MaxLinkDensity:
+		p.Class = JusTextBad
+	case strings.Contains(p.Text, "©") || strings.Contains(p.Text, "&copy"):
+		p.Class = JusTextBad
+	case strings.HasPrefix(p.domPath, "select") || strings.Contains(p.domPath, ".select"):
+		p.Class = JusTextBad
+	case p.Length < je.LengthLow:
+		if p.LinkDensity > 0 {
+			p.Class = JusTextBad
+		} else {
+			p.Class = JusTextShort
+		}
+	case p.StopwordDensity >= je.StopwordsHigh:
+		if p.Length > je.LengthHigh {
+			p.Class = JusTextGood
+		} else {
+			p.Class = JusTextNearGood
+		}
+	case p.StopwordDensity >= je.StopwordsLow:
+		p.Class = JusTextNearGood
+	default:
+		p.Class = JusTextBad
+	}
+
+	p.initialClass = p.Class
+}
+
+// reviseClasses does the context-sensitive classification, where short and near good
+// paragraphs are classified using the class of their neighbours.
+func (je JusTextExtractor) reviseClasses(paragraphs []jusTextParagraph) {
+	// Good headings
+	if je.UseHeadings {
+		je.reviseHeadings(paragraphs, JusTextShort)
+	}
+
+	// Classify short paragraphs
+	newClasses := make([]JusTextClass, len(paragraphs))
+	for i, p := range paragraphs {
+		newClasses[i] = p.Class
+		if p.Class != JusTextShort {
+			continue
+		}
+
+		prev := jusTextNeighbour(paragraphs, i, -1, true)
+		next := jusTextNeighbour(paragraphs, i, 1, true)
+		switch {
+		case prev == JusTextGood && next == JusTextGood:
+			newClasses[i] = JusTextGood
+		case prev == JusTextBad && next == JusTextBad:
+			newClasses[i] = JusTextBad
+		case prev == JusTextBad && jusTextNeighbour(paragraphs, i, -1, false) == JusTextNearGood,
+			next == JusTextBad && jusTextNeighbour(paragraphs, i, 1, false) == JusTextNearGood:
+			newClasses[i] = JusTextGood
+		default:
+			newClasses[i] = JusTextBad
+		}
+	}
+
+	for i := range paragraphs {
+		paragraphs[i].Class = newClasses[i]
+	}
+
+	// Revise near good paragraphs
+	for i, p := range paragraphs {
+		if p.Class != JusTextNearGood {
+			continue
+		}
+
+		prev := jusTextNeighbour(paragraphs, i, -1, true)
+		next := jusTextNeighbour(paragraphs, i, 1, true)
+		if prev == JusTextBad && next == JusTextBad {
+			paragraphs[i].Class = JusTextBad
+		} else {
+			paragraphs[i].Class = JusTextGood
+		}
+	}
+
+	// More good headings
+	if je.UseHeadings {
+		je.reviseHeadings(paragraphs, JusTextBad)
+	}
+}
+
+// reviseHeadings marks the heading with the specified class as good (or near good
+// in the first pass), if there is a good paragraph not far after it.
+func (je JusTextExtractor) reviseHeadings(paragraphs []jusTextParagraph, class JusTextClass) {
+	for i, p := range paragraphs {
+		if !p.Heading || p.Class != class {
+			continue
+		}
+
+		// Heading that initially bad is never revised
+		if class == JusTextBad && p.initialClass == JusTextBad {
+			continue
+		}
+
+		var distance int
+		for j := i + 1; j < len(paragraphs) && distance <= je.MaxHeadingDistance; j++ {
+			if paragraphs[j].Class == JusTextGood {
+				if class == JusTextShort {
+					paragraphs[i].Class = JusTextNearGood
+				} else {
+					paragraphs[i].Class = JusTextGood
+				}
+				break
+			}
+			distance += paragraphs[j].Length
+		}
+	}
+}
+
+// jusTextNeighbour returns the class of the nearest neighbour in the specified direction
+// which is either good or bad. If ignoreNearGood is false, near good neighbour is
+// returned as well. Edge of the document counts as bad.
+func jusTextNeighbour(paragraphs []jusTextParagraph, i, direction int, ignoreNearGood bool) JusTextClass {
+	for j := i + direction; j >= 0 && j < len(paragraphs); j += direction {
+		switch paragraphs[j].Class {
+		case JusTextGood, JusTextBad:
+			return paragraphs[j].Class
+		case JusTextNearGood:
+			if !ignoreNearGood {
+				return JusTextNearGood
+			}
+		}
+	}
+	return JusTextBad
+}
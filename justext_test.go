@@ -0,0 +1,122 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-shiori/dom"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_JusText(t *testing.T) {
+	paragraph := "The committee said that it would publish the report after the members had " +
+		"reviewed all of the evidence that was submitted during the hearing, which is expected to " +
+		"take several weeks."
+	rawHTML := `<html lang="en"><body>
+		<div class="menu"><a href="/">Home</a> <a href="/news">News</a> <a href="/sport">Sport</a></div>
+		<div class="article">
+			<h2>Report delayed</h2>
+			<p>` + paragraph + `</p>
+			<p>Short line here.</p>
+			<p>` + paragraph + `</p>
+			<p>Copyright © 2024 Example Inc. All rights reserved by the owner of this website.</p>
+		</div>
+		<select><option>Choose a language for this website so that all of the text is translated</option></select>
+		<footer>` + paragraph + `</footer>
+	</body></html>`
+
+	doc := docFromStr(rawHTML)
+	paragraphs := JusTextExtractor{}.Paragraphs(doc, zeroOpts)
+
+	classes := make(map[string]JusTextClass)
+	for _, p := range paragraphs {
+		classes[p.Text] = p.Class
+	}
+
+	assert.Equal(t, JusTextBad, classes["Home News Sport"])
+	assert.Equal(t, JusTextBad, classes["Report delayed"])
+	assert.Equal(t, JusTextGood, classes[paragraph])
+	assert.Equal(t, JusTextGood, classes["Short line here."])
+	assert.Equal(t, JusTextBad, classes["Copyright © 2024 Example Inc. All rights reserved by the owner of this website."])
+	assert.Equal(t, JusTextBad, classes["Choose a language for this website so that all of the text is translated"])
+
+	// Footer is removed in a copy, so the document is not modified
+	assert.Len(t, paragraphs, 7)
+	assert.NotNil(t, dom.QuerySelector(doc, "footer"))
+
+	// Good headings
+	paragraphs = JusTextExtractor{UseHeadings: true}.Paragraphs(doc, zeroOpts)
+	assert.Equal(t, "Report delayed", paragraphs[1].Text)
+	assert.Equal(t, JusTextGood, paragraphs[1].Class)
+
+	// Standalone extractor
+	result, err := JusTextExtractor{}.Extract(context.Background(), doc, zeroOpts)
+	assert.NoError(t, err)
+	assert.Len(t, dom.GetElementsByTagName(result, "p"), 3)
+
+	// Without any stop words, nothing is good
+	result, err = JusTextExtractor{Stoplist: []string{"xyz"}}.Extract(context.Background(), doc, zeroOpts)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func Test_JusTextParagraphs(t *testing.T) {
+	doc := docFromStr(`<div>First <b>line</b><br>same paragraph<br><br>Second <a href="#">paragraph</a></div>`)
+	paragraphs := makeJusTextParagraphs(doc)
+	assert.Len(t, paragraphs, 2)
+	assert.Equal(t, "First line same paragraph", paragraphs[0].Text)
+	assert.Equal(t, "Second paragraph", paragraphs[1].Text)
+	assert.InDelta(t, 9.0/16.0, paragraphs[1].LinkDensity, 0.001)
+	assert.Equal(t, "html.body.div", paragraphs[0].domPath)
+}
+
+func Test_JusTextStoplist(t *testing.T) {
+	en := getJusTextStoplist("en")
+	assert.Contains(t, en, "the")
+	assert.NotContains(t, en, "der")
+
+	all := getJusTextStoplist("xx")
+	assert.Contains(t, all, "the")
+	assert.Contains(t, all, "der")
+	assert.Greater(t, len(all), len(en))
+}
+
+func Test_JusTextCJK(t *testing.T) {
+	zh := "市政府今天宣布，新的图书馆将在明年春天开放，这个项目已经进行了三年，" +
+		"市民可以在那里借阅书籍，也可以参加各种文化活动。"
+	ja := "市は今日、新しい図書館が来年の春に開館すると発表した。このプロジェクトは三年前から" +
+		"進められており、市民は本を借りることができるほか、様々な文化活動にも参加できる。"
+	menu := "首页 新闻 体育 娱乐 财经 科技"
+
+	for lang, paragraph := range map[string]string{"zh": zh, "ja": ja} {
+		doc := docFromStr(`<html lang="` + lang + `"><body><div>` + menu + `</div><p>` + paragraph + `</p></body></html>`)
+		paragraphs := JusTextExtractor{}.Paragraphs(doc, zeroOpts)
+		assert.Len(t, paragraphs, 2)
+		assert.Equal(t, JusTextBad, paragraphs[0].Class, lang)
+		assert.Equal(t, JusTextGood, paragraphs[1].Class, lang)
+		assert.Greater(t, paragraphs[1].StopwordDensity, 0.2, lang)
+	}
+
+	// Longest stop word is matched, and the density uses the CJK word count
+	nStopwords, nWords := countJusTextStopwords("我们的书 and the book", getJusTextStoplist("xx"))
+	assert.Equal(t, 4, nStopwords)
+	assert.Equal(t, strWordCount("我们的书 and the book"), nWords)
+}
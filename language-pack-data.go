@@ -0,0 +1,202 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+// defaultLanguagePacks is the built-in language packs. English and German phrases
+// are also covered by `re2go.IsTextFilter` and `rxAuthorPrefix` regardless of the
+// page language, so their packs only contain the additional phrases.
+var defaultLanguagePacks = map[string]*LanguagePack{
+	"en": {
+		Boilerplate: []string{
+			"share", "share this", "share this article", "share this story", "share on facebook",
+			"share on twitter", "share on linkedin", "share via email", "tweet", "copy link",
+			"link copied", "advertisement", "sponsored content", "print this page", "save article",
+		},
+		BylinePrefixes: []string{"by", "written by", "posted by", "reporting by", "author"},
+		ReadMore: []string{
+			"read more", "read also", "continue reading", "read the full story", "see also",
+			"see more", "show more", "load more", "more from",
+		},
+		Related: []string{
+			"related", "related articles", "related posts", "related stories", "related content",
+			"you may also like", "you might also like", "more stories", "recommended for you",
+			"most read", "most popular", "trending now",
+		},
+		CommentLabels: []string{
+			"comments", "leave a comment", "leave a reply", "add a comment", "join the discussion",
+			"join the conversation", "show comments",
+		},
+	},
+	"de": {
+		Boilerplate: []string{
+			"teilen", "artikel teilen", "auf facebook teilen", "auf twitter teilen", "per e-mail teilen",
+			"per e-mail versenden", "link kopieren", "anzeige", "werbung", "merken", "artikel merken",
+		},
+		BylinePrefixes: []string{"von", "autor", "autorin", "ein beitrag von", "text"},
+		ReadMore: []string{
+			"weiterlesen", "mehr lesen", "lesen sie auch", "lesen sie mehr", "mehr erfahren",
+			"mehr anzeigen", "zum artikel", "auch interessant",
+		},
+		Related: []string{
+			"das könnte sie auch interessieren", "das könnte dich auch interessieren", "ähnliche artikel",
+			"verwandte artikel", "verwandte themen", "meistgelesen", "meistgelesene artikel",
+			"weitere artikel", "mehr aus dem ressort",
+		},
+		CommentLabels: []string{
+			"kommentare", "kommentar schreiben", "kommentieren", "diskutieren sie mit",
+			"kommentare anzeigen", "leserkommentare",
+		},
+	},
+	"fr": {
+		Boilerplate: []string{
+			"partager", "partager cet article", "partager sur facebook", "partager sur twitter",
+			"partager par e-mail", "envoyer par e-mail", "envoyer", "imprimer", "tweeter",
+			"copier le lien", "lien copié", "publicité", "sauvegarder", "ajouter à mes favoris",
+		},
+		BylinePrefixes: []string{"par", "auteur", "autrice", "écrit par", "propos recueillis par", "texte"},
+		ReadMore: []string{
+			"lire la suite", "lire aussi", "lire également", "à lire aussi", "voir aussi", "en savoir plus",
+			"voir plus", "afficher plus", "continuer la lecture",
+		},
+		Related: []string{
+			"à lire aussi", "sur le même sujet", "sur le même thème", "articles similaires",
+			"articles liés", "vous aimerez aussi", "vous aimerez aussi lire", "les plus lus",
+			"dans la même rubrique", "à découvrir",
+		},
+		CommentLabels: []string{
+			"commentaires", "laisser un commentaire", "ajouter un commentaire", "réagir",
+			"réactions", "vos réactions",
+		},
+	},
+	"es": {
+		Boilerplate: []string{
+			"compartir", "compartir en facebook", "compartir en twitter", "compartir por correo",
+			"enviar por correo", "enviar por email", "imprimir", "tuitear", "copiar enlace",
+			"enlace copiado", "publicidad", "guardar",
+		},
+		BylinePrefixes: []string{"por", "autor", "autora", "escrito por"},
+		ReadMore: []string{
+			"leer más", "seguir leyendo", "lee también", "lea también", "ver más", "más información",
+			"te puede interesar", "ver también",
+		},
+		Related: []string{
+			"noticias relacionadas", "artículos relacionados", "contenido relacionado",
+			"también te puede interesar", "te puede interesar", "lo más leído", "más noticias",
+			"más leídas", "recomendados",
+		},
+		CommentLabels: []string{
+			"comentarios", "deja un comentario", "dejar un comentario", "deja tu comentario",
+			"añadir comentario", "ver comentarios",
+		},
+	},
+	"it": {
+		Boilerplate: []string{
+			"condividi", "condividi su facebook", "condividi su twitter", "condividi via email",
+			"invia per email", "stampa", "copia link", "link copiato", "pubblicità", "salva",
+		},
+		BylinePrefixes: []string{"di", "autore", "autrice", "a cura di", "scritto da"},
+		ReadMore: []string{
+			"leggi di più", "leggi anche", "leggi tutto", "continua a leggere", "scopri di più",
+			"mostra altro", "vedi anche",
+		},
+		Related: []string{
+			"articoli correlati", "notizie correlate", "potrebbe interessarti", "ti potrebbe interessare",
+			"potrebbe interessarti anche", "i più letti", "altre notizie", "dello stesso autore",
+		},
+		CommentLabels: []string{
+			"commenti", "lascia un commento", "lascia un tuo commento", "aggiungi un commento",
+			"mostra commenti",
+		},
+	},
+	"pt": {
+		Boilerplate: []string{
+			"compartilhar", "compartilhe", "partilhar", "partilhe", "compartilhar no facebook",
+			"compartilhar no twitter", "enviar por e-mail", "imprimir", "copiar link", "link copiado",
+			"publicidade", "salvar",
+		},
+		BylinePrefixes: []string{"por", "autor", "autora", "escrito por"},
+		ReadMore: []string{
+			"leia mais", "ler mais", "leia também", "leia tambem", "continue lendo", "continuar a ler",
+			"saiba mais", "ver mais", "veja também",
+		},
+		Related: []string{
+			"notícias relacionadas", "artigos relacionados", "conteúdo relacionado", "veja também",
+			"leia também", "mais lidas", "mais lidos", "você também pode gostar", "recomendados",
+		},
+		CommentLabels: []string{
+			"comentários", "deixe um comentário", "deixe seu comentário", "deixe o seu comentário",
+			"comentar", "ver comentários",
+		},
+	},
+	"pl": {
+		Boilerplate: []string{
+			"udostępnij", "podziel się", "udostępnij na facebooku", "udostępnij na twitterze",
+			"wyślij e-mailem", "drukuj", "kopiuj link", "skopiuj link", "reklama", "zapisz",
+		},
+		BylinePrefixes: []string{"autor", "autorka", "oprac", "opracowanie", "tekst"},
+		ReadMore: []string{
+			"czytaj więcej", "czytaj dalej", "czytaj także", "czytaj też", "zobacz więcej",
+			"zobacz także", "zobacz też", "więcej informacji",
+		},
+		Related: []string{
+			"powiązane artykuły", "podobne artykuły", "zobacz także", "czytaj także", "polecamy",
+			"najczęściej czytane", "najpopularniejsze", "może cię zainteresować",
+		},
+		CommentLabels: []string{
+			"komentarze", "dodaj komentarz", "skomentuj", "pokaż komentarze", "opinie czytelników",
+		},
+	},
+	"ru": {
+		Boilerplate: []string{
+			"поделиться", "поделиться в facebook", "поделиться вконтакте", "отправить по почте",
+			"распечатать", "печать", "скопировать ссылку", "ссылка скопирована", "реклама",
+			"сохранить", "в закладки",
+		},
+		BylinePrefixes: []string{"автор", "текст", "подготовил", "подготовила"},
+		ReadMore: []string{
+			"читать далее", "читать полностью", "подробнее", "читайте также", "читать также",
+			"показать ещё", "смотрите также",
+		},
+		Related: []string{
+			"похожие статьи", "похожие новости", "читайте также", "по теме", "новости по теме",
+			"самое читаемое", "смотрите также", "вам может быть интересно", "популярное",
+		},
+		CommentLabels: []string{
+			"комментарии", "оставить комментарий", "добавить комментарий", "написать комментарий",
+			"обсуждение",
+		},
+	},
+	"ja": {
+		Boilerplate: []string{
+			"シェア", "シェアする", "ツイート", "ポスト", "いいね", "印刷", "印刷する", "メールで送る",
+			"リンクをコピー", "広告", "はてなブックマーク", "lineで送る", "保存",
+		},
+		BylinePrefixes: []string{"文", "著者", "記者", "執筆", "筆者", "取材・文", "執筆者"},
+		ReadMore: []string{
+			"続きを読む", "もっと見る", "詳しくはこちら", "記事を読む", "あわせて読みたい", "こちらもおすすめ",
+		},
+		Related: []string{
+			"関連記事", "関連ニュース", "おすすめ記事", "あわせて読みたい", "人気記事", "こちらもおすすめ",
+			"アクセスランキング", "よく読まれている記事",
+		},
+		CommentLabels: []string{
+			"コメント", "コメントを書く", "コメントする", "コメント一覧", "みんなのコメント",
+		},
+	},
+}
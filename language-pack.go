@@ -0,0 +1,326 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// LanguagePack is the language specific phrases that used by the heuristics to find
+// boilerplate and clean the metadata. The pack is selected using the language that
+// declared by the page, the target language, or the language detected from the page.
+// All phrases are matched case-insensitively.
+type LanguagePack struct {
+	// Boilerplate is the standalone text that should be removed from the content,
+	// e.g. share buttons and print links.
+	Boilerplate []string
+
+	// BylinePrefixes is the words that precede the author name in byline, e.g. "by".
+	BylinePrefixes []string
+
+	// ReadMore is the markers of link to other content, e.g. "read more". Text that
+	// only contains the marker, or the marker followed by colon or pipe (e.g.
+	// "Read also: other article") is removed from the content.
+	ReadMore []string
+
+	// Related is the headings of related content section, e.g. "related articles".
+	// The heading is removed along with the link list that follows it.
+	Related []string
+
+	// CommentLabels is the headings of comment section, e.g. "leave a comment".
+	CommentLabels []string
+}
+
+// languageMatcher is the compiled form of language pack.
+type languageMatcher struct {
+	phrases  map[string]struct{}
+	sections map[string]struct{}
+	readMore []string
+	rxByline *regexp.Regexp
+}
+
+// _CachedMatcher is the compiled matcher of a set of language packs. The packs are
+// kept, so their addresses that used as the cache key are never reused.
+type _CachedMatcher struct {
+	packs   []*LanguagePack
+	matcher *languageMatcher
+}
+
+// cachedMatchers is the compiled matchers, keyed by the addresses of their packs.
+var cachedMatchers sync.Map
+
+// Characters that separate the read more marker from the title of linked article.
+const readMoreSeparators = ":|»›>→–—-"
+
+// Max number of characters from the page that used to detect its language, when the
+// language pack is selected before the content is extracted.
+const maxLanguageSampleLength = 5000
+
+// Max length of text (measured by `strLength`) that checked against the language
+// pack. Longer text is never a boilerplate phrase, so it's skipped to save time.
+const maxPhraseLength = 100
+
+// DefaultLanguagePacks returns copy of the built-in language packs, keyed by ISO 639-1
+// language code. It can be modified and then used in `Options.LanguagePacks`, e.g. to
+// add phrases that used by specific websites or to add new languages.
+func DefaultLanguagePacks() map[string]*LanguagePack {
+	packs := make(map[string]*LanguagePack, len(defaultLanguagePacks))
+	for lang, pack := range defaultLanguagePacks {
+		packs[lang] = &LanguagePack{
+			Boilerplate:    slices.Clone(pack.Boilerplate),
+			BylinePrefixes: slices.Clone(pack.BylinePrefixes),
+			ReadMore:       slices.Clone(pack.ReadMore),
+			Related:        slices.Clone(pack.Related),
+			CommentLabels:  slices.Clone(pack.CommentLabels),
+		}
+	}
+	return packs
+}
+
+// selectLanguageMatcher returns the matcher for the language of the page. Since the
+// content is not extracted yet, it uses the language declared by the page or the
+// target language. If the page doesn't declare it and there are several target
+// languages, the packs of all targets are used instead. Only if there is no target,
+// the language is detected from the text of the whole page.
+func selectLanguageMatcher(doc *html.Node, lang string, opts Options) *languageMatcher {
+	languages := []string{lang}
+	if lang == "" {
+		if targets := targetLanguages(opts); len(targets) > 0 {
+			languages = targets
+		} else {
+			text := truncateRunes(pageText(doc), maxLanguageSampleLength)
+			languages = []string{detectLanguage(text, opts).Language}
+		}
+	}
+
+	// Phrases for filtering text are only used when user specified the packs, since
+	// they change the extraction result for the pages in those languages
+	packs, withPhrases := opts.LanguagePacks, true
+	if packs == nil {
+		packs, withPhrases = defaultLanguagePacks, false
+	}
+
+	var selectedPacks []*LanguagePack
+	for _, lang := range languages {
+		if pack := packs[lang]; pack != nil {
+			selectedPacks = append(selectedPacks, pack)
+		}
+	}
+
+	if len(selectedPacks) == 0 {
+		return nil
+	}
+
+	// Each set of packs is only compiled once
+	key := fmt.Sprint(withPhrases)
+	for _, pack := range selectedPacks {
+		key += fmt.Sprintf(",%p", pack)
+	}
+
+	if cached, exist := cachedMatchers.Load(key); exist {
+		return cached.(_CachedMatcher).matcher
+	}
+
+	matcher := compileLanguagePack(selectedPacks...)
+	if !withPhrases {
+		matcher.phrases = nil
+	}
+
+	cached, _ := cachedMatchers.LoadOrStore(key, _CachedMatcher{packs: selectedPacks, matcher: matcher})
+	return cached.(_CachedMatcher).matcher
+}
+
+// compileLanguagePack compiles the language packs into a single matcher.
+func compileLanguagePack(packs ...*LanguagePack) *languageMatcher {
+	matcher := &languageMatcher{
+		phrases:  make(map[string]struct{}),
+		sections: make(map[string]struct{}),
+	}
+
+	var pack LanguagePack
+	for _, p := range packs {
+		pack.Boilerplate = append(pack.Boilerplate, p.Boilerplate...)
+		pack.BylinePrefixes = append(pack.BylinePrefixes, p.BylinePrefixes...)
+		pack.ReadMore = append(pack.ReadMore, p.ReadMore...)
+		pack.Related = append(pack.Related, p.Related...)
+		pack.CommentLabels = append(pack.CommentLabels, p.CommentLabels...)
+	}
+
+	for _, list := range [][]string{pack.Boilerplate, pack.ReadMore, pack.Related, pack.CommentLabels} {
+		for _, phrase := range list {
+			if phrase = normalizePhrase(phrase); phrase != "" {
+				matcher.phrases[phrase] = struct{}{}
+			}
+		}
+	}
+
+	for _, phrase := range pack.Related {
+		if phrase = normalizePhrase(phrase); phrase != "" {
+			matcher.sections[phrase] = struct{}{}
+		}
+	}
+
+	for _, phrase := range pack.ReadMore {
+		if phrase = normalizePhrase(phrase); phrase != "" {
+			matcher.readMore = append(matcher.readMore, phrase)
+		}
+	}
+
+	var prefixes []string
+	for _, prefix := range pack.BylinePrefixes {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, regexp.QuoteMeta(prefix))
+		}
+	}
+
+	if len(prefixes) > 0 {
+		// Longer prefix first, so "written by" is removed instead of only "written"
+		slices.SortFunc(prefixes, func(a, b string) int { return len(b) - len(a) })
+		matcher.rxByline = regexp.MustCompile(`(?i)^(?:` + strings.Join(prefixes, "|") + `)(\s*[:：・]\s*|\s+)`)
+	}
+
+	return matcher
+}
+
+// normalizePhrase converts the text into lowercase, with the surrounding characters
+// that are not letter removed, e.g. "Comments (3)" becomes "comments".
+func normalizePhrase(s string) string {
+	s = strings.TrimFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	return strings.ToLower(trim(s))
+}
+
+// isBoilerplate checks whether the text is a localized boilerplate phrase.
+func (lm *languageMatcher) isBoilerplate(text string) bool {
+	if lm == nil || len(text) > maxPhraseLength*utf8.UTFMax {
+		return false
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = normalizePhrase(line)
+		if line == "" || strLength(line) > maxPhraseLength {
+			continue
+		}
+
+		if _, exist := lm.phrases[line]; exist {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isReadMore checks whether the text is a read more marker that followed by the
+// title of linked article, e.g. "Read also: other article".
+func (lm *languageMatcher) isReadMore(text string) bool {
+	if lm == nil {
+		return false
+	}
+
+	text = strings.ToLower(strings.TrimLeftFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }))
+	for _, marker := range lm.readMore {
+		rest, found := strings.CutPrefix(text, marker)
+		if !found {
+			continue
+		}
+
+		rest = strings.TrimSpace(rest)
+		if r, _ := utf8.DecodeRuneInString(rest); rest != "" && strings.ContainsRune(readMoreSeparators, r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cleanAuthors removes the localized byline prefixes from each author.
+func (lm *languageMatcher) cleanAuthors(authors string) string {
+	if lm == nil || lm.rxByline == nil || authors == "" {
+		return authors
+	}
+
+	var cleaned []string
+	for _, author := range strings.Split(authors, "; ") {
+		// Prefix like "di" or "de" is also common in names, so without explicit
+		// separator, it's only removed when the rest looks like a full name.
+		if match := lm.rxByline.FindStringSubmatchIndex(author); match != nil {
+			separator, rest := author[match[2]:match[3]], trim(author[match[1]:])
+			if strings.TrimSpace(separator) != "" || strWordCount(rest) >= 2 {
+				author = rest
+			}
+		}
+
+		if author != "" && !slices.Contains(cleaned, author) {
+			cleaned = append(cleaned, author)
+		}
+	}
+
+	return strings.Join(cleaned, "; ")
+}
+
+// pruneLocalizedSections removes the read more links, and the related content sections
+// whose heading is listed in the language pack.
+func pruneLocalizedSections(doc *html.Node, lm *languageMatcher) {
+	if lm == nil {
+		return
+	}
+
+	var nodesToRemove []*html.Node
+	for _, node := range dom.QuerySelectorAll(doc, "h1, h2, h3, h4, h5, h6, p, div, span, strong, a") {
+		if hasBlockDescendant(node) {
+			continue
+		}
+
+		text := trim(dom.TextContent(node))
+		if text == "" || strLength(text) > maxPhraseLength*2 {
+			continue
+		}
+
+		// Read more link to other article
+		hasLink := dom.TagName(node) == "a" || len(dom.GetElementsByTagName(node, "a")) > 0
+		if hasLink && lm.isReadMore(text) {
+			nodesToRemove = append(nodesToRemove, node)
+			continue
+		}
+
+		// Heading of related content, along with the links after it. The heading
+		// alone is kept, since phrases like "related" are too generic by themselves.
+		if _, isSection := lm.sections[normalizePhrase(text)]; !isSection {
+			continue
+		}
+
+		if next := dom.NextElementSibling(node); next != nil && linkDensity(next) >= minBoilerplateLinkDensity {
+			nodesToRemove = append(nodesToRemove, node, next)
+		}
+	}
+
+	for _, node := range nodesToRemove {
+		if node.Parent != nil {
+			node.Parent.RemoveChild(node)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LanguagePack(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("Le gouvernement a annoncé mardi une nouvelle réforme des retraites. ", 5) + "</p>"
+	createPage := func(lang string) string {
+		return `<html lang="` + lang + `"><head><meta name="author" content="Par Jean Dupont"/></head><body><article>
+			` + paragraph + `
+			<p>Partager</p>
+			<p>Lire aussi : <a href="/autre">Un autre article sur les retraites</a></p>
+			` + paragraph + `
+			<h3>Articles similaires</h3>
+			<ul><li><a href="/a">Premier article similaire</a></li><li><a href="/b">Deuxième article similaire</a></li></ul>
+		</article></body></html>`
+	}
+
+	opts := zeroOpts
+	opts.EnableFallback = false
+
+	// By default, only related content and bylines of the page language are cleaned
+	result, err := ExtractDocument(docFromStr(createPage("fr")), opts)
+	assert.NoError(t, err)
+	assert.Contains(t, result.ContentText, "nouvelle réforme")
+	assert.Contains(t, result.ContentText, "Partager")
+	assert.NotContains(t, result.ContentText, "Un autre article")
+	assert.NotContains(t, result.ContentText, "Articles similaires")
+	assert.NotContains(t, result.ContentText, "article similaire")
+	assert.Equal(t, "Jean Dupont", result.Metadata.Author)
+
+	// Pack of other language is not used
+	result, err = ExtractDocument(docFromStr(createPage("it")), opts)
+	assert.NoError(t, err)
+	assert.Contains(t, result.ContentText, "Partager")
+	assert.Equal(t, "Par Jean Dupont", result.Metadata.Author)
+
+	// Without declared language, the packs of all target languages are used
+	opts.TargetLanguages = []string{"it", "fr"}
+	result, err = ExtractDocument(docFromStr(createPage("")), opts)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.ContentText, "Un autre article")
+	assert.Equal(t, "Jean Dupont", result.Metadata.Author)
+	opts.TargetLanguages = nil
+
+	// Boilerplate phrases are only removed when the packs are specified
+	opts.LanguagePacks = DefaultLanguagePacks()
+	result, err = ExtractDocument(docFromStr(createPage("fr")), opts)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.ContentText, "Partager")
+
+	// User can extend the packs
+	opts.LanguagePacks = DefaultLanguagePacks()
+	opts.LanguagePacks["it"] = &LanguagePack{Boilerplate: []string{"partager"}, BylinePrefixes: []string{"par"}}
+	result, err = ExtractDocument(docFromStr(createPage("it")), opts)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.ContentText, "Partager")
+	assert.Contains(t, result.ContentText, "Un autre article")
+	assert.Equal(t, "Jean Dupont", result.Metadata.Author)
+
+	// Built-in packs are not modified
+	assert.NotContains(t, defaultLanguagePacks["it"].Boilerplate, "partager")
+}
+
+func Test_LanguagePack_Defaults(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("The council approved the new budget for the city library. ", 5) + "</p>"
+	doc := docFromStr(`<html lang="en"><body><article>
+		` + paragraph + `
+		<p>Advertisement</p>
+		<p>Related</p>
+		<p>Comments</p>
+		` + paragraph + `
+	</article></body></html>`)
+
+	opts := zeroOpts
+	opts.EnableFallback = false
+
+	// Generic phrases are kept by default, so the output is not changed
+	result, err := ExtractDocument(doc, opts)
+	assert.NoError(t, err)
+	assert.Contains(t, result.ContentText, "Advertisement")
+	assert.Contains(t, result.ContentText, "Related")
+	assert.Contains(t, result.ContentText, "Comments")
+
+	// Same set of packs is only compiled once
+	opts.LanguagePacks = DefaultLanguagePacks()
+	first := selectLanguageMatcher(doc, "en", opts)
+	second := selectLanguageMatcher(doc, "en", opts)
+	assert.NotNil(t, first)
+	assert.Same(t, first, second)
+	assert.NotSame(t, first, selectLanguageMatcher(doc, "en", zeroOpts))
+}
+
+func Test_LanguageMatcher(t *testing.T) {
+	matcher := compileLanguagePack(defaultLanguagePacks["it"])
+	assert.Equal(t, "Mario Rossi", matcher.cleanAuthors("Di Mario Rossi"))
+	assert.Equal(t, "Di Stefano", matcher.cleanAuthors("Di Stefano"))
+	assert.Equal(t, "Mario Rossi; Anna Bianchi", matcher.cleanAuthors("A cura di Mario Rossi; Anna Bianchi"))
+
+	matcher = compileLanguagePack(defaultLanguagePacks["ja"])
+	assert.Equal(t, "山田太郎", matcher.cleanAuthors("文：山田太郎"))
+	assert.True(t, matcher.isBoilerplate("シェアする"))
+	assert.True(t, matcher.isBoilerplate("コメント(12)"))
+
+	matcher = compileLanguagePack(defaultLanguagePacks["ru"])
+	assert.Equal(t, "Иван Петров", matcher.cleanAuthors("Автор Иван Петров"))
+	assert.True(t, matcher.isReadMore("Читайте также: Новости дня"))
+	assert.False(t, matcher.isReadMore("Читайте также"))
+	assert.False(t, matcher.isBoilerplate("Поделиться своим мнением о новой книге"))
+
+	// Phrase length is weighted for CJK text, like the other size thresholds
+	longPhrase := strings.Repeat("共有", 20)
+	matcher = compileLanguagePack(&LanguagePack{Boilerplate: []string{longPhrase, strings.Repeat("ab", 20)}})
+	assert.False(t, matcher.isBoilerplate(longPhrase))
+	assert.True(t, matcher.isBoilerplate(strings.Repeat("ab", 20)))
+
+	// Page text is truncated without splitting multi-byte characters
+	assert.Equal(t, "日本語", truncateRunes("日本語のテキスト", 3))
+	assert.Equal(t, "abc", truncateRunes("abc", 5))
+
+	// Nil matcher doesn't do anything
+	matcher = nil
+	assert.False(t, matcher.isBoilerplate("Share"))
+	assert.Equal(t, "By John", matcher.cleanAuthors("By John"))
+}
@@ -0,0 +1,154 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/RadhiFadlillah/whatlanggo"
+	"github.com/go-shiori/dom"
+	"github.com/markusmobius/go-trafilatura/internal/etree"
+	"golang.org/x/net/html"
+)
+
+// LanguageInfo is the language that detected from a text.
+type LanguageInfo struct {
+	// Language is the ISO 639-1 code of the language, e.g. "en". It's empty
+	// when the language can't be detected.
+	Language string
+
+	// Script is the name of the writing system, e.g. "Latin" or "Cyrillic".
+	Script string
+
+	// Confidence is how confident the detector about the language, from 0 to 1.
+	Confidence float64
+}
+
+// BlockLanguage is the language of a single block in the extracted content.
+type BlockLanguage struct {
+	// Node is the block element inside `ExtractResult.ContentNode`.
+	Node *html.Node
+
+	LanguageInfo
+}
+
+// Min length of block text whose language is detected. Shorter text doesn't have
+// enough trigrams to be detected reliably.
+const minBlockLanguageLength = 30
+
+// targetLanguages returns the normalized languages that specified in `TargetLanguage`
+// and `TargetLanguages`.
+func targetLanguages(opts Options) []string {
+	var languages []string
+	for _, lang := range append([]string{opts.TargetLanguage}, opts.TargetLanguages...) {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang != "" && !slices.Contains(languages, lang) {
+			languages = append(languages, lang)
+		}
+	}
+	return languages
+}
+
+// declaredLanguage returns the language that declared by the page in HTML, or the
+// target language if there is only one of it. Used to choose the size setting before
+// the content is extracted and its language detected.
+func declaredLanguage(doc *html.Node, opts Options) string {
+	selectors := []string{
+		`html[lang]`,
+		`meta[http-equiv="content-language" i][content]`,
+		`meta[property="og:locale"][content]`,
+	}
+
+	for _, selector := range selectors {
+		node := dom.QuerySelector(doc, selector)
+		if node == nil {
+			continue
+		}
+
+		value := strOr(dom.GetAttribute(node, "lang"), dom.GetAttribute(node, "content"))
+		if lang := rxHtmlLang.FindString(value); lang != "" {
+			return strings.ToLower(lang)
+		}
+	}
+
+	if targets := targetLanguages(opts); len(targets) == 1 {
+		return targets[0]
+	}
+
+	return ""
+}
+
+// detectLanguage detects the language of the text, restricted to the languages in
+// `LanguageCandidates` if it's specified.
+func detectLanguage(text string, opts Options) LanguageInfo {
+	var detectOpts whatlanggo.Options
+	if len(opts.LanguageCandidates) > 0 {
+		detectOpts.Whitelist = languageWhitelist(opts.LanguageCandidates)
+	}
+
+	info := whatlanggo.DetectWithOptions(text, detectOpts)
+	result := LanguageInfo{
+		Language:   info.Lang.Iso6391(),
+		Confidence: info.Confidence,
+	}
+
+	if info.Script != nil {
+		result.Script = whatlanggo.Scripts[info.Script]
+	}
+
+	return result
+}
+
+// languageWhitelist converts the ISO 639-1 codes into whitelist for the detector.
+// Unknown codes are ignored.
+func languageWhitelist(codes []string) map[whatlanggo.Lang]bool {
+	whitelist := make(map[whatlanggo.Lang]bool)
+	for lang := range whatlanggo.Langs {
+		if slices.ContainsFunc(codes, func(code string) bool {
+			return strings.EqualFold(strings.TrimSpace(code), lang.Iso6391())
+		}) {
+			whitelist[lang] = true
+		}
+	}
+	return whitelist
+}
+
+// detectBlockLanguages detects the language of each block in the content, then
+// mark the block with `lang` attribute. Blocks that too short or whose language
+// can't be detected confidently are left as it is.
+func detectBlockLanguages(content *html.Node, opts Options) []BlockLanguage {
+	var blocks []BlockLanguage
+	for _, block := range dom.Children(content) {
+		text := trim(etree.IterText(block, " "))
+		if utf8.RuneCountInString(text) < minBlockLanguageLength {
+			continue
+		}
+
+		info := detectLanguage(text, opts)
+		if info.Language == "" || info.Confidence < opts.Config.MinLanguageConfidence {
+			continue
+		}
+
+		dom.SetAttribute(block, "lang", info.Language)
+		blocks = append(blocks, BlockLanguage{Node: block, LanguageInfo: info})
+	}
+	return blocks
+}
@@ -0,0 +1,104 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	textFrench  = "Le gouvernement a annoncé mardi une nouvelle réforme des retraites qui sera présentée au parlement."
+	textGerman  = "Die Regierung hat am Dienstag eine neue Rentenreform angekündigt, die dem Parlament vorgelegt wird."
+	textEnglish = "The government announced a new pension reform on Tuesday that will be presented to parliament."
+)
+
+func Test_TargetLanguages(t *testing.T) {
+	createPage := func(text string) string {
+		return `<html><body><article>` + strings.Repeat("<p>"+text+"</p>", 5) + `</article></body></html>`
+	}
+
+	opts := zeroOpts
+	opts.EnableFallback = false
+	opts.TargetLanguages = []string{"en", "DE"}
+
+	// Any of the target languages is accepted
+	result, err := ExtractDocument(docFromStr(createPage(textGerman)), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "de", result.Language.Language)
+	assert.Equal(t, "Latin", result.Language.Script)
+	assert.Greater(t, result.Language.Confidence, 0.0)
+	assert.Equal(t, "de", result.Metadata.Language)
+
+	_, err = ExtractDocument(docFromStr(createPage(textFrench)), opts)
+	assert.ErrorContains(t, err, "wrong language")
+
+	// Detection restricted to candidates, which is not confident for French text
+	opts.LanguageCandidates = []string{"en", "de"}
+	opts.Config = DefaultConfig()
+	opts.Config.MinExtractedSize = 0
+	opts.Config.MinLanguageConfidence = 0.5
+
+	result, err = ExtractDocument(docFromStr(createPage(textFrench)), opts)
+	assert.NoError(t, err)
+	assert.Less(t, result.Language.Confidence, 0.5)
+	assert.Empty(t, result.Metadata.Language)
+
+	// HTML meta is checked as well
+	opts = Options{TargetLanguages: []string{"fr", "de"}}
+	doc := docFromStr(`<html><head><meta name="language" content="de-AT"/></head><body></body></html>`)
+	assert.True(t, checkHtmlLanguage(doc, opts, false))
+
+	opts = Options{TargetLanguages: []string{"fr", "it"}}
+	doc = docFromStr(`<html><head><meta name="DC.language" content="de"/></head><body></body></html>`)
+	assert.False(t, checkHtmlLanguage(doc, opts, false))
+}
+
+func Test_DetectBlockLanguages(t *testing.T) {
+	rawHTML := `<html><body><article>
+		<p>` + textEnglish + `</p>
+		<p>` + textGerman + `</p>
+		<p>Too short.</p>
+		<p>` + textEnglish + `</p>
+	</article></body></html>`
+
+	opts := zeroOpts
+	opts.EnableFallback = false
+	opts.DetectBlockLanguages = true
+
+	result, err := ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+
+	var languages []string
+	for _, block := range result.BlockLanguages {
+		languages = append(languages, block.Language)
+		assert.Equal(t, block.Language, dom.GetAttribute(block.Node, "lang"))
+	}
+	assert.Equal(t, []string{"en", "de", "en"}, languages)
+
+	// Without the option, blocks are not detected
+	opts.DetectBlockLanguages = false
+	result, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Empty(t, result.BlockLanguages)
+	assert.NotContains(t, dom.OuterHTML(result.ContentNode), "lang=")
+}
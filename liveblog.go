@@ -0,0 +1,309 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// LiveUpdate is a single timestamped update in a live blog.
+type LiveUpdate struct {
+	// ID is the identifier of the update, taken from its `@id` in JSON+LD or the
+	// id attribute of its element. If there is none, it will be the 1-based
+	// position of the update in the page.
+	ID string
+
+	Date     time.Time
+	Headline string
+	Author   string
+	URL      string
+	Text     string
+}
+
+// Elements that used for the headline and author inside a live blog update.
+const (
+	liveUpdateHeadlineSelector = "[itemprop=headline], h2, h3, h4"
+	liveUpdateAuthorSelector   = "[itemprop=author] [itemprop=name], [itemprop=author], [rel=author], " +
+		".author, .byline, .contributor"
+)
+
+// Minimum text length of a block to be regarded as live blog update.
+const minLiveUpdateLength = 20
+
+// rxLiveBlogMarker matches the class and id that used by CMS and live blog plugins
+// to mark the live blog. Generic names like "live-feed" or "live-stream" are not
+// included, since they are used by widgets in ordinary article pages.
+var (
+	rxLiveBlogMarker        = regexp.MustCompile(`(?i)(?:^|[-_\s])live[-_]?(?:blog|ticker|updates?)s?(?:$|[-_\s])`)
+	liveBlogMicrodata       = cascadia.MustCompile(`[itemtype*="LiveBlogPosting"], [itemprop="liveBlogUpdate"]`)
+	liveBlogMarkerCandidate = cascadia.MustCompile(`[class*="live" i], [id*="live" i]`)
+)
+
+// extractLiveUpdates detects whether the page is a live blog, then extracts each of
+// its updates. The updates are taken from `liveBlogUpdate` in JSON+LD when present,
+// otherwise from repeated timestamped blocks in the page. The updates are returned
+// in the same order as in the page, which usually the newest first.
+func extractLiveUpdates(doc *html.Node, opts Options) []LiveUpdate {
+	liveBlogSchemas := findLiveBlogSchemas(doc, opts)
+	for _, schema := range liveBlogSchemas {
+		if updates := liveUpdatesFromJsonLd(schema, opts); len(updates) > 0 {
+			return updates
+		}
+	}
+
+	// Without structured data, the timestamped updates must be inside the element
+	// that marked as live blog
+	root := doc
+	if len(liveBlogSchemas) == 0 && cascadia.Query(doc, liveBlogMicrodata) == nil {
+		if root = findLiveBlogMarker(doc); root == nil {
+			return nil
+		}
+	}
+
+	return liveUpdatesFromDOM(root, opts)
+}
+
+// findLiveBlogSchemas returns the `LiveBlogPosting` schemas in JSON+LD.
+func findLiveBlogSchemas(doc *html.Node, opts Options) []map[string]any {
+	var schemas []map[string]any
+	articles := jsonLdSchemas(doc, opts).articles
+	for _, article := range articles {
+		if strIn("liveblogposting", getSchemaTypes(article.Data, true)...) {
+			schemas = append(schemas, article.Data)
+		}
+	}
+	return schemas
+}
+
+// isLiveBlogUpdateSchema checks whether the schema is an update of live blog.
+func isLiveBlogUpdateSchema(data SchemaData) bool {
+	return data.Parent != nil && strIn("liveblogposting", getSchemaTypes(data.Parent.Data, true)...)
+}
+
+// isLiveBlog checks whether the page is marked as live blog in JSON+LD, microdata
+// or in the class and id of its elements.
+func isLiveBlog(doc *html.Node, opts Options) bool {
+	return len(findLiveBlogSchemas(doc, opts)) > 0 ||
+		cascadia.Query(doc, liveBlogMicrodata) != nil ||
+		findLiveBlogMarker(doc) != nil
+}
+
+// findLiveBlogMarker returns the first element whose class or id marks it as live
+// blog. The elements are filtered using selector first, so the regex is only used
+// for a few of them.
+func findLiveBlogMarker(doc *html.Node) *html.Node {
+	for _, node := range cascadia.QueryAll(doc, liveBlogMarkerCandidate) {
+		if rxLiveBlogMarker.MatchString(dom.ClassName(node)) || rxLiveBlogMarker.MatchString(dom.ID(node)) {
+			return node
+		}
+	}
+	return nil
+}
+
+func liveUpdatesFromJsonLd(schema map[string]any, opts Options) []LiveUpdate {
+	var items []map[string]any
+	switch value := schema["liveBlogUpdate"].(type) {
+	case map[string]any:
+		items = append(items, value)
+	case []any:
+		for _, item := range value {
+			if obj, isObject := item.(map[string]any); isObject {
+				items = append(items, obj)
+			}
+		}
+	}
+
+	var updates []LiveUpdate
+	for _, item := range items {
+		update := LiveUpdate{
+			ID:       getSingleStringValue(item, "@id"),
+			Headline: strOr(getSingleStringValue(item, "headline"), getSingleStringValue(item, "name")),
+			Author:   strings.Join(getSchemaNames(item["author"]), "; "),
+			URL:      getSingleStringValue(item, "url"),
+			Text: strOr(
+				getSingleStringValue(item, "articleBody"),
+				getSingleStringValue(item, "text"),
+				getSingleStringValue(item, "description")),
+		}
+
+		for _, key := range []string{"datePublished", "dateCreated", "dateModified"} {
+			if update.Date = parseLiveUpdateDate(getSingleStringValue(item, key)); !update.Date.IsZero() {
+				break
+			}
+		}
+
+		// The id is often the permalink of the update
+		if update.URL == "" && update.ID != "" {
+			update.URL = update.ID
+		}
+
+		updates = appendLiveUpdate(updates, update, opts)
+	}
+
+	return updates
+}
+
+// liveUpdatesFromDOM extracts the updates from microdata, or from the repeated
+// siblings that each has its own timestamp.
+func liveUpdatesFromDOM(doc *html.Node, opts Options) []LiveUpdate {
+	blocks := dom.QuerySelectorAll(doc, `[itemprop="liveBlogUpdate"]`)
+	if len(blocks) < 2 {
+		blocks = findTimestampedBlocks(doc)
+	}
+
+	var updates []LiveUpdate
+	for _, block := range blocks {
+		update := LiveUpdate{ID: dom.ID(block)}
+
+		// Metadata
+		dateNode := dom.QuerySelector(block, commentDateSelector)
+		if dateNode != nil {
+			update.Date = parseCommentDate(dateNode)
+		}
+
+		headlineNode := dom.QuerySelector(block, liveUpdateHeadlineSelector)
+		if headlineNode != nil {
+			update.Headline = trim(dom.TextContent(headlineNode))
+		}
+
+		authorNode := dom.QuerySelector(block, liveUpdateAuthorSelector)
+		if authorNode != nil {
+			update.Author = trim(dom.TextContent(authorNode))
+			update.Author = rxCommentSays.ReplaceAllString(update.Author, "")
+		}
+
+		// Permalink, either from the link around timestamp or from the id
+		for _, a := range dom.QuerySelectorAll(block, "a[href]") {
+			href := strings.TrimSpace(dom.GetAttribute(a, "href"))
+			_, fragment, _ := strings.Cut(href, "#")
+			if (fragment != "" && fragment == update.ID) || (dateNode != nil && isAncestorOf(a, dateNode)) {
+				update.URL = href
+				break
+			}
+		}
+
+		if update.URL == "" && update.ID != "" {
+			update.URL = "#" + update.ID
+		}
+
+		// Text of the update, excluding its metadata
+		update.Text = commentText(block, func(n *html.Node) bool {
+			return n == dateNode || n == headlineNode || n == authorNode
+		})
+
+		updates = appendLiveUpdate(updates, update, opts)
+	}
+
+	if len(updates) < 2 {
+		return nil
+	}
+	return updates
+}
+
+// findTimestampedBlocks returns the largest group of siblings with the same tag and
+// class, where each of them has its own timestamp and text. The root itself might
+// be the parent of the group.
+func findTimestampedBlocks(doc *html.Node) []*html.Node {
+	var bestGroup []*html.Node
+	parents := append([]*html.Node{doc}, dom.GetElementsByTagName(doc, "*")...)
+	for _, parent := range parents {
+		groups := make(map[string][]*html.Node)
+		var signatures []string
+		for _, child := range dom.Children(parent) {
+			signature := dom.TagName(child) + "." + dom.ClassName(child)
+			if _, exist := groups[signature]; !exist {
+				signatures = append(signatures, signature)
+			}
+			groups[signature] = append(groups[signature], child)
+		}
+
+		for _, signature := range signatures {
+			var blocks []*html.Node
+			for _, node := range groups[signature] {
+				dateNode := dom.QuerySelector(node, commentDateSelector)
+				if dateNode == nil || parseCommentDate(dateNode).IsZero() {
+					continue
+				}
+
+				if strLength(trim(dom.TextContent(node))) >= minLiveUpdateLength {
+					blocks = append(blocks, node)
+				}
+			}
+
+			if len(blocks) >= 2 && len(blocks) > len(bestGroup) {
+				bestGroup = blocks
+			}
+		}
+	}
+
+	return bestGroup
+}
+
+// appendLiveUpdate normalizes the update then appends it into the list, unless it's
+// empty or duplicate of an existing update.
+func appendLiveUpdate(updates []LiveUpdate, update LiveUpdate, opts Options) []LiveUpdate {
+	update.Headline = trim(update.Headline)
+	update.Author = trim(update.Author)
+	update.Text = strings.TrimSpace(update.Text)
+	if update.Text == "" && update.Headline == "" {
+		return updates
+	}
+
+	if update.URL != "" {
+		update.URL = createAbsoluteURL(update.URL, opts.OriginalURL)
+		if strings.HasPrefix(update.URL, "#") && opts.OriginalURL != nil {
+			pageURL := *opts.OriginalURL
+			pageURL.Fragment = update.URL[1:]
+			update.URL = pageURL.String()
+		}
+	}
+
+	for _, existing := range updates {
+		if (update.ID != "" && existing.ID == update.ID) ||
+			(existing.Date.Equal(update.Date) && existing.Headline == update.Headline && existing.Text == update.Text) {
+			return updates
+		}
+	}
+
+	if update.ID == "" {
+		update.ID = strconv.Itoa(len(updates) + 1)
+	}
+
+	return append(updates, update)
+}
+
+func parseLiveUpdateDate(value string) time.Time {
+	if value = trim(value); value == "" {
+		return time.Time{}
+	}
+
+	for _, format := range commentDateFormats {
+		if date, err := time.Parse(format, value); err == nil {
+			return date
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,126 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LiveUpdates_JsonLd(t *testing.T) {
+	rawHTML := `<html><head><script type="application/ld+json">{
+		"@context": "https://schema.org",
+		"@type": "LiveBlogPosting",
+		"headline": "Election night live",
+		"liveBlogUpdate": [
+			{"@type": "BlogPosting", "@id": "https://example.org/live#post-2", "headline": "Polls close",
+			 "datePublished": "2024-11-05T21:00:00Z", "author": {"@type": "Person", "name": "Jane Doe"},
+			 "articleBody": "Polls have closed in the eastern states."},
+			{"@type": "BlogPosting", "@id": "https://example.org/live#post-1", "headline": "Turnout is high",
+			 "datePublished": "2024-11-05T18:30:00Z", "articleBody": "Long queues are reported in several cities."},
+			{"@type": "BlogPosting", "@id": "https://example.org/live#post-1", "headline": "Turnout is high",
+			 "datePublished": "2024-11-05T18:30:00Z", "articleBody": "Long queues are reported in several cities."}
+		]}</script></head>
+		<body><article><h1>Election night live</h1><p>` + strings.Repeat("Follow the results as they come in. ", 10) + `</p></article></body></html>`
+
+	result, err := ExtractDocument(docFromStr(rawHTML), zeroOpts)
+	assert.NoError(t, err)
+	assert.Equal(t, "Election night live", result.Metadata.Title)
+	assert.Len(t, result.LiveUpdates, 2)
+
+	first := result.LiveUpdates[0]
+	assert.Equal(t, "https://example.org/live#post-2", first.ID)
+	assert.Equal(t, "https://example.org/live#post-2", first.URL)
+	assert.Equal(t, "Polls close", first.Headline)
+	assert.Equal(t, "Jane Doe", first.Author)
+	assert.Equal(t, time.Date(2024, 11, 5, 21, 0, 0, 0, time.UTC), first.Date)
+	assert.Equal(t, "Polls have closed in the eastern states.", first.Text)
+
+	assert.Equal(t, "Turnout is high", result.LiveUpdates[1].Headline)
+	assert.Equal(t, time.Date(2024, 11, 5, 18, 30, 0, 0, time.UTC), result.LiveUpdates[1].Date)
+}
+
+func Test_LiveUpdates_DOM(t *testing.T) {
+	rawHTML := `<html><body><main class="liveblog">
+		<h1>Storm updates</h1>
+		<div class="entry" id="update-3">
+			<time datetime="2024-02-10T09:15:00Z">09:15</time>
+			<h3>Roads reopen</h3><span class="author">Max Power</span>
+			<p>The main roads into the city have reopened.</p>
+		</div>
+		<div class="entry" id="update-2">
+			<time datetime="2024-02-10T08:40:00Z">08:40</time>
+			<p>Power has been restored to most homes.</p><p>Crews are still working in the north.</p>
+		</div>
+		<div class="entry" id="update-1">
+			<time datetime="2024-02-10T07:05:00Z">07:05</time>
+			<h3>Schools closed</h3>
+			<p>All schools are closed for the day.</p>
+		</div>
+		<div class="share">Share this page</div>
+	</main></body></html>`
+
+	opts := zeroOpts
+	result, err := ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Len(t, result.LiveUpdates, 3)
+
+	first := result.LiveUpdates[0]
+	assert.Equal(t, "update-3", first.ID)
+	assert.Equal(t, "https://example.org#update-3", first.URL)
+	assert.Equal(t, "Roads reopen", first.Headline)
+	assert.Equal(t, "Max Power", first.Author)
+	assert.Equal(t, time.Date(2024, 2, 10, 9, 15, 0, 0, time.UTC), first.Date)
+	assert.Equal(t, "The main roads into the city have reopened.", first.Text)
+
+	// Order in the page is kept
+	second := result.LiveUpdates[1]
+	assert.Equal(t, "", second.Headline)
+	assert.Equal(t, "Power has been restored to most homes.\nCrews are still working in the north.", second.Text)
+	assert.Equal(t, "Schools closed", result.LiveUpdates[2].Headline)
+
+	// Timestamped blocks in regular page are not live updates
+	rawHTML = strings.ReplaceAll(rawHTML, `class="liveblog"`, `class="archive"`)
+	result, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Empty(t, result.LiveUpdates)
+
+	// Generic live widgets in regular article page don't make it a live blog
+	for _, class := range []string{"live-feed", "live-stream", "live-post"} {
+		widgetHTML := strings.ReplaceAll(rawHTML, `class="archive"`, `class="article"`)
+		widgetHTML = strings.ReplaceAll(widgetHTML, `<div class="share">`, `<div class="`+class+`">Watch now</div><div class="share">`)
+		result, err = ExtractDocument(docFromStr(widgetHTML), opts)
+		assert.NoError(t, err)
+		assert.Empty(t, result.LiveUpdates, class)
+	}
+
+	// Timestamped blocks outside of the live blog element are not used
+	rawHTML = strings.ReplaceAll(rawHTML, `<main class="archive">`, `<div class="live-ticker">Ticker</div><main>`)
+	result, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Empty(t, result.LiveUpdates)
+
+	// Live blog is not split into articles
+	assert.Empty(t, findSplitArticles(docFromStr(`<html><body><div class="live-blog">`+
+		`<article><h2>One</h2>`+articleParagraphs("one")+`</article>`+
+		`<article><h2>Two</h2>`+articleParagraphs("two")+`</article></div></body></html>`), zeroOpts))
+}
@@ -2,6 +2,8 @@ package trafilatura
 
 import (
 	"encoding/json"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
 	"unicode/utf8"
@@ -17,6 +19,27 @@ type SchemaData struct {
 	Parent     *SchemaData
 }
 
+// _JsonLd is the decoded JSON+LD schemas of a document, grouped by their types.
+type _JsonLd struct {
+	persons       []SchemaData
+	organizations []SchemaData
+	articles      []SchemaData
+
+	// values is the top level objects of each script, for checks that look into
+	// the whole JSON+LD regardless of the schema types.
+	values []map[string]any
+}
+
+// jsonLdSchemas returns the JSON+LD of the document. It's decoded once in
+// `ExtractDocument` then shared by each extraction step, so the document only
+// decoded here when it's used outside of the main extraction.
+func jsonLdSchemas(doc *html.Node, opts Options) *_JsonLd {
+	if opts.jsonLd != nil {
+		return opts.jsonLd
+	}
+	return decodeJsonLd(doc, opts)
+}
+
 // extractJsonLd search metadata from JSON+LD data following the Schema.org guidelines
 // (https://schema.org). Here we don't really care about error here, so if parse failed
 // we just return the original metadata.
@@ -24,7 +47,8 @@ func extractJsonLd(opts Options, doc *html.Node, originalMetadata Metadata) Meta
 	var metadata Metadata
 
 	// Decode all script nodes that contain JSON+Ld schema
-	persons, organizations, articles := decodeJsonLd(doc, opts)
+	jsonLd := jsonLdSchemas(doc, opts)
+	persons, organizations, articles := jsonLd.persons, jsonLd.organizations, jsonLd.articles
 
 	// Extract metadata from each article
 	for _, article := range articles {
@@ -148,7 +172,10 @@ func extractJsonLd(opts Options, doc *html.Node, originalMetadata Metadata) Meta
 	return originalMetadata
 }
 
-func decodeJsonLd(doc *html.Node, opts Options) (persons, organizations, articles []SchemaData) {
+func decodeJsonLd(doc *html.Node, opts Options) *_JsonLd {
+	var values []map[string]any
+	var persons, organizations, articles []SchemaData
+
 	// Prepare function to find articles and persons inside JSON+LD recursively
 	var findImportantObjects func(obj map[string]any, parent *SchemaData)
 	findImportantObjects = func(obj map[string]any, parent *SchemaData) {
@@ -211,9 +238,10 @@ func decodeJsonLd(doc *html.Node, opts Options) (persons, organizations, article
 			articles = append(articles, schemaData)
 		}
 
-		// Continue to look in its sub values
-		for _, value := range obj {
-			switch v := value.(type) {
+		// Continue to look in its sub values. The keys are sorted so the order of
+		// the found objects is stable between runs.
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			switch v := obj[key].(type) {
 			case map[string]any:
 				findImportantObjects(v, &schemaData)
 
@@ -259,6 +287,7 @@ func decodeJsonLd(doc *html.Node, opts Options) (persons, organizations, article
 
 		// Extract each data
 		for _, data := range dataList {
+			values = append(values, data)
 			findImportantObjects(data, nil)
 		}
 	}
@@ -296,7 +325,12 @@ func decodeJsonLd(doc *html.Node, opts Options) (persons, organizations, article
 		organizations = articleOrganizations
 	}
 
-	return
+	return &_JsonLd{
+		persons:       persons,
+		organizations: organizations,
+		articles:      articles,
+		values:        values,
+	}
 }
 
 func getSchemaNames(v any, expectedTypes ...string) []string {
@@ -116,11 +116,19 @@ type Metadata struct {
 	Language    string
 	Image       string
 	PageType    string
+
+	// Rights is the normalized license, copyright and reuse reservations of the page.
+	Rights Rights
+
+	// Scholarly is the bibliographic record of the page, only available if the
+	// page is a scholarly work, e.g. journal article or preprint.
+	Scholarly *ScholarlyRecord
 }
 
 func extractMetadata(doc *html.Node, opts Options) Metadata {
 	// Extract metadata from <meta> tags
 	metadata := examineMeta(doc)
+	metadata.Author = opts.langMatcher.cleanAuthors(metadata.Author)
 	metadata.Author = removeBlacklistedAuthors(metadata.Author, opts)
 
 	// TODO: in original trafilatura, if author name is a single word,
@@ -130,6 +138,7 @@ func extractMetadata(doc *html.Node, opts Options) Metadata {
 
 	// Extract metadata from JSON-LD and override
 	metadata = extractJsonLd(opts, doc, metadata)
+	metadata.Author = opts.langMatcher.cleanAuthors(metadata.Author)
 	metadata.Author = removeBlacklistedAuthors(metadata.Author, opts)
 
 	// Try extracting from DOM element using selectors
@@ -141,6 +150,7 @@ func extractMetadata(doc *html.Node, opts Options) Metadata {
 	// Author
 	if metadata.Author == "" {
 		metadata.Author = extractDomAuthor(doc)
+		metadata.Author = opts.langMatcher.cleanAuthors(metadata.Author)
 		metadata.Author = removeBlacklistedAuthors(metadata.Author, opts)
 	}
 
@@ -260,6 +270,57 @@ func extractMetadata(doc *html.Node, opts Options) Metadata {
 
 	// License
 	metadata.License = extractLicense(doc)
+	metadata.Rights = extractRights(doc, opts)
+
+	// Bibliographic record for scholarly work
+	metadata.Scholarly = extractScholarlyMetadata(doc, opts, metadata)
+
+	// ADDITIONAL: fill the missing fields using hints from user
+	if opts.MetadataHints != nil {
+		metadata = applyMetadataHints(metadata, *opts.MetadataHints)
+	}
+
+	return metadata
+}
+
+// applyMetadataHints fills the empty fields in metadata using the hints, e.g. the
+// title and author that listed in the feed where the page is found.
+func applyMetadataHints(metadata Metadata, hints Metadata) Metadata {
+	if metadata.Title == "" {
+		metadata.Title = hints.Title
+	}
+
+	if metadata.Author == "" {
+		metadata.Author = hints.Author
+	}
+
+	if metadata.Description == "" {
+		metadata.Description = hints.Description
+	}
+
+	if metadata.Sitename == "" {
+		metadata.Sitename = hints.Sitename
+	}
+
+	if metadata.Date.IsZero() {
+		metadata.Date = hints.Date
+	}
+
+	if metadata.Image == "" {
+		metadata.Image = hints.Image
+	}
+
+	if metadata.Language == "" {
+		metadata.Language = hints.Language
+	}
+
+	if len(metadata.Categories) == 0 {
+		metadata.Categories = hints.Categories
+	}
+
+	if len(metadata.Tags) == 0 {
+		metadata.Tags = hints.Tags
+	}
 
 	return metadata
 }
@@ -488,8 +549,7 @@ func extractDomTitle(doc *html.Node) string {
 
 // extractDomAuthor returns the document author from DOM elements.
 func extractDomAuthor(doc *html.Node) string {
-	clone := dom.Clone(doc, true)
-	clone = pruneUnwantedNodes(clone, selector.MetaAuthorDiscard)
+	clone := pruneUnwantedNodes(doc, selector.MetaAuthorDiscard)
 
 	author := extractDomMetaSelectors(clone, 120, selector.MetaAuthor)
 	if author != "" {
@@ -27,6 +27,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/net/html"
@@ -571,6 +572,33 @@ func Test_Metadata_MetaTags(t *testing.T) {
 	assert.True(t, isEmpty(metadata))
 }
 
+func Test_Metadata_Hints(t *testing.T) {
+	hints := &Metadata{
+		Title:  "Feed Title",
+		Author: "Feed Author",
+		Date:   time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+		Tags:   []string{"feed"},
+	}
+
+	opts := Options{HtmlDateMode: Disabled, MetadataHints: hints}
+
+	// Hints fill the missing metadata
+	metadata := testGetMetadataFromHTML(`<html><body><p>Hello</p></body></html>`, opts)
+	assert.Equal(t, "Feed Title", metadata.Title)
+	assert.Equal(t, "Feed Author", metadata.Author)
+	assert.Equal(t, "2021-03-04", metadata.Date.Format("2006-01-02"))
+	assert.Equal(t, []string{"feed"}, metadata.Tags)
+
+	// Metadata from the page takes precedence
+	metadata = testGetMetadataFromHTML(`<html><head>
+		<title>Page Title</title>
+		<meta name="author" content="Jenny Smith"/>
+	</head><body><p>Hello</p></body></html>`, opts)
+	assert.Equal(t, "Page Title", metadata.Title)
+	assert.Equal(t, "Jenny Smith", metadata.Author)
+	assert.Equal(t, "2021-03-04", metadata.Date.Format("2006-01-02"))
+}
+
 func testGetMetadataFromHTML(rawHTML string, customOpts ...Options) Metadata {
 	// Parse raw html
 	doc, err := html.Parse(strings.NewReader(rawHTML))
@@ -0,0 +1,371 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// PageState is the state of a web page, i.e. whether its actual content is
+// accessible or it's replaced by some kind of interstitial.
+type PageState uint8
+
+const (
+	// PageAccessible means the content of the page is accessible as usual.
+	PageAccessible PageState = iota
+
+	// PagePaywalled means the content is hidden behind a paywall, and only the
+	// teaser is accessible.
+	PagePaywalled
+
+	// PageConsentWall means the content is replaced by cookie consent dialog.
+	PageConsentWall
+
+	// PageLoginWall means the content is only accessible after the user logged in.
+	PageLoginWall
+
+	// PageNotFound means the page is an error page (e.g. soft 404), even if it
+	// might be served with success status code.
+	PageNotFound
+)
+
+func (s PageState) String() string {
+	switch s {
+	case PageAccessible:
+		return "accessible"
+	case PagePaywalled:
+		return "paywalled"
+	case PageConsentWall:
+		return "consent-wall"
+	case PageLoginWall:
+		return "login-wall"
+	case PageNotFound:
+		return "not-found"
+	default:
+		return fmt.Sprintf("PageState(%d)", s)
+	}
+}
+
+// PageStatus is the verdict of page state classification.
+type PageStatus struct {
+	// State is the detected state of the page.
+	State PageState
+
+	// Evidence is the list of signals that found in the page and used to decide
+	// its state, e.g. "json-ld isAccessibleForFree: false".
+	Evidence []string
+}
+
+// PageStateError is the error that returned when the state of web page is listed
+// in `RejectedPageStates` option.
+type PageStateError struct {
+	Status PageStatus
+}
+
+func (err *PageStateError) Error() string {
+	return fmt.Sprintf("page is %s: %s", err.Status.State, strings.Join(err.Status.Evidence, "; "))
+}
+
+// Max length of extracted text that considered as teaser or interstitial, instead
+// of an actual full content.
+const maxInterstitialLength = 1500
+
+var (
+	rxPaywallClass = regexp.MustCompile(`(?i)(?:^|[\s_-])(?:paywall|pay-wall|premium-(?:content|barrier|wall|teaser)|` +
+		`subscriber-only|subscribers-only|subscription-wall|piano-offer|tp-modal|metered-content|` +
+		`article-locked|locked-content|regwall|plus-teaser|paid-content)(?:$|[\s_-])`)
+	rxPaywallCTA = regexp.MustCompile(`(?i)subscribe (?:now )?to (?:continue|read|unlock)|` +
+		`(?:already a|become a) subscriber|subscribers? only|exclusive(?:ly)? for subscribers|` +
+		`this (?:article|content) is for (?:paid )?subscribers|continue reading with|` +
+		`jetzt abonnieren|weiterlesen mit|nur für abonnenten|abonnez-vous|réservé aux abonnés|` +
+		`suscríbete para|solo para suscriptores|abbonati per|riservato agli abbonati`)
+
+	rxConsentClass = regexp.MustCompile(`(?i)cookie|consent|gdpr|onetrust|didomi|cookiebot|usercentrics|` +
+		`qc-cmp|sp_message|truste|(?:^|[\s_-])cmp(?:$|[\s_-])`)
+	rxConsentText = regexp.MustCompile(`(?i)we use cookies|use of cookies|cookie (?:policy|settings|preferences)|` +
+		`accept all|reject all|manage (?:preferences|options)|before you continue|` +
+		`alle akzeptieren|cookie-einstellungen|wir verwenden cookies|tout accepter|nous utilisons des cookies|` +
+		`aceptar todas?|utilizamos cookies|accetta tutti|utilizziamo i cookie`)
+
+	rxLoginText = regexp.MustCompile(`(?i)(?:sign|log) ?in to (?:continue|read|view|see)|` +
+		`(?:please|you must|you need to) (?:sign|log) ?in|login required|create (?:a free|an) account to|` +
+		`anmelden,? um|bitte melden sie sich an|connectez-vous pour|inicia sesión para|accedi per`)
+
+	rxNotFoundText = regexp.MustCompile(`(?i)^\s*(?:error )?404\b|\b(?:error 404|404 (?:error|not found)|` +
+		`(?:page|file) (?:was )?not found|(?:page|file) (?:could not|cannot|can[’']t|can not) be found|` +
+		`page (?:does not|doesn't|no longer) exists?|(?:page|content) is no longer available|` +
+		`seite (?:wurde )?nicht gefunden|page introuvable|página no encontrada|pagina non trovata)\b`)
+	rxNotFoundClass = regexp.MustCompile(`(?i)(?:^|\s)(?:error404|error-404|page-not-found|not-found|notfound|page-404)(?:$|\s)`)
+)
+
+// _PageStateInput is the data that used to detect the state of a page.
+type _PageStateInput struct {
+	doc         *html.Node
+	metadata    Metadata
+	contentText string
+	isShort     bool
+	jsonLd      *_JsonLd
+
+	// pageText returns text of the whole page. It's only needed by some checks,
+	// so it's computed lazily once.
+	pageText func() string
+}
+
+// detectPageState classifies the state of the page, using the original document
+// and the extracted content.
+func detectPageState(doc *html.Node, metadata Metadata, contentText string, opts Options) PageStatus {
+	in := _PageStateInput{
+		doc:         doc,
+		metadata:    metadata,
+		contentText: contentText,
+		isShort:     utf8.RuneCountInString(contentText) < maxInterstitialLength,
+		jsonLd:      jsonLdSchemas(doc, opts),
+		pageText:    sync.OnceValue(func() string { return pageText(doc) }),
+	}
+
+	// Check each state in order of precedence
+	checks := []struct {
+		state PageState
+		check func(_PageStateInput) []string
+	}{
+		{PageNotFound, detectNotFound},
+		{PageConsentWall, detectConsentWall},
+		{PageLoginWall, detectLoginWall},
+		{PagePaywalled, detectPaywall},
+	}
+
+	for _, c := range checks {
+		if evidence := c.check(in); len(evidence) > 0 {
+			return PageStatus{State: c.state, Evidence: evidence}
+		}
+	}
+
+	return PageStatus{State: PageAccessible}
+}
+
+func detectNotFound(in _PageStateInput) []string {
+	var evidence []string
+
+	// Status code that specified for pre-rendering service is decisive
+	for _, meta := range dom.QuerySelectorAll(in.doc, `meta[name="prerender-status-code"]`) {
+		if content := dom.GetAttribute(meta, "content"); content == "404" || content == "410" {
+			return []string{"meta prerender-status-code: " + content}
+		}
+	}
+
+	var hasClass bool
+	if body := dom.QuerySelector(in.doc, "body"); body != nil {
+		if match := rxNotFoundClass.FindString(dom.ClassName(body)); match != "" {
+			evidence = append(evidence, "body class: "+strings.TrimSpace(match))
+			hasClass = true
+		}
+	}
+
+	if match := rxNotFoundText.FindString(in.metadata.Title); match != "" {
+		evidence = append(evidence, fmt.Sprintf("title: %q", match))
+	}
+
+	if h1 := dom.QuerySelector(in.doc, "h1"); h1 != nil {
+		if match := rxNotFoundText.FindString(trim(dom.TextContent(h1))); match != "" {
+			evidence = append(evidence, fmt.Sprintf("heading: %q", match))
+		}
+	}
+
+	// Real article might mention "not found" in its title, so a text match alone is
+	// not enough. It must be confirmed by the body class or by both title and heading,
+	// and the text must be short as well.
+	if !in.isShort || (!hasClass && len(evidence) < 2) {
+		return nil
+	}
+
+	return append(evidence, "short content")
+}
+
+func detectConsentWall(in _PageStateInput) []string {
+	if !in.isShort {
+		return nil
+	}
+
+	// Consent banner exists in most pages, so it's only a wall if the consent text
+	// is what we extracted as the content
+	var evidence []string
+	if match := rxConsentText.FindString(in.contentText); match != "" {
+		evidence = append(evidence, fmt.Sprintf("content text: %q", match))
+	} else if match := rxConsentText.FindString(in.metadata.Title); match != "" {
+		evidence = append(evidence, fmt.Sprintf("title: %q", match))
+	} else {
+		return nil
+	}
+
+	if node := findByClass(in.doc, rxConsentClass); node != nil {
+		evidence = append(evidence, "consent element: "+describeNode(node))
+	}
+
+	if strings.HasPrefix(in.metadata.Hostname, "consent.") {
+		evidence = append(evidence, "hostname: "+in.metadata.Hostname)
+	}
+
+	if len(evidence) < 2 {
+		return nil
+	}
+
+	return evidence
+}
+
+func detectLoginWall(in _PageStateInput) []string {
+	if !in.isShort || dom.QuerySelector(in.doc, `input[type="password"]`) == nil {
+		return nil
+	}
+
+	evidence := []string{"password input"}
+	if match := rxLoginText.FindString(in.contentText); match != "" {
+		evidence = append(evidence, fmt.Sprintf("content text: %q", match))
+	} else if match := rxLoginText.FindString(in.pageText()); match != "" {
+		evidence = append(evidence, fmt.Sprintf("page text: %q", match))
+	} else {
+		return nil
+	}
+
+	return evidence
+}
+
+func detectPaywall(in _PageStateInput) []string {
+	// Full-length article might be marked as subscriber content even when it's
+	// accessible, so all signals are only meaningful when the content is a teaser
+	if !in.isShort {
+		return nil
+	}
+
+	var evidence []string
+
+	// Paywall that declared in structured data is decisive for teaser
+	for _, data := range in.jsonLd.values {
+		if isAccessibleForFree(data) == "false" {
+			evidence = append(evidence, "json-ld isAccessibleForFree: false")
+			break
+		}
+	}
+
+	for _, meta := range dom.QuerySelectorAll(in.doc, `meta[name="isAccessibleForFree"], meta[itemprop="isAccessibleForFree"]`) {
+		if strings.EqualFold(dom.GetAttribute(meta, "content"), "false") && len(evidence) == 0 {
+			evidence = append(evidence, "meta isAccessibleForFree: false")
+		}
+	}
+
+	if node := findByClass(in.doc, rxPaywallClass); node != nil {
+		evidence = append(evidence, "paywall element: "+describeNode(node))
+	}
+
+	if match := rxPaywallCTA.FindString(in.pageText()); match != "" {
+		evidence = append(evidence, fmt.Sprintf("subscribe text: %q", match))
+	}
+
+	// Without structured data, at least two signals are needed
+	hasStructuredData := len(evidence) > 0 && strings.Contains(evidence[0], "isAccessibleForFree")
+	if !hasStructuredData && len(evidence) < 2 {
+		return nil
+	}
+
+	return append(evidence, "short content")
+}
+
+// isAccessibleForFree looks for `isAccessibleForFree` property in JSON-LD data,
+// and returns its value in lowercase.
+func isAccessibleForFree(data any) string {
+	switch v := data.(type) {
+	case map[string]any:
+		if value, exist := v["isAccessibleForFree"]; exist {
+			switch value := value.(type) {
+			case bool:
+				return fmt.Sprint(value)
+			case string:
+				return strings.ToLower(strings.TrimSpace(value))
+			}
+		}
+
+		for _, key := range []string{"@graph", "mainEntity", "mainEntityOfPage"} {
+			if result := isAccessibleForFree(v[key]); result != "" {
+				return result
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if result := isAccessibleForFree(item); result != "" {
+				return result
+			}
+		}
+	}
+	return ""
+}
+
+// findByClass returns the first element whose id or class matched with the pattern.
+func findByClass(doc *html.Node, rx *regexp.Regexp) *html.Node {
+	for _, node := range dom.GetElementsByTagName(doc, "*") {
+		if strIn(dom.TagName(node), "html", "body", "script", "style", "link", "meta") {
+			continue
+		}
+
+		if rx.MatchString(dom.ID(node)) || rx.MatchString(dom.ClassName(node)) {
+			return node
+		}
+	}
+	return nil
+}
+
+func describeNode(node *html.Node) string {
+	desc := dom.TagName(node)
+	if id := dom.ID(node); id != "" {
+		desc += "#" + id
+	}
+
+	if classes := strings.Fields(dom.ClassName(node)); len(classes) > 0 {
+		desc += "." + strings.Join(classes, ".")
+	}
+
+	return desc
+}
+
+// pageText returns the visible text of the whole page.
+func pageText(doc *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			switch child.Type {
+			case html.TextNode:
+				sb.WriteString(child.Data)
+				sb.WriteString(" ")
+			case html.ElementNode:
+				if !slices.Contains([]string{"script", "style", "noscript", "template", "head"}, dom.TagName(child)) {
+					walk(child)
+				}
+			}
+		}
+	}
+	walk(doc)
+	return trim(sb.String())
+}
@@ -0,0 +1,141 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PageState(t *testing.T) {
+	article := `<p>` + strings.Repeat("This is a long article about many things. ", 50) + `</p>`
+	teaser := `<p>The opening paragraph of an article that is mostly hidden for the readers.</p>`
+
+	tests := []struct {
+		name     string
+		html     string
+		expected PageState
+	}{{
+		name:     "regular article",
+		html:     `<html><head><title>Article</title></head><body><article>` + article + `</article></body></html>`,
+		expected: PageAccessible,
+	}, {
+		name: "json-ld paywall",
+		html: `<html><head><title>Article</title>
+			<script type="application/ld+json">{"@context": "https://schema.org", "@graph": [
+				{"@type": "NewsArticle", "headline": "Article", "isAccessibleForFree": "False"}
+			]}</script></head>
+			<body><article>` + teaser + `</article></body></html>`,
+		expected: PagePaywalled,
+	}, {
+		name: "full article marked as subscriber content",
+		html: `<html><head><title>Article</title>
+			<script type="application/ld+json">{"@context": "https://schema.org",
+				"@type": "NewsArticle", "headline": "Article", "isAccessibleForFree": false}</script>
+			<meta name="isAccessibleForFree" content="false"/></head>
+			<body><article>` + article + `</article></body></html>`,
+		expected: PageAccessible,
+	}, {
+		name: "teaser with subscribe prompt",
+		html: `<html><head><title>Article</title></head><body><article>` + teaser + `
+			<div class="article-paywall"><p>Subscribe now to continue reading.</p></div>
+			</article></body></html>`,
+		expected: PagePaywalled,
+	}, {
+		name: "subscribe prompt without teaser",
+		html: `<html><head><title>Article</title></head><body><article>` + article + `
+			<div class="newsletter"><p>Already a subscriber? Log in.</p></div>
+			</article></body></html>`,
+		expected: PageAccessible,
+	}, {
+		name: "consent wall",
+		html: `<html><head><title>Before you continue</title></head><body>
+			<div id="consent-dialog"><p>We use cookies and data to deliver our services.</p>
+			<button>Accept all</button><button>Reject all</button></div></body></html>`,
+		expected: PageConsentWall,
+	}, {
+		name: "login wall",
+		html: `<html><head><title>Members area</title></head><body><main>
+			<p>Please log in to continue reading this story.</p>
+			<form><input type="email" name="email"/><input type="password" name="password"/></form>
+			</main></body></html>`,
+		expected: PageLoginWall,
+	}, {
+		name: "soft 404",
+		html: `<html><head><title>Page not found - Example</title></head><body class="error404">
+			<h1>Oops! That page can't be found.</h1><p>Try searching for something else.</p></body></html>`,
+		expected: PageNotFound,
+	}, {
+		name: "soft 404 without body class",
+		html: `<html><head><title>404 Not Found</title></head><body>
+			<h1>Page not found</h1><p>Try searching for something else.</p></body></html>`,
+		expected: PageNotFound,
+	}, {
+		name: "short news mentioning not found",
+		html: `<html><head><title>Bug not found in the new release</title></head><body>
+			<h1>Bug not found in the new release</h1><p>The developers could not reproduce the crash reported last week.</p>
+			</body></html>`,
+		expected: PageAccessible,
+	}, {
+		name: "short news with only title signal",
+		html: `<html><head><title>Page not found: how broken links hurt small shops</title></head><body>
+			<h1>How broken links hurt small shops</h1><p>A short teaser about the cost of dead links.</p>
+			</body></html>`,
+		expected: PageAccessible,
+	}, {
+		name: "article mentioning not found",
+		html: `<html><head><title>Missing painting not found after 30 years</title></head><body>
+			<article>` + article + `</article></body></html>`,
+		expected: PageAccessible,
+	}}
+
+	for _, test := range tests {
+		result, err := ExtractDocument(docFromStr(test.html), zeroOpts)
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, result.PageStatus.State, test.name)
+		if test.expected != PageAccessible {
+			assert.NotEmpty(t, result.PageStatus.Evidence, test.name)
+		}
+	}
+}
+
+func Test_PageState_Reject(t *testing.T) {
+	rawHTML := `<html><head><title>404 Not Found</title>
+		<meta name="prerender-status-code" content="404"/></head>
+		<body><p>Sorry, the page you are looking for does not exist.</p></body></html>`
+
+	// Only reported by default
+	result, err := ExtractDocument(docFromStr(rawHTML), zeroOpts)
+	assert.NoError(t, err)
+	assert.Equal(t, PageNotFound, result.PageStatus.State)
+	assert.Equal(t, []string{"meta prerender-status-code: 404"}, result.PageStatus.Evidence)
+
+	// Rejected with typed error
+	opts := zeroOpts
+	opts.RejectedPageStates = []PageState{PageNotFound, PagePaywalled}
+	_, err = ExtractDocument(docFromStr(rawHTML), opts)
+
+	var stateErr *PageStateError
+	assert.True(t, errors.As(err, &stateErr))
+	assert.Equal(t, PageNotFound, stateErr.Status.State)
+	assert.Equal(t, "page is not-found: meta prerender-status-code: 404", err.Error())
+}
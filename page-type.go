@@ -0,0 +1,368 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"fmt"
+	nurl "net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-shiori/dom"
+	"github.com/markusmobius/go-trafilatura/internal/selector"
+	"golang.org/x/net/html"
+)
+
+// PageType is the type of a web page, which is used to decide whether the page
+// is worth to be extracted.
+type PageType uint8
+
+const (
+	// UnknownPage means there are not enough signals to decide the page type.
+	UnknownPage PageType = iota
+
+	// ArticlePage is a page with a single main content, e.g. news article or blog post.
+	ArticlePage
+
+	// ListingPage is a page that lists other pages, e.g. category, tag or archive page.
+	ListingPage
+
+	// HomePage is the front page of a website.
+	HomePage
+
+	// ProductPage is a page of a product in online shop.
+	ProductPage
+
+	// ForumPage is a discussion thread in forum or Q&A site.
+	ForumPage
+
+	// VideoPage is a page whose main content is a video.
+	VideoPage
+
+	// GalleryPage is a page whose main content is a collection of images.
+	GalleryPage
+
+	// SearchPage is a page of search results.
+	SearchPage
+)
+
+func (pt PageType) String() string {
+	switch pt {
+	case UnknownPage:
+		return "unknown"
+	case ArticlePage:
+		return "article"
+	case ListingPage:
+		return "listing"
+	case HomePage:
+		return "homepage"
+	case ProductPage:
+		return "product"
+	case ForumPage:
+		return "forum"
+	case VideoPage:
+		return "video"
+	case GalleryPage:
+		return "gallery"
+	case SearchPage:
+		return "search"
+	default:
+		return fmt.Sprintf("PageType(%d)", pt)
+	}
+}
+
+// PageClass is the result of page type classification.
+type PageClass struct {
+	// Type is the most likely type of the page.
+	Type PageType
+
+	// Confidence is how sure the classifier about the type, between 0 and 1.
+	Confidence float64
+
+	// Evidence is the list of signals that support the type,
+	// e.g. "og:type: article" or "url path: /tag/".
+	Evidence []string
+}
+
+// PageTypeError is the error that returned when the type of web page is listed
+// in `SkippedPageTypes` option.
+type PageTypeError struct {
+	Class PageClass
+}
+
+func (err *PageTypeError) Error() string {
+	return fmt.Sprintf("page type is %s (confidence %.2f): %s",
+		err.Class.Type, err.Class.Confidence, strings.Join(err.Class.Evidence, "; "))
+}
+
+var (
+	rxListingPath = regexp.MustCompile(`(?i)/(?:tags?|categor(?:y|ies)|topics?|section|sections|archives?|` +
+		`rubri[ck]|ressort|themen?|kategorie)(?:/|$)|/page/\d+/?$`)
+	rxProductPath = regexp.MustCompile(`(?i)/(?:products?|produkt|dp|item|shop/p)/`)
+	rxForumPath   = regexp.MustCompile(`(?i)/(?:forums?|threads?|t|topic|questions|showthread\.php|viewtopic\.php)(?:/|$)`)
+	rxVideoPath   = regexp.MustCompile(`(?i)/(?:videos?|watch|mediathek)(?:/|$)`)
+	rxGalleryPath = regexp.MustCompile(`(?i)/(?:galler(?:y|ies)|photos?|bildergalerie|fotostrecke|slideshow)(?:/|$)`)
+	rxSearchPath  = regexp.MustCompile(`(?i)/(?:search|suche|recherche|buscar)(?:/|$)`)
+	rxArticlePath = regexp.MustCompile(`/(?:19|20)\d{2}/\d{1,2}/|(?:[^/]+-){3,}[^/]+(?:\.html?)?/?$`)
+	rxHomePath    = regexp.MustCompile(`(?i)^/?(?:index\.(?:html?|php))?$|^/(?:[a-z]{2}(?:-[a-z]{2})?)/?$`)
+	rxForumClass  = regexp.MustCompile(`(?i)(?:^|[\s_-])(?:forum|thread|topic-post|message-list|postbit|bbpress)(?:$|[\s_-])`)
+	rxVideoEmbed  = regexp.MustCompile(`(?i)youtube\.com/embed|player\.vimeo\.com|dailymotion\.com/embed`)
+)
+
+// ClassifyPage guesses the type of the web page using its URL, OpenGraph and JSON+LD
+// metadata and the structure of its content. It's cheaper than the full extraction,
+// so it can be used to decide how the page should be processed.
+func ClassifyPage(doc *html.Node, opts Options) PageClass {
+	scores := make(map[PageType]float64)
+	evidence := make(map[PageType][]string)
+	add := func(pt PageType, weight float64, reason string) {
+		scores[pt] += weight
+		evidence[pt] = append(evidence[pt], reason)
+	}
+
+	// Check the URL. Some sites put wrong canonical URL (e.g. their home page) in
+	// every pages, so URL from the document is less trusted than the original URL.
+	pageURL, urlWeight := opts.OriginalURL, 1.0
+	if pageURL == nil {
+		pageURL, urlWeight = documentURL(doc), 0.6
+	}
+
+	if pageURL != nil {
+		path := pageURL.Path
+		query := pageURL.Query()
+		switch {
+		case query.Has("q") || query.Has("s") || query.Has("query") || rxSearchPath.MatchString(path):
+			add(SearchPage, 0.6*urlWeight, "url: search")
+		case rxHomePath.MatchString(path):
+			add(HomePage, 0.6*urlWeight, "url path: "+strOr(path, "/"))
+		case rxProductPath.MatchString(path):
+			add(ProductPage, 0.5*urlWeight, "url path: "+rxProductPath.FindString(path))
+		case rxForumPath.MatchString(path):
+			add(ForumPage, 0.5*urlWeight, "url path: "+rxForumPath.FindString(path))
+		case rxVideoPath.MatchString(path):
+			add(VideoPage, 0.4*urlWeight, "url path: "+rxVideoPath.FindString(path))
+		case rxGalleryPath.MatchString(path):
+			add(GalleryPage, 0.4*urlWeight, "url path: "+rxGalleryPath.FindString(path))
+		case rxListingPath.MatchString(path):
+			add(ListingPage, 0.5*urlWeight, "url path: "+rxListingPath.FindString(path))
+		case rxArticlePath.MatchString(path):
+			add(ArticlePage, 0.4*urlWeight, "url path: article slug")
+		}
+	}
+
+	// Check OpenGraph type
+	for _, meta := range dom.QuerySelectorAll(doc, `meta[property="og:type"], meta[name="og:type"]`) {
+		ogType := strings.ToLower(strings.TrimSpace(dom.GetAttribute(meta, "content")))
+		switch {
+		case ogType == "article" || ogType == "blog" || strings.HasSuffix(ogType, ":article"):
+			add(ArticlePage, 0.5, "og:type: "+ogType)
+		case ogType == "product" || strings.HasPrefix(ogType, "product."):
+			add(ProductPage, 0.7, "og:type: "+ogType)
+		case strings.HasPrefix(ogType, "video."):
+			add(VideoPage, 0.6, "og:type: "+ogType)
+		}
+	}
+
+	// Check types in JSON+LD
+	seenTypes := make(map[PageType]bool)
+	for _, schemaType := range jsonLdTypes(doc, opts) {
+		pt, weight := schemaPageType(schemaType)
+		if pt != UnknownPage && !seenTypes[pt] {
+			seenTypes[pt] = true
+			add(pt, weight, "json-ld: "+schemaType)
+		}
+	}
+
+	// Check the structure of the body
+	if body := dom.QuerySelector(doc, "body"); body != nil {
+		classifyPageStructure(body, add)
+	}
+
+	// Home page usually lists the other pages as well, so the listing signals are
+	// used to support it
+	if scores[HomePage] > 0 && scores[ListingPage] > 0 {
+		scores[HomePage] += scores[ListingPage]
+		evidence[HomePage] = append(evidence[HomePage], evidence[ListingPage]...)
+		delete(scores, ListingPage)
+	}
+
+	// Pick the type with highest score
+	var best PageType
+	var bestScore, total float64
+	for pt := ArticlePage; pt <= SearchPage; pt++ {
+		total += scores[pt]
+		if scores[pt] > bestScore {
+			best, bestScore = pt, scores[pt]
+		}
+	}
+
+	if best == UnknownPage {
+		return PageClass{Type: UnknownPage}
+	}
+
+	return PageClass{
+		Type:       best,
+		Confidence: bestScore / max(total, 1),
+		Evidence:   evidence[best],
+	}
+}
+
+func classifyPageStructure(body *html.Node, add func(PageType, float64, string)) {
+	// Measure text and link density
+	var textLength, linkLength, paragraphLength int
+	var walk func(*html.Node, bool)
+	walk = func(n *html.Node, inLink bool) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			switch child.Type {
+			case html.TextNode:
+				length := utf8.RuneCountInString(strings.TrimSpace(child.Data))
+				textLength += length
+				if inLink {
+					linkLength += length
+				}
+			case html.ElementNode:
+				switch dom.TagName(child) {
+				case "script", "style", "noscript", "template", "nav", "header", "footer":
+				case "p":
+					if length := utf8.RuneCountInString(trim(dom.TextContent(child))); length >= 80 {
+						paragraphLength += length
+					}
+					walk(child, inLink)
+				default:
+					walk(child, inLink || dom.TagName(child) == "a")
+				}
+			}
+		}
+	}
+	walk(body, false)
+
+	if paragraphLength >= 1500 {
+		add(ArticlePage, 0.4, fmt.Sprintf("long paragraphs: %d chars", paragraphLength))
+	}
+
+	if textLength > 0 && paragraphLength < 1000 {
+		if density := float64(linkLength) / float64(textLength); density > 0.5 {
+			add(ListingPage, 0.3, fmt.Sprintf("link density: %.2f", density))
+		}
+	}
+
+	// Repeated teasers and articles are typical for listing
+	nTeasers := len(selector.QueryAll(body, selector.DiscardedTeaser[0]))
+	nArticles := len(dom.GetElementsByTagName(body, "article"))
+	if nTeasers >= 5 {
+		add(ListingPage, 0.4, fmt.Sprintf("teaser blocks: %d", nTeasers))
+	} else if nArticles >= 5 {
+		add(ListingPage, 0.3, fmt.Sprintf("article blocks: %d", nArticles))
+	}
+
+	// Forum thread
+	if node := findByClass(body, rxForumClass); node != nil {
+		add(ForumPage, 0.3, "forum element: "+describeNode(node))
+	}
+
+	// Page dominated by media
+	if paragraphLength < 1000 {
+		hasVideo := len(dom.GetElementsByTagName(body, "video")) > 0
+		for _, iframe := range dom.GetElementsByTagName(body, "iframe") {
+			hasVideo = hasVideo || rxVideoEmbed.MatchString(dom.GetAttribute(iframe, "src"))
+		}
+
+		if hasVideo {
+			add(VideoPage, 0.3, "embedded video with short text")
+		}
+
+		if nImages := len(dom.GetElementsByTagName(body, "img")); nImages >= 8 {
+			add(GalleryPage, 0.3, fmt.Sprintf("images with short text: %d", nImages))
+		}
+	}
+
+	// Search form that filled with query
+	for _, input := range dom.QuerySelectorAll(body, `input[type="search"], input[name="q"], input[name="s"]`) {
+		if strings.TrimSpace(dom.GetAttribute(input, "value")) != "" {
+			add(SearchPage, 0.3, "filled search input")
+			break
+		}
+	}
+}
+
+// schemaPageType maps the schema.org type into page type along with its weight.
+func schemaPageType(schemaType string) (PageType, float64) {
+	st := strings.ToLower(schemaType)
+	switch {
+	case st == "searchresultspage":
+		return SearchPage, 0.8
+	case st == "product" || st == "productgroup":
+		return ProductPage, 0.7
+	case st == "discussionforumposting" || st == "qapage" || st == "question":
+		return ForumPage, 0.7
+	case st == "imagegallery":
+		return GalleryPage, 0.6
+	case st == "collectionpage" || st == "itemlist":
+		return ListingPage, 0.5
+	case st == "videoobject":
+		// Article often embeds video, so it's a weak signal
+		return VideoPage, 0.3
+	case strings.Contains(st, "article") || st == "blogposting" || st == "report":
+		return ArticlePage, 0.6
+	default:
+		return UnknownPage, 0
+	}
+}
+
+// jsonLdTypes returns all schema types that declared in JSON+LD of the document.
+func jsonLdTypes(doc *html.Node, opts Options) []string {
+	var types []string
+	var find func(any)
+	find = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			types = append(types, getSchemaTypes(v, false)...)
+			for _, key := range []string{"@graph", "mainEntity", "mainEntityOfPage", "itemListElement"} {
+				find(v[key])
+			}
+		case []any:
+			for _, item := range v {
+				find(item)
+			}
+		}
+	}
+
+	for _, value := range jsonLdSchemas(doc, opts).values {
+		find(value)
+	}
+
+	return types
+}
+
+// documentURL returns the URL of document that declared in canonical link or OpenGraph.
+func documentURL(doc *html.Node) *nurl.URL {
+	for _, query := range []string{`link[rel="canonical"]`, `meta[property="og:url"]`} {
+		node := dom.QuerySelector(doc, query)
+		if node == nil {
+			continue
+		}
+
+		href := strOr(dom.GetAttribute(node, "href"), dom.GetAttribute(node, "content"))
+		if isAbs, parsedURL := isAbsoluteURL(href); isAbs {
+			return parsedURL
+		}
+	}
+	return nil
+}
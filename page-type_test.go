@@ -0,0 +1,149 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"errors"
+	nurl "net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ClassifyPage(t *testing.T) {
+	paragraphs := strings.Repeat(`<p>`+strings.Repeat("A sentence in a long paragraph of the article. ", 5)+`</p>`, 10)
+	teasers := strings.Repeat(`<div class="teaser"><a href="/news/some-story">Some story headline that links elsewhere</a></div>`, 8)
+
+	tests := []struct {
+		name     string
+		url      string
+		html     string
+		expected PageType
+	}{{
+		name: "article",
+		url:  "https://example.org/2021/05/a-long-article-slug",
+		html: `<html><head><meta property="og:type" content="article"/></head>
+			<body><article>` + paragraphs + `</article></body></html>`,
+		expected: ArticlePage,
+	}, {
+		name:     "listing",
+		url:      "https://example.org/category/news/",
+		html:     `<html><body><main>` + teasers + `</main></body></html>`,
+		expected: ListingPage,
+	}, {
+		name:     "homepage",
+		url:      "https://example.org/",
+		html:     `<html><head><meta property="og:type" content="website"/></head><body>` + teasers + `</body></html>`,
+		expected: HomePage,
+	}, {
+		name: "product",
+		url:  "https://shop.example.org/p/12345",
+		html: `<html><head><meta property="og:type" content="product"/>
+			<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Product", "name": "Mug"}</script>
+			</head><body><h1>Mug</h1><p>Nice mug.</p></body></html>`,
+		expected: ProductPage,
+	}, {
+		name: "forum",
+		url:  "https://forum.example.org/threads/help-me.123/",
+		html: `<html><head><script type="application/ld+json">{"@type": "DiscussionForumPosting", "headline": "Help"}</script>
+			</head><body><div class="message-list"><p>Question</p></div></body></html>`,
+		expected: ForumPage,
+	}, {
+		name: "video",
+		url:  "https://example.org/watch?v=abc",
+		html: `<html><head><meta property="og:type" content="video.other"/></head>
+			<body><iframe src="https://www.youtube.com/embed/abc"></iframe><p>Short description.</p></body></html>`,
+		expected: VideoPage,
+	}, {
+		name: "search",
+		url:  "https://example.org/?s=trafilatura",
+		html: `<html><head><script type="application/ld+json">{"@type": "SearchResultsPage"}</script></head>
+			<body><input type="search" name="s" value="trafilatura"/>` + teasers + `</body></html>`,
+		expected: SearchPage,
+	}}
+
+	for _, test := range tests {
+		opts := Options{}
+		opts.OriginalURL, _ = nurl.Parse(test.url)
+
+		class := ClassifyPage(docFromStr(test.html), opts)
+		assert.Equal(t, test.expected, class.Type, test.name)
+		assert.GreaterOrEqual(t, class.Confidence, 0.5, test.name)
+		assert.NotEmpty(t, class.Evidence, test.name)
+	}
+
+	// Without any signal, the type is unknown
+	class := ClassifyPage(docFromStr(`<html><body><p>Hello</p></body></html>`), Options{})
+	assert.Equal(t, UnknownPage, class.Type)
+	assert.Zero(t, class.Confidence)
+}
+
+func Test_ClassifyPage_Skip(t *testing.T) {
+	rawHTML := `<html><body><main>` + strings.Repeat(
+		`<div class="teaser"><a href="/news/some-story">Some story headline that links elsewhere</a></div>`, 8) +
+		`</main></body></html>`
+
+	opts := defaultOpts
+	opts.OriginalURL, _ = nurl.Parse("https://example.org/tag/go/")
+	opts.SkippedPageTypes = []PageType{ListingPage, HomePage}
+
+	_, err := ExtractDocument(docFromStr(rawHTML), opts)
+	var typeErr *PageTypeError
+	assert.True(t, errors.As(err, &typeErr))
+	assert.Equal(t, ListingPage, typeErr.Class.Type)
+
+	// Other types are extracted as usual
+	opts.SkippedPageTypes = []PageType{HomePage}
+	result, err := ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, ListingPage, result.PageClass.Type)
+
+	// Not skipped when the classifier is not confident enough
+	rawHTML = `<html><body><p>` + strings.Repeat("A sentence in a long paragraph of the article. ", 50) + `</p></body></html>`
+	opts.SkippedPageTypes = []PageType{ListingPage}
+	opts.Config = DefaultConfig()
+	opts.Config.MinPageTypeConfidence = 0.6
+
+	result, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, ListingPage, result.PageClass.Type)
+	assert.Less(t, result.PageClass.Confidence, 0.6)
+}
+
+func Test_ClassifyPage_CanonicalURL(t *testing.T) {
+	// Some sites put their home page as canonical URL in every pages, so the URL from
+	// document must not be trusted as much as URL from user.
+	paragraphs := strings.Repeat(`<p>`+strings.Repeat("A sentence in a long paragraph of the article. ", 5)+`</p>`, 10)
+	rawHTML := `<html><head><link rel="canonical" href="https://example.org/"/></head>
+		<body><article>` + paragraphs + `</article></body></html>`
+
+	opts := defaultOpts
+	result, err := ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, ArticlePage, result.PageClass.Type)
+	assert.Equal(t, ClassifyPage(docFromStr(rawHTML), opts), result.PageClass)
+
+	// URL from user is trusted over the canonical URL
+	opts.OriginalURL, _ = nurl.Parse("https://example.org/2021/05/a-long-article-slug")
+	result, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, ArticlePage, result.PageClass.Type)
+	assert.Contains(t, result.PageClass.Evidence, "url path: article slug")
+}
@@ -0,0 +1,371 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"fmt"
+	"io"
+	nurl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"github.com/markusmobius/go-trafilatura/internal/etree"
+	"golang.org/x/net/html"
+)
+
+// PageFetcher is function to fetch the raw HTML of a web page in the specified URL.
+// It's used to fetch the subsequent pages of a paginated article, so user can decide
+// how the page is downloaded, e.g. using custom HTTP client or from cache.
+type PageFetcher func(pageURL string) ([]byte, error)
+
+// PageSegment is the part of extracted content that came from a single page of
+// a paginated article.
+type PageSegment struct {
+	// Number is the 1-based page number.
+	Number int
+
+	// URL is the address of the page.
+	URL string
+
+	// TextStart and TextEnd are the byte offsets of the page text in `ContentText`.
+	TextStart int
+	TextEnd   int
+}
+
+// Default number of pages that followed in paginated article.
+const defaultMaxPages = 10
+
+var (
+	rxNextPageText = regexp.MustCompile(`(?i)^(?:next(?: page)?|older|weiter|nächste(?: seite)?|suivant(?:e)?|` +
+		`page suivante|siguiente|successiva|volgende|próxima|›|»|>|>>|→)\s*(?:›|»|>|→)?$`)
+	rxPaginationClass = regexp.MustCompile(`(?i)pagination|pager|paging|page-?nav|pages|seiten`)
+	rxPageNumberQuery = regexp.MustCompile(`(?i)^(?:page|p|pg|seite|pagina)$`)
+	rxPageNumberPath  = regexp.MustCompile(`(?i)(?:/(?:page|seite|pagina)/|[/_-]p(?:age)?-?)(\d+)/?$|/(\d+)/?$`)
+)
+
+// ExtractPages parses a reader that contains the first page of a paginated article,
+// then follows and extracts the subsequent pages using the fetcher. The result of
+// all pages is merged into a single result, with the boundaries of each page
+// recorded in `Pages`. If maxPages is zero, it will follow at most 10 pages.
+func ExtractPages(r io.Reader, opts Options, fetcher PageFetcher, maxPages int) (*ExtractResult, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, encoding, err := parseHTML(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := ExtractDocumentPages(doc, opts, fetcher, maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Encoding = encoding
+	return result, nil
+}
+
+// ExtractDocumentPages is like `ExtractPages`, but the first page is already parsed
+// as HTML document.
+func ExtractDocumentPages(doc *html.Node, opts Options, fetcher PageFetcher, maxPages int) (*ExtractResult, error) {
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+
+	// Find the next page before extraction, since the document might be modified
+	pageURL := opts.OriginalURL
+	if pageURL == nil {
+		pageURL = documentURL(doc)
+	}
+	nextURL := findNextPageURL(doc, pageURL)
+
+	// Extract the first page
+	result, err := ExtractDocument(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	merger := newPageMerger(result, pageURL)
+	visited := map[string]struct{}{}
+	if pageURL != nil {
+		visited[normalizePageURL(pageURL)] = struct{}{}
+	}
+
+	// Follow the next pages
+	for nextURL != nil && len(merger.pages) < maxPages {
+		if _, seen := visited[normalizePageURL(nextURL)]; seen {
+			break
+		}
+		visited[normalizePageURL(nextURL)] = struct{}{}
+
+		strURL := nextURL.String()
+		content, err := fetcher(strURL)
+		if err != nil {
+			logWarn(opts, "failed to fetch page %s: %v", strURL, err)
+			break
+		}
+
+		pageOpts := opts
+		pageOpts.OriginalURL = nextURL
+		pageOpts.ContentType = ""
+		pageDoc, _, err := parseHTML(content, pageOpts)
+		if err != nil {
+			logWarn(opts, "failed to parse page %s: %v", strURL, err)
+			break
+		}
+
+		pageURL, nextURL = nextURL, findNextPageURL(pageDoc, nextURL)
+		pageResult, err := ExtractDocument(pageDoc, pageOpts)
+		if err != nil {
+			logWarn(opts, "failed to extract page %s: %v", strURL, err)
+			break
+		}
+
+		merger.add(pageResult, pageURL)
+	}
+
+	return merger.result(), nil
+}
+
+// pageMerger combines the extraction result of each page, while removing the
+// blocks that repeated at the start or end of every pages, e.g. header and footer
+// of the article. Blocks in the middle of the page are always kept, since quotes
+// and short paragraphs might be legitimately repeated.
+type pageMerger struct {
+	first      *ExtractResult
+	pages      []PageSegment
+	texts      []string
+	seenBlocks map[string]struct{}
+}
+
+func newPageMerger(first *ExtractResult, firstURL *nurl.URL) *pageMerger {
+	pm := &pageMerger{
+		first:      first,
+		seenBlocks: make(map[string]struct{}),
+	}
+
+	// Remember the blocks in first page, but keep them all
+	for _, block := range dom.Children(first.ContentNode) {
+		if text := trim(etree.IterText(block, " ")); text != "" {
+			pm.seenBlocks[text] = struct{}{}
+		}
+	}
+
+	pm.addSegment(first.ContentText, firstURL)
+	return pm
+}
+
+func (pm *pageMerger) add(result *ExtractResult, pageURL *nurl.URL) {
+	blocks := dom.Children(result.ContentNode)
+	blockTexts := make([]string, len(blocks))
+	for i, block := range blocks {
+		blockTexts[i] = trim(etree.IterText(block, " "))
+	}
+
+	// Find the repeated blocks at the start and the end of page
+	isSeen := func(i int) bool {
+		_, seen := pm.seenBlocks[blockTexts[i]]
+		return blockTexts[i] != "" && seen
+	}
+
+	start, end := 0, len(blocks)
+	for start < end && isSeen(start) {
+		start++
+	}
+	for end > start && isSeen(end-1) {
+		end--
+	}
+
+	// Move the remaining blocks into the merged content. Since some blocks are
+	// removed, the page text must be generated again.
+	var texts []string
+	for i := start; i < end; i++ {
+		if text := blockTexts[i]; text != "" {
+			pm.seenBlocks[text] = struct{}{}
+			texts = append(texts, text)
+		}
+		dom.AppendChild(pm.first.ContentNode, blocks[i])
+	}
+	pm.addSegment(strings.Join(texts, " "), pageURL)
+
+	// Only keep language of the blocks that moved
+	for _, block := range result.BlockLanguages {
+		if block.Node.Parent == pm.first.ContentNode {
+			pm.first.BlockLanguages = append(pm.first.BlockLanguages, block)
+		}
+	}
+
+	// Merge the comments as well
+	if result.CommentsNode != nil {
+		if pm.first.CommentsNode == nil {
+			pm.first.CommentsNode = result.CommentsNode
+		} else {
+			for _, child := range dom.ChildNodes(result.CommentsNode) {
+				dom.AppendChild(pm.first.CommentsNode, child)
+			}
+		}
+		pm.first.CommentsText = strings.TrimSpace(pm.first.CommentsText + " " + result.CommentsText)
+	}
+	pm.first.Comments = append(pm.first.Comments, result.Comments...)
+}
+
+func (pm *pageMerger) addSegment(text string, pageURL *nurl.URL) {
+	var start int
+	for _, t := range pm.texts {
+		start += len(t) + 1
+	}
+
+	var strURL string
+	if pageURL != nil {
+		strURL = pageURL.String()
+	}
+
+	pm.texts = append(pm.texts, text)
+	pm.pages = append(pm.pages, PageSegment{
+		Number:    len(pm.pages) + 1,
+		URL:       strURL,
+		TextStart: start,
+		TextEnd:   start + len(text),
+	})
+}
+
+func (pm *pageMerger) result() *ExtractResult {
+	pm.first.ContentText = strings.Join(pm.texts, " ")
+	pm.first.Pages = pm.pages
+	return pm.first
+}
+
+// findNextPageURL looks for the link to the next page of article. The next page
+// must be in the same host as the current page.
+func findNextPageURL(doc *html.Node, pageURL *nurl.URL) *nurl.URL {
+	validate := func(href string) *nurl.URL {
+		href = strings.TrimSpace(href)
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") {
+			return nil
+		}
+
+		var nextURL *nurl.URL
+		var err error
+		if pageURL != nil {
+			nextURL, err = pageURL.Parse(href)
+		} else {
+			nextURL, err = nurl.Parse(href)
+		}
+
+		if err != nil || !nextURL.IsAbs() || (nextURL.Scheme != "http" && nextURL.Scheme != "https") {
+			return nil
+		}
+
+		if pageURL != nil {
+			if nextURL.Hostname() != pageURL.Hostname() || normalizePageURL(nextURL) == normalizePageURL(pageURL) {
+				return nil
+			}
+		}
+
+		nextURL.Fragment = ""
+		return nextURL
+	}
+
+	// Check the explicit relation first. Many blog themes use it to link the next
+	// post, so it's only followed when it's inside pagination or it points to the
+	// next page of the same article.
+	currentNumber := pageNumber(pageURL)
+	for _, link := range dom.QuerySelectorAll(doc, `link[rel~="next"], a[rel~="next"]`) {
+		nextURL := validate(dom.GetAttribute(link, "href"))
+		if nextURL == nil {
+			continue
+		}
+
+		isNextPage := pageURL != nil && pageNumber(nextURL) == currentNumber+1 &&
+			articleBaseURL(nextURL) == articleBaseURL(pageURL)
+		if isNextPage || (dom.TagName(link) == "a" && isInPagination(link)) {
+			return nextURL
+		}
+	}
+
+	// Check the links inside pagination
+	for _, a := range dom.QuerySelectorAll(doc, "a[href]") {
+		text := trim(dom.TextContent(a))
+		inPagination := isInPagination(a)
+		isNext := rxNextPageText.MatchString(text) || strings.Contains(strings.ToLower(dom.ClassName(a)), "next")
+		isNextNumber := inPagination && text == strconv.Itoa(currentNumber+1)
+		if (isNext && inPagination) || isNextNumber {
+			if nextURL := validate(dom.GetAttribute(a, "href")); nextURL != nil {
+				return nextURL
+			}
+		}
+	}
+
+	return nil
+}
+
+// isInPagination checks whether the node is inside pagination container.
+func isInPagination(node *html.Node) bool {
+	for parent := node; parent != nil && parent.Type == html.ElementNode; parent = parent.Parent {
+		if rxPaginationClass.MatchString(dom.ClassName(parent)) || rxPaginationClass.MatchString(dom.ID(parent)) {
+			return true
+		}
+	}
+	return false
+}
+
+// pageNumber returns the page number that specified in URL, default to 1.
+func pageNumber(pageURL *nurl.URL) int {
+	if pageURL == nil {
+		return 1
+	}
+
+	for key, values := range pageURL.Query() {
+		if rxPageNumberQuery.MatchString(key) && len(values) > 0 {
+			if number, err := strconv.Atoi(values[0]); err == nil {
+				return number
+			}
+		}
+	}
+
+	if match := rxPageNumberPath.FindStringSubmatch(pageURL.Path); match != nil {
+		if number, err := strconv.Atoi(match[1] + match[2]); err == nil && number < 1000 {
+			return number
+		}
+	}
+
+	return 1
+}
+
+// articleBaseURL returns the URL of article without its page number, so the pages
+// of the same article have the same base URL.
+func articleBaseURL(u *nurl.URL) string {
+	query := u.Query()
+	for key := range query {
+		if rxPageNumberQuery.MatchString(key) {
+			query.Del(key)
+		}
+	}
+
+	path := rxPageNumberPath.ReplaceAllString(u.Path, "")
+	return fmt.Sprintf("%s%s?%s", u.Host, strings.TrimSuffix(path, "/"), query.Encode())
+}
+
+func normalizePageURL(u *nurl.URL) string {
+	return fmt.Sprintf("%s%s?%s", u.Host, strings.TrimSuffix(u.Path, "/"), u.RawQuery)
+}
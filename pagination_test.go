@@ -0,0 +1,139 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"fmt"
+	nurl "net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExtractPages(t *testing.T) {
+	createPage := func(number int, navigation string) string {
+		return fmt.Sprintf(`<html><head><title>Long story</title>%s</head><body><article>
+			<p>This story is published by Example Magazine, all rights reserved.</p>
+			<p>%s</p>
+			<blockquote>We will rebuild the old bridge, the mayor said.</blockquote>
+			<p>%s</p>
+			<div class="pagination">%s</div>
+		</article></body></html>`,
+			map[bool]string{true: `<link rel="next" href="/story?page=2"/>`}[number == 1],
+			strings.Repeat(fmt.Sprintf("Content of page number %d. ", number), 20),
+			strings.Repeat(fmt.Sprintf("More content of page number %d. ", number), 20),
+			navigation)
+	}
+
+	pages := map[string]string{
+		"https://example.org/story?page=2": createPage(2, `<a href="/story">1</a> 2 <a href="/story?page=3">3</a>`),
+		"https://example.org/story?page=3": createPage(3, `<a href="/story">1</a> <a href="/story?page=2">2</a> 3`),
+	}
+
+	var fetched []string
+	fetcher := func(pageURL string) ([]byte, error) {
+		fetched = append(fetched, pageURL)
+		if page, exist := pages[pageURL]; exist {
+			return []byte(page), nil
+		}
+		return nil, fmt.Errorf("not found: %s", pageURL)
+	}
+
+	opts := zeroOpts
+	opts.OriginalURL, _ = nurl.Parse("https://example.org/story")
+	opts.EnableFallback = false
+
+	firstPage := createPage(1, `1 <a href="/story?page=2">2</a> <a href="/story?page=3">3</a>`)
+	result, err := ExtractPages(strings.NewReader(firstPage), opts, fetcher, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://example.org/story?page=2", "https://example.org/story?page=3"}, fetched)
+
+	// Each page is recorded
+	assert.Len(t, result.Pages, 3)
+	for i, page := range result.Pages {
+		pageText := result.ContentText[page.TextStart:page.TextEnd]
+		assert.Equal(t, i+1, page.Number)
+		assert.Contains(t, pageText, fmt.Sprintf("Content of page number %d.", i+1))
+	}
+	assert.Equal(t, "https://example.org/story?page=3", result.Pages[2].URL)
+
+	// Repeated header only kept once, while repeated body paragraph is kept
+	assert.Equal(t, 1, strings.Count(result.ContentText, "published by Example Magazine"))
+	assert.Equal(t, 3, strings.Count(result.ContentText, "the mayor said"))
+	assert.Contains(t, result.ContentText, "Content of page number 3.")
+
+	// Limit the number of pages
+	fetched = nil
+	result, err = ExtractPages(strings.NewReader(firstPage), opts, fetcher, 2)
+	assert.NoError(t, err)
+	assert.Len(t, result.Pages, 2)
+	assert.Len(t, fetched, 1)
+}
+
+func Test_FindNextPageURL(t *testing.T) {
+	baseURL, _ := nurl.Parse("https://example.org/news/story/page/2")
+
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{{
+		name:     "rel next",
+		html:     `<html><head><link rel="next" href="/news/story/page/3"/></head><body></body></html>`,
+		expected: "https://example.org/news/story/page/3",
+	}, {
+		name:     "next text in pagination",
+		html:     `<html><body><nav class="pager"><a href="page/1">Previous</a><a href="3">Next page »</a></nav></body></html>`,
+		expected: "https://example.org/news/story/page/3",
+	}, {
+		name:     "page number",
+		html:     `<html><body><ul id="pagination"><li><a href="/news/story">1</a></li><li><a href="/news/story/page/3">3</a></li></ul></body></html>`,
+		expected: "https://example.org/news/story/page/3",
+	}, {
+		name:     "rel next to another post",
+		html:     `<html><head><link rel="next" href="/news/another-story"/></head><body></body></html>`,
+		expected: "",
+	}, {
+		name:     "rel next to other page of the same story",
+		html:     `<html><head><link rel="next" href="/news/story/page/5"/></head><body></body></html>`,
+		expected: "",
+	}, {
+		name:     "rel next inside pagination",
+		html:     `<html><body><div class="pagination"><a rel="next" href="/news/story?part=b">Continue</a></div></body></html>`,
+		expected: "https://example.org/news/story?part=b",
+	}, {
+		name:     "other host",
+		html:     `<html><head><link rel="next" href="https://other.org/page/3"/></head><body></body></html>`,
+		expected: "",
+	}, {
+		name:     "next outside pagination",
+		html:     `<html><body><a href="/news/another-story">Next</a></body></html>`,
+		expected: "",
+	}}
+
+	for _, test := range tests {
+		nextURL := findNextPageURL(docFromStr(test.html), baseURL)
+		if test.expected == "" {
+			assert.Nil(t, nextURL, test.name)
+		} else if assert.NotNil(t, nextURL, test.name) {
+			assert.Equal(t, test.expected, nextURL.String(), test.name)
+		}
+	}
+}
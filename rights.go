@@ -0,0 +1,434 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Rights is the normalized rights information of the page, used to decide whether
+// its content can be reused.
+type Rights struct {
+	// License is the SPDX identifier of the license, e.g. "CC-BY-SA-4.0" or "MIT".
+	// Creative Commons license without known version is written without it, e.g.
+	// "CC-BY-NC". Empty if the license is not found or can't be identified.
+	License string
+
+	// LicenseURL is the URL of the license, if any.
+	LicenseURL string
+
+	// LicenseSource is where the license is found: "rel-license", "json-ld",
+	// "meta" or "footer".
+	LicenseSource string
+
+	// CopyrightHolder and CopyrightYear is taken from JSON+LD or from the
+	// copyright notice of the page. For range of years, the latest one is used.
+	CopyrightHolder string
+	CopyrightYear   int
+
+	// AllRightsReserved is true if the page states that all rights are reserved.
+	AllRightsReserved bool
+
+	// NoAI and NoImageAI is true if the page opts out from AI training using the
+	// `noai` and `noimageai` robots directives.
+	NoAI      bool
+	NoImageAI bool
+
+	// TDMReserved is true if the text and data mining rights are reserved following
+	// the TDM Reservation Protocol (TDMRep). TDMPolicy is the URL of its policy.
+	TDMReserved bool
+	TDMPolicy   string
+}
+
+// RightsRestriction is the restriction of content reuse that stated in the page.
+type RightsRestriction int
+
+const (
+	// AllRightsReserved means the page states that all rights are reserved.
+	AllRightsReserved RightsRestriction = iota
+
+	// NoAI means the page opts out from AI training with `noai` robots directive.
+	NoAI
+
+	// NoImageAI means the page opts out from AI training on its images with
+	// `noimageai` robots directive.
+	NoImageAI
+
+	// TDMReserved means the text and data mining rights are reserved.
+	TDMReserved
+)
+
+func (rr RightsRestriction) String() string {
+	switch rr {
+	case AllRightsReserved:
+		return "all-rights-reserved"
+	case NoAI:
+		return "noai"
+	case NoImageAI:
+		return "noimageai"
+	case TDMReserved:
+		return "tdm-reserved"
+	default:
+		return fmt.Sprintf("RightsRestriction(%d)", rr)
+	}
+}
+
+// Restrictions returns the reuse restrictions that stated in the rights.
+func (r Rights) Restrictions() []RightsRestriction {
+	var restrictions []RightsRestriction
+	if r.AllRightsReserved {
+		restrictions = append(restrictions, AllRightsReserved)
+	}
+
+	if r.NoAI {
+		restrictions = append(restrictions, NoAI)
+	}
+
+	if r.NoImageAI {
+		restrictions = append(restrictions, NoImageAI)
+	}
+
+	if r.TDMReserved {
+		restrictions = append(restrictions, TDMReserved)
+	}
+
+	return restrictions
+}
+
+// RightsError is the error that returned when the rights of web page doesn't pass
+// `RejectedRights` or `AllowedLicenses` option.
+type RightsError struct {
+	Rights Rights
+	Reason string
+}
+
+func (err *RightsError) Error() string {
+	return "page rights are rejected: " + err.Reason
+}
+
+var (
+	rxCcLicenseName = regexp.MustCompile(`(?i)creative\s+commons\s+((?:attribution|namensnennung|paternité|reconocimiento|atribución)` +
+		`(?:[\s,-]+(?:non-?commercial|no-?deriv(?:ative)?s?|share-?alike|nicht-?kommerziell|keine\s+bearbeitungen|` +
+		`weitergabe\s+unter\s+gleichen\s+bedingungen))*)(?:\s+([1-9]\.[0-9]))?`)
+	rxCcLicenseShort = regexp.MustCompile(`(?i)\b(?:cc|creative\s+commons)[\s-](by-nc-nd|by-nc-sa|by-nc|by-nd|by-sa|by)\b(?:[\s-]([1-9]\.[0-9]))?`)
+	rxCcZero         = regexp.MustCompile(`(?i)creativecommons\.org/publicdomain/zero/([1-9]\.[0-9])|\bcc0\b|\bcc[\s-]zero\b`)
+	rxCopyright      = regexp.MustCompile(`(?i)(?:©|\(c\)|copyright)\s*(?:©|\(c\))?\s*(?:(\d{4})(?:\s*[-–—]\s*(\d{4}))?)?[\s,.:]*([^|·•\n]{0,100})`)
+	rxRightsReserved = regexp.MustCompile(`(?i)all\s+rights\s+reserved|alle\s+rechte\s+vorbehalten|tous\s+droits\s+réservés|` +
+		`todos\s+los\s+derechos\s+reservados|tutti\s+i\s+diritti\s+riservati|alle\s+rechten\s+voorbehouden|` +
+		`todos\s+os\s+direitos\s+reservados`)
+	rxCopyrightHolderEnd = regexp.MustCompile(`(?i)[.,;]?\s*(?:all\s+rights|alle\s+rechte|tous\s+droits|todos\s+los|tutti\s+i|alle\s+rechten|todos\s+os|\.\s|$)`)
+)
+
+// Rights selectors, looked in the footer and copyright notices.
+const rightsNoticeSelector = `footer, [class*="footer"], [id*="footer"], [class*="copyright"], [id*="copyright"], ` +
+	`[class*="license"], [id*="license"], [class*="rights"]`
+
+// spdxLicenseURLs maps the URL of common non-CC licenses to their SPDX identifier.
+var spdxLicenseURLs = map[string]string{
+	"opensource.org/licenses/mit":           "MIT",
+	"opensource.org/license/mit":            "MIT",
+	"apache.org/licenses/license-2.0":       "Apache-2.0",
+	"opensource.org/licenses/apache-2.0":    "Apache-2.0",
+	"opensource.org/licenses/bsd-2-clause":  "BSD-2-Clause",
+	"opensource.org/licenses/bsd-3-clause":  "BSD-3-Clause",
+	"gnu.org/licenses/gpl-2.0":              "GPL-2.0-only",
+	"gnu.org/licenses/gpl-3.0":              "GPL-3.0-only",
+	"gnu.org/licenses/lgpl-3.0":             "LGPL-3.0-only",
+	"gnu.org/licenses/agpl-3.0":             "AGPL-3.0-only",
+	"gnu.org/licenses/fdl-1.3":              "GFDL-1.3-only",
+	"mozilla.org/mpl/2.0":                   "MPL-2.0",
+	"opendatacommons.org/licenses/odbl":     "ODbL-1.0",
+	"opendatacommons.org/licenses/by":       "ODC-By-1.0",
+	"opendatacommons.org/licenses/pddl":     "PDDL-1.0",
+	"unlicense.org":                         "Unlicense",
+	"creativecommons.org/publicdomain/mark": "LicenseRef-PublicDomain",
+}
+
+// extractRights looks for the license, copyright notice and reuse reservations of
+// the page, in its markup, JSON+LD, meta tags and HTTP response header.
+func extractRights(doc *html.Node, opts Options) Rights {
+	var rights Rights
+
+	// License from the links labeled as license
+	for _, node := range dom.QuerySelectorAll(doc, `a[rel~="license"][href], link[rel~="license"][href]`) {
+		href := dom.GetAttribute(node, "href")
+		if license := identifyLicense(href, dom.TextContent(node)); license != "" {
+			rights.License, rights.LicenseURL, rights.LicenseSource = license, href, "rel-license"
+			break
+		}
+	}
+
+	// License and copyright from JSON+LD
+	articles := jsonLdSchemas(doc, opts).articles
+	for _, article := range articles {
+		if rights.License == "" {
+			for _, value := range schemaLicenseValues(article.Data["license"]) {
+				if license := identifyLicense(value, value); license != "" {
+					rights.License, rights.LicenseSource = license, "json-ld"
+					if isAbs, _ := isAbsoluteURL(value); isAbs {
+						rights.LicenseURL = value
+					}
+					break
+				}
+			}
+		}
+
+		if rights.CopyrightHolder == "" {
+			rights.CopyrightHolder = strings.Join(getSchemaNames(article.Data["copyrightHolder"]), "; ")
+		}
+
+		// Year might be written as number or string
+		if rights.CopyrightYear == 0 {
+			switch year := article.Data["copyrightYear"].(type) {
+			case float64:
+				rights.CopyrightYear = int(year)
+			case string:
+				rights.CopyrightYear, _ = strconv.Atoi(trim(year))
+			}
+		}
+	}
+
+	// Meta tags, for license, robots directives and TDMRep
+	var notices []string
+	for _, node := range dom.QuerySelectorAll(doc, "meta[name][content]") {
+		name := strings.ToLower(trim(dom.GetAttribute(node, "name")))
+		content := trim(dom.GetAttribute(node, "content"))
+		switch {
+		case strIn(name, "dcterms.license", "dc.rights", "dc:rights", "dcterms.rights", "dcterms.accessrights", "rights", "copyright"):
+			notices = append(notices, content)
+			if license := identifyLicense(content, content); rights.License == "" && license != "" {
+				rights.License, rights.LicenseSource = license, "meta"
+				if isAbs, _ := isAbsoluteURL(content); isAbs {
+					rights.LicenseURL = content
+				}
+			}
+		case name == "robots" || strings.HasSuffix(name, "bot"):
+			applyRobotsDirectives(&rights, content)
+		case name == "tdm-reservation":
+			rights.TDMReserved = rights.TDMReserved || content == "1"
+		case name == "tdm-policy":
+			rights.TDMPolicy = strOr(rights.TDMPolicy, content)
+		}
+	}
+
+	// HTTP response header
+	if opts.ResponseHeader != nil {
+		for _, value := range opts.ResponseHeader.Values("X-Robots-Tag") {
+			applyRobotsDirectives(&rights, value)
+		}
+
+		rights.TDMReserved = rights.TDMReserved || trim(opts.ResponseHeader.Get("TDM-Reservation")) == "1"
+		rights.TDMPolicy = strOr(rights.TDMPolicy, trim(opts.ResponseHeader.Get("TDM-Policy")))
+	}
+
+	// Footer and copyright notices
+	for _, node := range dom.QuerySelectorAll(doc, rightsNoticeSelector) {
+		noticeText := commentText(node, func(*html.Node) bool { return false })
+		notices = append(notices, strings.Split(noticeText, "\n")...)
+		if rights.License != "" {
+			continue
+		}
+
+		for _, a := range dom.QuerySelectorAll(node, "a[href]") {
+			href := dom.GetAttribute(a, "href")
+			if license := identifyLicense(href, ""); license != "" {
+				rights.License, rights.LicenseURL, rights.LicenseSource = license, href, "footer"
+				break
+			}
+		}
+	}
+
+	for _, notice := range notices {
+		if rights.License == "" {
+			if license := identifyLicense("", notice); license != "" {
+				rights.License, rights.LicenseSource = license, "footer"
+			}
+		}
+
+		rights.AllRightsReserved = rights.AllRightsReserved || rxRightsReserved.MatchString(notice)
+		if rights.CopyrightHolder == "" || rights.CopyrightYear == 0 {
+			holder, year := parseCopyrightNotice(notice)
+			rights.CopyrightHolder = strOr(rights.CopyrightHolder, holder)
+			if rights.CopyrightYear == 0 {
+				rights.CopyrightYear = year
+			}
+		}
+	}
+
+	// Make sure license URL is absolute
+	if rights.LicenseURL != "" {
+		validURL, isAbs := validateURL(rights.LicenseURL, opts.OriginalURL)
+		rights.LicenseURL = ""
+		if isAbs {
+			rights.LicenseURL = validURL
+		}
+	}
+
+	return rights
+}
+
+// identifyLicense returns the SPDX identifier of license from its URL or name.
+func identifyLicense(url, text string) string {
+	url = strings.ToLower(trim(url))
+	text = trim(text)
+
+	if parts := rxCcZero.FindStringSubmatch(url + " " + text); parts != nil {
+		return "CC0-" + strOr(parts[1], "1.0")
+	}
+
+	if parts := rxCcLicense.FindStringSubmatch(url); parts != nil && strings.Contains(url, "creativecommons") {
+		return "CC-" + strings.ToUpper(parts[1]) + "-" + parts[2]
+	}
+
+	for licenseURL, spdx := range spdxLicenseURLs {
+		if url != "" && strings.Contains(url, licenseURL) {
+			return spdx
+		}
+	}
+
+	if parts := rxCcLicenseShort.FindStringSubmatch(text); parts != nil {
+		return strings.TrimSuffix("CC-"+strings.ToUpper(parts[1])+"-"+parts[2], "-")
+	}
+
+	if parts := rxCcLicenseName.FindStringSubmatch(text); parts != nil {
+		return ccLicenseFromName(parts[1], parts[2])
+	}
+
+	return ""
+}
+
+// ccLicenseFromName converts the long name of Creative Commons license, e.g.
+// "Attribution-NonCommercial-ShareAlike", into its SPDX identifier.
+func ccLicenseFromName(name, version string) string {
+	name = strings.ToLower(name)
+	license := "CC-BY"
+	if strings.Contains(name, "commercial") || strings.Contains(name, "kommerziell") {
+		license += "-NC"
+	}
+
+	if strings.Contains(name, "deriv") || strings.Contains(name, "bearbeitung") {
+		license += "-ND"
+	} else if strings.Contains(name, "alike") || strings.Contains(name, "gleichen") {
+		license += "-SA"
+	}
+
+	if version != "" {
+		license += "-" + version
+	}
+	return license
+}
+
+// schemaLicenseValues returns the license in JSON+LD, which might be URL, name or
+// CreativeWork.
+func schemaLicenseValues(v any) []string {
+	var values []string
+	switch value := v.(type) {
+	case string:
+		values = append(values, value)
+	case []any:
+		for _, item := range value {
+			values = append(values, schemaLicenseValues(item)...)
+		}
+	case map[string]any:
+		values = append(values, getStringValues(value, "url")...)
+		values = append(values, getStringValues(value, "@id")...)
+		values = append(values, getStringValues(value, "name")...)
+	}
+	return values
+}
+
+// applyRobotsDirectives marks the AI opt-out in robots directives, e.g. "noai,
+// noimageai" in meta tag or "otherbot: noai" in `X-Robots-Tag` header.
+func applyRobotsDirectives(rights *Rights, directives string) {
+	for directive := range strings.FieldsFuncSeq(strings.ToLower(directives), func(r rune) bool {
+		return r == ',' || r == ' ' || r == ':'
+	}) {
+		switch directive {
+		case "noai":
+			rights.NoAI = true
+		case "noimageai":
+			rights.NoImageAI = true
+		}
+	}
+}
+
+// parseCopyrightNotice returns the copyright holder and the latest year from the
+// notice, e.g. "© 2019-2024 Example Media. All rights reserved."
+func parseCopyrightNotice(notice string) (string, int) {
+	parts := rxCopyright.FindStringSubmatch(notice)
+	if parts == nil || parts[1] == "" {
+		return "", 0
+	}
+
+	year, _ := strconv.Atoi(strOr(parts[2], parts[1]))
+	if year < 1900 || year > 2100 {
+		year = 0
+	}
+
+	holder := parts[3]
+	if loc := rxCopyrightHolderEnd.FindStringIndex(holder); loc != nil {
+		holder = holder[:loc[0]]
+	}
+
+	holder = trim(holder)
+	if len(holder) > 3 && strings.EqualFold(holder[:3], "by ") {
+		holder = holder[3:]
+	}
+	if strLength(holder) < 2 || strWordCount(holder) > 8 {
+		holder = ""
+	}
+
+	return holder, year
+}
+
+// checkRights checks whether the rights of the page pass the restrictions and the
+// licenses that allowed by user.
+func checkRights(rights Rights, opts Options) error {
+	for _, restriction := range rights.Restrictions() {
+		for _, rejected := range opts.RejectedRights {
+			if restriction == rejected {
+				return &RightsError{Rights: rights, Reason: restriction.String()}
+			}
+		}
+	}
+
+	if len(opts.AllowedLicenses) == 0 {
+		return nil
+	}
+
+	for _, allowed := range opts.AllowedLicenses {
+		allowed = strings.ToUpper(trim(allowed))
+		license := strings.ToUpper(rights.License)
+		if license == "" {
+			break
+		}
+
+		if prefix, isWildcard := strings.CutSuffix(allowed, "*"); (isWildcard && strings.HasPrefix(license, prefix)) || allowed == license {
+			return nil
+		}
+	}
+
+	return &RightsError{Rights: rights, Reason: fmt.Sprintf("license %q is not allowed", rights.License)}
+}
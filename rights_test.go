@@ -0,0 +1,114 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExtractRights(t *testing.T) {
+	// License from rel=license, copyright from footer
+	doc := docFromStr(`<html><body><article><p>Text</p></article><footer>
+		<p>© 2019–2024 Example Media Inc. All rights reserved.</p>
+		<a rel="license" href="https://creativecommons.org/licenses/by-sa/4.0/">CC BY-SA</a>
+		</footer></body></html>`)
+	rights := extractRights(doc, zeroOpts)
+	assert.Equal(t, "CC-BY-SA-4.0", rights.License)
+	assert.Equal(t, "https://creativecommons.org/licenses/by-sa/4.0/", rights.LicenseURL)
+	assert.Equal(t, "rel-license", rights.LicenseSource)
+	assert.Equal(t, "Example Media Inc", rights.CopyrightHolder)
+	assert.Equal(t, 2024, rights.CopyrightYear)
+	assert.True(t, rights.AllRightsReserved)
+	assert.Equal(t, []RightsRestriction{AllRightsReserved}, rights.Restrictions())
+
+	// License and copyright from JSON+LD
+	doc = docFromStr(`<html><head><script type="application/ld+json">{"@type":"NewsArticle",
+		"license":{"@type":"CreativeWork","url":"https://opensource.org/licenses/MIT"},
+		"copyrightHolder":{"@type":"Organization","name":"Daily News"},"copyrightYear":2021}</script>
+		</head><body></body></html>`)
+	rights = extractRights(doc, zeroOpts)
+	assert.Equal(t, "MIT", rights.License)
+	assert.Equal(t, "json-ld", rights.LicenseSource)
+	assert.Equal(t, "Daily News", rights.CopyrightHolder)
+	assert.Equal(t, 2021, rights.CopyrightYear)
+	assert.False(t, rights.AllRightsReserved)
+
+	// License from meta tag and footer text
+	doc = docFromStr(`<html><head><meta name="dcterms.license" content="https://creativecommons.org/publicdomain/zero/1.0/"/></head></html>`)
+	assert.Equal(t, "CC0-1.0", extractRights(doc, zeroOpts).License)
+
+	doc = docFromStr(`<html><body><div class="site-footer">Content is available under
+		Creative Commons Attribution-NonCommercial-ShareAlike 3.0 unless otherwise noted.</div></body></html>`)
+	rights = extractRights(doc, zeroOpts)
+	assert.Equal(t, "CC-BY-NC-SA-3.0", rights.License)
+	assert.Equal(t, "footer", rights.LicenseSource)
+
+	// Reservations in meta tags and HTTP header
+	doc = docFromStr(`<html><head><meta name="robots" content="index, follow, noai, noimageai"/>
+		<meta name="tdm-reservation" content="1"/><meta name="tdm-policy" content="https://example.org/tdm.json"/>
+		</head><body></body></html>`)
+	rights = extractRights(doc, zeroOpts)
+	assert.True(t, rights.NoAI)
+	assert.True(t, rights.NoImageAI)
+	assert.True(t, rights.TDMReserved)
+	assert.Equal(t, "https://example.org/tdm.json", rights.TDMPolicy)
+
+	opts := zeroOpts
+	opts.ResponseHeader = http.Header{}
+	opts.ResponseHeader.Set("X-Robots-Tag", "otherbot: noai")
+	opts.ResponseHeader.Set("TDM-Reservation", "1")
+	rights = extractRights(docFromStr(`<html><body></body></html>`), opts)
+	assert.True(t, rights.NoAI)
+	assert.False(t, rights.NoImageAI)
+	assert.True(t, rights.TDMReserved)
+}
+
+func Test_RightsFilter(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("The museum reopened its doors after two years of renovation. ", 4) + "</p>"
+	rawHTML := `<html><head><meta name="robots" content="noai"/></head><body><article>` + paragraph +
+		`</article><footer><a rel="license" href="https://creativecommons.org/licenses/by/4.0/">CC BY 4.0</a></footer></body></html>`
+
+	opts := zeroOpts
+	result, err := ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "CC-BY-4.0", result.Metadata.Rights.License)
+	assert.True(t, result.Metadata.Rights.NoAI)
+
+	// Rejected restriction
+	opts.RejectedRights = []RightsRestriction{TDMReserved, NoAI}
+	_, err = ExtractDocument(docFromStr(rawHTML), opts)
+	var rightsErr *RightsError
+	assert.True(t, errors.As(err, &rightsErr))
+	assert.Equal(t, "noai", rightsErr.Reason)
+
+	// Allowed licenses
+	opts.RejectedRights = nil
+	opts.AllowedLicenses = []string{"CC-BY*"}
+	_, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+
+	opts.AllowedLicenses = []string{"CC0-1.0"}
+	_, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.True(t, errors.As(err, &rightsErr))
+}
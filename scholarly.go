@@ -0,0 +1,518 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// ScholarlyRecord is the bibliographic record of a scholarly work, e.g. journal
+// article, preprint or thesis. It's taken from Highwire Press (`citation_*`),
+// PRISM and Dublin Core meta tags, and from `ScholarlyArticle` in JSON+LD.
+type ScholarlyRecord struct {
+	// Type is the type of the work using CSL vocabulary, e.g. "article-journal",
+	// "paper-conference", "preprint", "thesis", "report" or "chapter".
+	Type string
+
+	Title   string
+	Authors []ScholarlyAuthor
+
+	// Date is the publication date. DateParts is the number of its meaningful
+	// parts: 1 if only the year is known, 2 for year and month, 3 for full date.
+	Date      time.Time
+	DateParts int
+
+	Journal       string
+	JournalAbbrev string
+	Conference    string
+	Institution   string
+	Publisher     string
+	Volume        string
+	Issue         string
+	FirstPage     string
+	LastPage      string
+	ISSN          []string
+	ISBN          string
+	DOI           string
+	ArXivID       string
+	URL           string
+	PDFURL        string
+	Abstract      string
+	Keywords      []string
+	Language      string
+}
+
+// ScholarlyAuthor is the author of scholarly work.
+type ScholarlyAuthor struct {
+	// Name is the full name as written in the page. Given and Family are only
+	// available if the name can be split reliably.
+	Name         string
+	Given        string
+	Family       string
+	ORCID        string
+	Affiliations []string
+}
+
+var (
+	rxDOI       = regexp.MustCompile(`(?i)\b(10\.\d{4,9}/[^\s"'<>]+)`)
+	rxArXivID   = regexp.MustCompile(`(?i)(?:arxiv:|arxiv\.org/(?:abs|pdf)/)?(\d{4}\.\d{4,5}(?:v\d+)?)`)
+	rxORCID     = regexp.MustCompile(`(\d{4}-\d{4}-\d{4}-\d{3}[\dX])`)
+	rxPageRange = regexp.MustCompile(`^\s*(\w+)\s*[-–—]+\s*(\w+)\s*$`)
+)
+
+// scholarlyDateFormats is the date formats used in scholarly meta tags, ordered
+// from the most precise. The number is the date parts in the format.
+var scholarlyDateFormats = []struct {
+	layout string
+	parts  int
+}{
+	{time.RFC3339, 3},
+	{"2006-01-02T15:04:05", 3},
+	{"2006-01-02", 3},
+	{"2006/01/02", 3},
+	{"2006-1-2", 3},
+	{"2006/1/2", 3},
+	{"January 2, 2006", 3},
+	{"2 January 2006", 3},
+	{"2006-01", 2},
+	{"2006/01", 2},
+	{"January 2006", 2},
+	{"2006", 1},
+}
+
+// scholarlySchemaTypes is the JSON+LD types of scholarly work, with its CSL type.
+var scholarlySchemaTypes = map[string]string{
+	"scholarlyarticle":        "article-journal",
+	"medicalscholarlyarticle": "article-journal",
+	"thesis":                  "thesis",
+	"chapter":                 "chapter",
+}
+
+// scholarlyMetaTags is the meta tags that used in scholarly pages, collected by
+// their lowercase name in the order of appearance.
+type scholarlyMetaTags struct {
+	names  []string
+	values []string
+}
+
+func (tags scholarlyMetaTags) first(names ...string) string {
+	for _, name := range names {
+		for i, tagName := range tags.names {
+			if tagName == name && tags.values[i] != "" {
+				return tags.values[i]
+			}
+		}
+	}
+	return ""
+}
+
+func (tags scholarlyMetaTags) all(names ...string) []string {
+	var values []string
+	for i, tagName := range tags.names {
+		if strIn(tagName, names...) {
+			values = append(values, tags.values[i])
+		}
+	}
+	return values
+}
+
+func (tags scholarlyMetaTags) hasPrefix(prefixes ...string) bool {
+	for _, name := range tags.names {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractScholarlyMetadata creates the bibliographic record of the page. Returns
+// nil if the page is not a scholarly work.
+func extractScholarlyMetadata(doc *html.Node, opts Options, metadata Metadata) *ScholarlyRecord {
+	// Collect the meta tags. Bepress uses the same names as Highwire, with prefix.
+	var tags scholarlyMetaTags
+	for _, node := range dom.QuerySelectorAll(doc, "meta[name][content]") {
+		name := strings.ToLower(trim(dom.GetAttribute(node, "name")))
+		name = strings.TrimPrefix(name, "bepress_")
+		name = strings.Replace(name, "dc:", "dc.", 1)
+		content := trim(html.UnescapeString(dom.GetAttribute(node, "content")))
+		if content != "" {
+			tags.names = append(tags.names, name)
+			tags.values = append(tags.values, content)
+		}
+	}
+
+	record := scholarlyFromMetaTags(tags)
+	isScholarly := tags.hasPrefix("citation_", "prism.") || record.DOI != ""
+
+	// Fill the missing fields from JSON+LD
+	if schema, cslType := findScholarlySchema(doc, opts); schema != nil {
+		isScholarly = true
+		fillScholarlyFromJsonLd(&record, schema, cslType)
+	}
+
+	if !isScholarly {
+		return nil
+	}
+
+	// Use the general metadata as the last resort
+	record.Title = strOr(record.Title, metadata.Title)
+	record.URL = strOr(record.URL, metadata.URL)
+	record.Language = strOr(record.Language, metadata.Language)
+	if record.Date.IsZero() && !metadata.Date.IsZero() {
+		record.Date, record.DateParts = metadata.Date, 3
+	}
+
+	if len(record.Authors) == 0 && metadata.Author != "" {
+		for _, name := range strings.Split(metadata.Author, "; ") {
+			record.Authors = append(record.Authors, newScholarlyAuthor(name))
+		}
+	}
+
+	// Make sure the URLs are absolute
+	for _, url := range []*string{&record.URL, &record.PDFURL} {
+		if *url != "" {
+			validURL, isAbs := validateURL(*url, opts.OriginalURL)
+			*url = ""
+			if isAbs {
+				*url = validURL
+			}
+		}
+	}
+
+	if record.Type == "" {
+		record.Type = guessScholarlyType(record)
+	}
+
+	record.ISSN = uniquifyLists(record.ISSN...)
+	record.Keywords = uniquifyLists(record.Keywords...)
+	return &record
+}
+
+func scholarlyFromMetaTags(tags scholarlyMetaTags) ScholarlyRecord {
+	record := ScholarlyRecord{
+		Title:         tags.first("citation_title", "dc.title", "dcterms.title"),
+		Journal:       tags.first("citation_journal_title", "prism.publicationname", "dc.relation.ispartof", "dcterms.ispartof"),
+		JournalAbbrev: tags.first("citation_journal_abbrev"),
+		Conference:    tags.first("citation_conference_title", "citation_conference"),
+		Institution:   tags.first("citation_dissertation_institution", "citation_technical_report_institution"),
+		Publisher:     tags.first("citation_publisher", "dc.publisher", "dcterms.publisher"),
+		Volume:        tags.first("citation_volume", "prism.volume"),
+		Issue:         tags.first("citation_issue", "prism.number", "prism.issueidentifier"),
+		FirstPage:     tags.first("citation_firstpage", "prism.startingpage"),
+		LastPage:      tags.first("citation_lastpage", "prism.endingpage"),
+		ISBN:          tags.first("citation_isbn", "prism.isbn"),
+		URL:           tags.first("citation_public_url", "citation_abstract_html_url", "prism.url"),
+		PDFURL:        tags.first("citation_pdf_url"),
+		Abstract:      tags.first("citation_abstract", "dcterms.abstract", "dc.description.abstract"),
+		Language:      tags.first("citation_language", "dc.language", "dcterms.language"),
+		ArXivID:       normalizeArXivID(tags.first("citation_arxiv_id")),
+		ISSN:          tags.all("citation_issn", "prism.issn", "prism.eissn"),
+	}
+
+	// DOI, also looked in the generic identifiers
+	record.DOI = normalizeDOI(tags.first("citation_doi", "prism.doi"))
+	if record.DOI == "" {
+		for _, identifier := range tags.all("dc.identifier", "dcterms.identifier") {
+			if record.DOI = normalizeDOI(identifier); record.DOI != "" {
+				break
+			}
+		}
+	}
+
+	// Date
+	for _, name := range []string{"citation_publication_date", "citation_date", "citation_online_date",
+		"prism.publicationdate", "prism.coverdate", "dcterms.issued", "dc.date", "dcterms.date"} {
+		if value := tags.first(name); value != "" {
+			if record.Date, record.DateParts = parseScholarlyDate(value); record.DateParts > 0 {
+				break
+			}
+		}
+	}
+
+	// Keywords might be listed in a single tag
+	for _, keywords := range tags.all("citation_keywords", "prism.keyword", "dc.subject") {
+		for _, keyword := range rxCommaSeparator.Split(keywords, -1) {
+			if keyword = trim(keyword); keyword != "" {
+				record.Keywords = append(record.Keywords, keyword)
+			}
+		}
+	}
+
+	// Affiliation, ORCID and email of Highwire author belong to the author
+	// right before them.
+	for i, name := range tags.names {
+		value := tags.values[i]
+		switch {
+		case name == "citation_author":
+			record.Authors = append(record.Authors, newScholarlyAuthor(value))
+		case name == "citation_authors":
+			for _, author := range strings.Split(value, ";") {
+				if author = trim(author); author != "" {
+					record.Authors = append(record.Authors, newScholarlyAuthor(author))
+				}
+			}
+		case len(record.Authors) == 0:
+			continue
+		case strIn(name, "citation_author_institution", "citation_author_affiliation"):
+			last := &record.Authors[len(record.Authors)-1]
+			last.Affiliations = append(last.Affiliations, value)
+		case name == "citation_author_orcid":
+			record.Authors[len(record.Authors)-1].ORCID = normalizeORCID(value)
+		}
+	}
+
+	// Dublin Core creators are only used when Highwire authors don't exist
+	if len(record.Authors) == 0 {
+		for _, creator := range tags.all("dc.creator", "dcterms.creator") {
+			record.Authors = append(record.Authors, newScholarlyAuthor(creator))
+		}
+	}
+
+	// Find arXiv id from the DOI that registered by arXiv
+	if record.ArXivID == "" && strings.HasPrefix(strings.ToLower(record.DOI), "10.48550/arxiv.") {
+		record.ArXivID = normalizeArXivID(record.DOI)
+	}
+
+	return record
+}
+
+// findScholarlySchema returns the first JSON+LD schema of scholarly work, along
+// with its CSL type.
+func findScholarlySchema(doc *html.Node, opts Options) (map[string]any, string) {
+	articles := jsonLdSchemas(doc, opts).articles
+	for _, article := range articles {
+		for _, schemaType := range getSchemaTypes(article.Data, true) {
+			if cslType, isScholarly := scholarlySchemaTypes[schemaType]; isScholarly {
+				return article.Data, cslType
+			}
+		}
+	}
+	return nil, ""
+}
+
+func fillScholarlyFromJsonLd(record *ScholarlyRecord, schema map[string]any, cslType string) {
+	record.Type = strOr(record.Type, cslType)
+	record.Title = strOr(record.Title, getSingleStringValue(schema, "headline"), getSingleStringValue(schema, "name"))
+	record.Abstract = strOr(record.Abstract, getSingleStringValue(schema, "abstract"), getSingleStringValue(schema, "description"))
+	record.Language = strOr(record.Language, getSingleStringValue(schema, "inLanguage"))
+	record.URL = strOr(record.URL, getSingleStringValue(schema, "url"))
+	record.FirstPage = strOr(record.FirstPage, getSingleStringValue(schema, "pageStart"))
+	record.LastPage = strOr(record.LastPage, getSingleStringValue(schema, "pageEnd"))
+
+	if record.FirstPage == "" {
+		if parts := rxPageRange.FindStringSubmatch(getSingleStringValue(schema, "pagination")); parts != nil {
+			record.FirstPage, record.LastPage = parts[1], parts[2]
+		}
+	}
+
+	if record.Publisher == "" {
+		record.Publisher = strings.Join(getSchemaNames(schema["publisher"]), "; ")
+	}
+
+	if record.DateParts == 0 {
+		for _, key := range []string{"datePublished", "dateCreated"} {
+			if record.Date, record.DateParts = parseScholarlyDate(getSingleStringValue(schema, key)); record.DateParts > 0 {
+				break
+			}
+		}
+	}
+
+	// Identifiers, either as plain string or as PropertyValue
+	if record.DOI == "" {
+		for _, identifier := range schemaIdentifiers(schema) {
+			if record.DOI = normalizeDOI(identifier); record.DOI != "" {
+				break
+			}
+		}
+	}
+
+	// Keywords, either as list or as comma separated string
+	if len(record.Keywords) == 0 {
+		for _, keywords := range getStringValues(schema, "keywords") {
+			for _, keyword := range rxCommaSeparator.Split(keywords, -1) {
+				if keyword = trim(keyword); keyword != "" {
+					record.Keywords = append(record.Keywords, keyword)
+				}
+			}
+		}
+	}
+
+	// PDF in the encoding of the work
+	if record.PDFURL == "" {
+		for _, key := range []string{"encoding", "associatedMedia"} {
+			for _, media := range schemaObjects(schema[key]) {
+				format := strings.ToLower(getSingleStringValue(media, "encodingFormat"))
+				if strings.Contains(format, "pdf") {
+					record.PDFURL = getSingleStringValue(media, "contentUrl")
+					break
+				}
+			}
+		}
+	}
+
+	// Journal, volume and issue are nested in `isPartOf`, from the issue to the
+	// volume and then the periodical.
+	for parent := schemaObjects(schema["isPartOf"]); len(parent) > 0; parent = schemaObjects(parent[0]["isPartOf"]) {
+		part := parent[0]
+		types := getSchemaTypes(part, true)
+		switch {
+		case strIn("publicationissue", types...):
+			record.Issue = strOr(record.Issue, getSingleStringValue(part, "issueNumber"))
+		case strIn("publicationvolume", types...):
+			record.Volume = strOr(record.Volume, getSingleStringValue(part, "volumeNumber"))
+		default:
+			record.Journal = strOr(record.Journal, getSingleStringValue(part, "name"))
+			if len(record.ISSN) == 0 {
+				record.ISSN = getStringValues(part, "issn")
+			}
+		}
+	}
+
+	if len(record.Authors) == 0 {
+		for _, author := range schemaObjects(schema["author"]) {
+			names := getSchemaNames(author, "person")
+			if len(names) == 0 {
+				continue
+			}
+
+			scholarlyAuthor := newScholarlyAuthor(names[0])
+			if given, family := getSingleStringValue(author, "givenName"), getSingleStringValue(author, "familyName"); given != "" && family != "" {
+				scholarlyAuthor.Given, scholarlyAuthor.Family = given, family
+			}
+
+			scholarlyAuthor.Affiliations = getSchemaNames(author["affiliation"])
+			for _, identifier := range append(schemaIdentifiers(author), getStringValues(author, "sameAs")...) {
+				if orcid := normalizeORCID(identifier); orcid != "" && strings.Contains(strings.ToLower(identifier), "orcid") {
+					scholarlyAuthor.ORCID = orcid
+					break
+				}
+			}
+
+			record.Authors = append(record.Authors, scholarlyAuthor)
+		}
+	}
+}
+
+// schemaObjects returns the JSON+LD value as list of objects.
+func schemaObjects(v any) []map[string]any {
+	switch value := v.(type) {
+	case map[string]any:
+		return []map[string]any{value}
+	case []any:
+		var objects []map[string]any
+		for _, item := range value {
+			if obj, isObject := item.(map[string]any); isObject {
+				objects = append(objects, obj)
+			}
+		}
+		return objects
+	}
+	return nil
+}
+
+// schemaIdentifiers returns the identifiers of JSON+LD schema, which might be a
+// string, URL or PropertyValue.
+func schemaIdentifiers(schema map[string]any) []string {
+	identifiers := getStringValues(schema, "identifier")
+	identifiers = append(identifiers, getStringValues(schema, "sameAs")...)
+	identifiers = append(identifiers, getStringValues(schema, "@id")...)
+	for _, obj := range schemaObjects(schema["identifier"]) {
+		propertyID := strings.ToLower(getSingleStringValue(obj, "propertyID"))
+		if value := getSingleStringValue(obj, "value"); value != "" {
+			identifiers = append(identifiers, propertyID+":"+value)
+		}
+	}
+	return identifiers
+}
+
+// newScholarlyAuthor creates author from name that written either as "Family, Given"
+// like in Highwire tags or as "Given Family".
+func newScholarlyAuthor(name string) ScholarlyAuthor {
+	name = trim(name)
+	if family, given, found := strings.Cut(name, ","); found {
+		family, given = trim(family), trim(given)
+		if family != "" && given != "" {
+			return ScholarlyAuthor{Name: given + " " + family, Given: given, Family: family}
+		}
+	}
+
+	author := ScholarlyAuthor{Name: name}
+	if parts := strings.Fields(name); len(parts) > 1 {
+		author.Given = strings.Join(parts[:len(parts)-1], " ")
+		author.Family = parts[len(parts)-1]
+	}
+	return author
+}
+
+// guessScholarlyType guesses the CSL type from the fields that exist in the record.
+func guessScholarlyType(record ScholarlyRecord) string {
+	switch {
+	case record.Conference != "":
+		return "paper-conference"
+	case record.Journal != "":
+		return "article-journal"
+	case record.ArXivID != "":
+		return "preprint"
+	case record.Institution != "":
+		return "report"
+	default:
+		return "article"
+	}
+}
+
+func parseScholarlyDate(value string) (time.Time, int) {
+	if value = trim(value); value == "" {
+		return time.Time{}, 0
+	}
+
+	for _, format := range scholarlyDateFormats {
+		if date, err := time.Parse(format.layout, value); err == nil {
+			return date, format.parts
+		}
+	}
+	return time.Time{}, 0
+}
+
+func normalizeDOI(value string) string {
+	if match := rxDOI.FindStringSubmatch(value); match != nil {
+		return strings.TrimRight(match[1], ".,;")
+	}
+	return ""
+}
+
+func normalizeArXivID(value string) string {
+	if match := rxArXivID.FindStringSubmatch(value); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+func normalizeORCID(value string) string {
+	if match := rxORCID.FindStringSubmatch(strings.ToUpper(value)); match != nil {
+		return match[1]
+	}
+	return ""
+}
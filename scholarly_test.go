@@ -0,0 +1,126 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ScholarlyMetadata_Highwire(t *testing.T) {
+	rawHTML := `<html><head>
+		<meta name="citation_title" content="Deep Learning for Coral Reefs"/>
+		<meta name="citation_author" content="Doe, Jane"/>
+		<meta name="citation_author_institution" content="University of Somewhere"/>
+		<meta name="citation_author_orcid" content="https://orcid.org/0000-0002-1825-0097"/>
+		<meta name="citation_author" content="John Smith"/>
+		<meta name="citation_publication_date" content="2021/03/15"/>
+		<meta name="citation_journal_title" content="Journal of Marine Science"/>
+		<meta name="citation_journal_abbrev" content="J Mar Sci"/>
+		<meta name="citation_volume" content="12"/>
+		<meta name="citation_issue" content="3"/>
+		<meta name="citation_firstpage" content="101"/>
+		<meta name="citation_lastpage" content="115"/>
+		<meta name="citation_issn" content="1234-5678"/>
+		<meta name="prism.eIssn" content="8765-4321"/>
+		<meta name="citation_doi" content="doi:10.1234/jms.2021.42"/>
+		<meta name="citation_pdf_url" content="/articles/42.pdf"/>
+		<meta name="citation_keywords" content="coral; deep learning"/>
+		<meta name="dc.description.abstract" content="We classify coral reefs."/>
+		</head><body><p>Abstract</p></body></html>`
+
+	metadata := extractMetadata(docFromStr(rawHTML), zeroOpts)
+	record := metadata.Scholarly
+	assert.NotNil(t, record)
+	assert.Equal(t, "article-journal", record.Type)
+	assert.Equal(t, "Deep Learning for Coral Reefs", record.Title)
+	assert.Equal(t, time.Date(2021, 3, 15, 0, 0, 0, 0, time.UTC), record.Date)
+	assert.Equal(t, 3, record.DateParts)
+	assert.Equal(t, "Journal of Marine Science", record.Journal)
+	assert.Equal(t, "J Mar Sci", record.JournalAbbrev)
+	assert.Equal(t, "12", record.Volume)
+	assert.Equal(t, "3", record.Issue)
+	assert.Equal(t, "101", record.FirstPage)
+	assert.Equal(t, "115", record.LastPage)
+	assert.Equal(t, []string{"1234-5678", "8765-4321"}, record.ISSN)
+	assert.Equal(t, "10.1234/jms.2021.42", record.DOI)
+	assert.Equal(t, "https://example.org/articles/42.pdf", record.PDFURL)
+	assert.Equal(t, []string{"coral", "deep learning"}, record.Keywords)
+	assert.Equal(t, "We classify coral reefs.", record.Abstract)
+
+	assert.Len(t, record.Authors, 2)
+	assert.Equal(t, ScholarlyAuthor{
+		Name:         "Jane Doe",
+		Given:        "Jane",
+		Family:       "Doe",
+		ORCID:        "0000-0002-1825-0097",
+		Affiliations: []string{"University of Somewhere"},
+	}, record.Authors[0])
+	assert.Equal(t, "Smith", record.Authors[1].Family)
+	assert.Empty(t, record.Authors[1].Affiliations)
+
+	// Regular page doesn't have bibliographic record
+	metadata = extractMetadata(docFromStr(`<html><head><meta name="dc.title" content="Hello"/>
+		<meta name="dc.creator" content="Jane Doe"/></head><body></body></html>`), zeroOpts)
+	assert.Nil(t, metadata.Scholarly)
+}
+
+func Test_ScholarlyMetadata_JsonLd(t *testing.T) {
+	rawHTML := `<html><head><script type="application/ld+json">{
+		"@context": "https://schema.org",
+		"@type": "ScholarlyArticle",
+		"headline": "Bees and Flowers",
+		"datePublished": "2020-07",
+		"pagination": "5-9",
+		"keywords": ["bees", "pollination"],
+		"identifier": {"@type": "PropertyValue", "propertyID": "DOI", "value": "10.5555/bees.1"},
+		"author": [{"@type": "Person", "givenName": "Ada", "familyName": "Lovelace", "name": "Ada Lovelace",
+			"affiliation": {"@type": "Organization", "name": "Analytical Society"},
+			"sameAs": "https://orcid.org/0000-0001-2345-6789"}],
+		"encoding": {"@type": "MediaObject", "encodingFormat": "application/pdf", "contentUrl": "https://example.org/bees.pdf"},
+		"isPartOf": {"@type": "PublicationIssue", "issueNumber": "2",
+			"isPartOf": {"@type": "PublicationVolume", "volumeNumber": "7",
+				"isPartOf": {"@type": "Periodical", "name": "Insect Letters", "issn": "1111-2222"}}}
+	}</script></head><body><p>Text</p></body></html>`
+
+	record := extractMetadata(docFromStr(rawHTML), zeroOpts).Scholarly
+	assert.NotNil(t, record)
+	assert.Equal(t, "article-journal", record.Type)
+	assert.Equal(t, "Bees and Flowers", record.Title)
+	assert.Equal(t, 2, record.DateParts)
+	assert.Equal(t, time.July, record.Date.Month())
+	assert.Equal(t, "5", record.FirstPage)
+	assert.Equal(t, "9", record.LastPage)
+	assert.Equal(t, "10.5555/bees.1", record.DOI)
+	assert.Equal(t, "Insect Letters", record.Journal)
+	assert.Equal(t, "7", record.Volume)
+	assert.Equal(t, "2", record.Issue)
+	assert.Equal(t, []string{"1111-2222"}, record.ISSN)
+	assert.Equal(t, []string{"bees", "pollination"}, record.Keywords)
+	assert.Equal(t, "https://example.org/bees.pdf", record.PDFURL)
+	assert.Equal(t, []ScholarlyAuthor{{
+		Name:         "Ada Lovelace",
+		Given:        "Ada",
+		Family:       "Lovelace",
+		ORCID:        "0000-0001-2345-6789",
+		Affiliations: []string{"Analytical Society"},
+	}}, record.Authors)
+}
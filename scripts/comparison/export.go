@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	fp "path/filepath"
+	"slices"
+	"strings"
+)
+
+// exportGoldSet saves the comparison data as JSONL gold set, which can be used by
+// `go-trafilatura eval` command. The file paths are relative to the output file.
+func exportGoldSet(outputPath string) {
+	type goldEntry struct {
+		URL string `json:"url"`
+		ComparisonEntry
+	}
+
+	// Sort the URLs so the output is stable
+	urls := make([]string, 0, len(comparisonData))
+	for url := range comparisonData {
+		urls = append(urls, url)
+	}
+	slices.Sort(urls)
+
+	// Prepare output file
+	absOutputDir, err := fp.Abs(fp.Dir(outputPath))
+	if err != nil {
+		log.Fatal().Msgf("failed to resolve output dir: %v", err)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		log.Fatal().Msgf("failed to create output: %v", err)
+	}
+	defer f.Close()
+
+	// Write each entry as a line
+	var nEntry int
+	enc := json.NewEncoder(f)
+	enc.SetEscapeHTML(false)
+
+	for _, url := range urls {
+		entry := comparisonData[url]
+		if entry.File == "" || (len(entry.With) == 0 && len(entry.Without) == 0) {
+			continue
+		}
+
+		dataFile, err := openDataFile(entry.File)
+		if err != nil {
+			log.Error().Msgf("%v", err)
+			continue
+		}
+		dataFile.Close()
+
+		absPath, _ := fp.Abs(dataFile.Name())
+		relPath, err := fp.Rel(absOutputDir, absPath)
+		if err != nil {
+			relPath = absPath
+		}
+
+		entry.File = strings.ReplaceAll(relPath, string(fp.Separator), "/")
+		if err := enc.Encode(goldEntry{URL: url, ComparisonEntry: entry}); err != nil {
+			log.Fatal().Msgf("failed to write entry: %v", err)
+		}
+		nEntry++
+	}
+
+	log.Info().Msgf("exported %d entries into %s", nEntry, outputPath)
+}
@@ -26,7 +26,7 @@ func main() {
 	}
 
 	// Add sub command
-	rootCmd.AddCommand(cmdContent(), cmdAuthor())
+	rootCmd.AddCommand(cmdContent(), cmdAuthor(), cmdExport())
 
 	// Execute
 	err := rootCmd.Execute()
@@ -67,3 +67,18 @@ func cmdAuthor() *cobra.Command {
 		},
 	}
 }
+
+func cmdExport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "export comparison data as gold set for eval command",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			output, _ := cmd.Flags().GetString("output")
+			exportGoldSet(output)
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "gold.jsonl", "path of the output file")
+	return cmd
+}
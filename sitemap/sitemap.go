@@ -0,0 +1,314 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sitemap parses sitemap files as described in <https://www.sitemaps.org>,
+// including the gzipped and plain text variants, and the Google News and image
+// sitemap extensions.
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	betree "github.com/beevik/etree"
+	"golang.org/x/net/html/charset"
+)
+
+// Sitemap is the parsed content of a sitemap file.
+type Sitemap struct {
+	// Entries is the list of pages that listed in `<urlset>` or plain text sitemap.
+	Entries []Entry
+
+	// Sitemaps is the list of child sitemaps that listed in `<sitemapindex>`.
+	// Only `URL` and `LastMod` are filled for these entries.
+	Sitemaps []Entry
+}
+
+// Entry is a single URL that listed in sitemap.
+type Entry struct {
+	URL        string
+	LastMod    time.Time
+	ChangeFreq string
+
+	// Priority is the priority of this URL relative to other URLs in the site,
+	// between 0.0 and 1.0. It will be zero if not specified.
+	Priority float64
+
+	// News is the metadata from Google News sitemap extension.
+	// Will be nil if the entry doesn't have it.
+	News *News
+
+	// Images is the list of images from Google image sitemap extension.
+	Images []Image
+}
+
+// News is metadata from Google News sitemap extension.
+type News struct {
+	PublicationName string
+	Language        string
+	PublicationDate time.Time
+	Title           string
+	Keywords        []string
+}
+
+// Image is metadata from Google image sitemap extension.
+type Image struct {
+	URL     string
+	Title   string
+	Caption string
+}
+
+// Date returns the most relevant date of the entry, i.e. the news publication
+// date if it exists, or the last modification date otherwise.
+func (e Entry) Date() time.Time {
+	if e.News != nil && !e.News.PublicationDate.IsZero() {
+		return e.News.PublicationDate
+	}
+	return e.LastMod
+}
+
+// W3C datetime formats that used in sitemap, from the most to the least precise.
+var dateFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// Max size of uncompressed sitemap as specified in the protocol. It's also used to
+// prevent gzip bomb from exhausting the memory.
+const maxSitemapSize = 50 * 1024 * 1024
+
+// Parse parses sitemap from the reader. The sitemap can be either XML or plain text
+// which contains one URL per line. If the content is gzipped, it will be decompressed
+// automatically. Sitemap that larger than 50 MB after decompressed is rejected.
+func Parse(r io.Reader) (*Sitemap, error) {
+	br := bufio.NewReader(r)
+
+	// Decompress if necessary
+	magic, _ := br.Peek(2)
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress sitemap: %w", err)
+		}
+		defer gz.Close()
+		br = bufio.NewReader(gz)
+	}
+
+	// Read one more byte than the limit, to find out if it's exceeded
+	content, err := io.ReadAll(io.LimitReader(br, maxSitemapSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) > maxSitemapSize {
+		return nil, fmt.Errorf("sitemap is larger than %d bytes", maxSitemapSize)
+	}
+
+	// Check if it's XML by looking at the first non space character
+
+	trimmed := bytes.TrimLeft(content, " \t\r\n\ufeff")
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		return ParseXML(bytes.NewReader(content))
+	}
+
+	return ParseText(bytes.NewReader(content))
+}
+
+// ParseXML parses XML sitemap, either `<urlset>` or `<sitemapindex>`.
+func ParseXML(r io.Reader) (*Sitemap, error) {
+	doc := betree.NewDocument()
+	doc.ReadSettings.CharsetReader = charset.NewReaderLabel
+	if _, err := doc.ReadFrom(r); err != nil {
+		return nil, err
+	}
+
+	root := doc.Root()
+	if root == nil {
+		return nil, fmt.Errorf("sitemap is empty")
+	}
+
+	sitemap := &Sitemap{}
+	for _, elem := range root.ChildElements() {
+		switch elem.Tag {
+		case "url":
+			if entry, ok := parseEntry(elem); ok {
+				sitemap.Entries = append(sitemap.Entries, entry)
+			}
+		case "sitemap":
+			if entry, ok := parseEntry(elem); ok {
+				sitemap.Sitemaps = append(sitemap.Sitemaps, entry)
+			}
+		}
+	}
+
+	return sitemap, nil
+}
+
+// ParseText parses plain text sitemap which contains one URL per line.
+func ParseText(r io.Reader) (*Sitemap, error) {
+	sitemap := &Sitemap{}
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
+			sitemap.Entries = append(sitemap.Entries, Entry{URL: line})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return sitemap, nil
+}
+
+// Filter returns entries whose date is within the specified range. Zero time means
+// the range is unbounded on that side. If any bound is specified, entries without
+// date will be excluded.
+func Filter(entries []Entry, since, until time.Time) []Entry {
+	if since.IsZero() && until.IsZero() {
+		return entries
+	}
+
+	var filtered []Entry
+	for _, entry := range entries {
+		date := entry.Date()
+		switch {
+		case date.IsZero(),
+			!since.IsZero() && date.Before(since),
+			!until.IsZero() && date.After(until):
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+
+	return filtered
+}
+
+// SortByRecency sorts the entries from the newest to the oldest. Entries without
+// date are put in the end, keeping their original order.
+func SortByRecency(entries []Entry) {
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		dateA, dateB := a.Date(), b.Date()
+		switch {
+		case dateA.IsZero() && dateB.IsZero():
+			return 0
+		case dateA.IsZero():
+			return 1
+		case dateB.IsZero():
+			return -1
+		default:
+			return dateB.Compare(dateA)
+		}
+	})
+}
+
+// ParseDate parses date in W3C datetime format that used in sitemap.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, format := range dateFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+func parseEntry(elem *betree.Element) (Entry, bool) {
+	var entry Entry
+	for _, child := range elem.ChildElements() {
+		text := strings.TrimSpace(child.Text())
+		switch child.Tag {
+		case "loc":
+			entry.URL = text
+		case "lastmod":
+			entry.LastMod, _ = ParseDate(text)
+		case "changefreq":
+			entry.ChangeFreq = strings.ToLower(text)
+		case "priority":
+			entry.Priority, _ = strconv.ParseFloat(text, 64)
+		case "news":
+			entry.News = parseNews(child)
+		case "image":
+			if image := parseImage(child); image.URL != "" {
+				entry.Images = append(entry.Images, image)
+			}
+		}
+	}
+
+	return entry, entry.URL != ""
+}
+
+func parseNews(elem *betree.Element) *News {
+	news := &News{}
+	for _, child := range elem.ChildElements() {
+		text := strings.TrimSpace(child.Text())
+		switch child.Tag {
+		case "publication":
+			for _, pub := range child.ChildElements() {
+				switch pub.Tag {
+				case "name":
+					news.PublicationName = strings.TrimSpace(pub.Text())
+				case "language":
+					news.Language = strings.TrimSpace(pub.Text())
+				}
+			}
+		case "publication_date":
+			news.PublicationDate, _ = ParseDate(text)
+		case "title":
+			news.Title = text
+		case "keywords":
+			for keyword := range strings.SplitSeq(text, ",") {
+				if keyword = strings.TrimSpace(keyword); keyword != "" {
+					news.Keywords = append(news.Keywords, keyword)
+				}
+			}
+		}
+	}
+	return news
+}
+
+func parseImage(elem *betree.Element) Image {
+	var image Image
+	for _, child := range elem.ChildElements() {
+		text := strings.TrimSpace(child.Text())
+		switch child.Tag {
+		case "loc":
+			image.URL = text
+		case "title":
+			image.Title = text
+		case "caption":
+			image.Caption = text
+		}
+	}
+	return image
+}
@@ -0,0 +1,151 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const newsSitemap = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+	xmlns:news="http://www.google.com/schemas/sitemap-news/0.9"
+	xmlns:image="http://www.google.com/schemas/sitemap-image/1.1">
+	<url>
+		<loc>https://example.org/old</loc>
+		<lastmod>2021-03-01</lastmod>
+		<changefreq>Monthly</changefreq>
+		<priority>0.3</priority>
+	</url>
+	<url>
+		<loc>https://example.org/no-date</loc>
+	</url>
+	<url>
+		<loc> https://example.org/news </loc>
+		<lastmod>2021-01-01T10:00:00+00:00</lastmod>
+		<news:news>
+			<news:publication>
+				<news:name>Example Times</news:name>
+				<news:language>en</news:language>
+			</news:publication>
+			<news:publication_date>2021-05-10T08:30:00Z</news:publication_date>
+			<news:title><![CDATA[Breaking & News]]></news:title>
+			<news:keywords>politics, economy</news:keywords>
+		</news:news>
+		<image:image>
+			<image:loc>https://example.org/image.jpg</image:loc>
+			<image:caption>A caption</image:caption>
+		</image:image>
+	</url>
+</urlset>`
+
+func Test_ParseXML(t *testing.T) {
+	sitemap, err := Parse(strings.NewReader(newsSitemap))
+	assert.NoError(t, err)
+	assert.Len(t, sitemap.Entries, 3)
+	assert.Empty(t, sitemap.Sitemaps)
+
+	old := sitemap.Entries[0]
+	assert.Equal(t, "https://example.org/old", old.URL)
+	assert.Equal(t, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), old.LastMod)
+	assert.Equal(t, "monthly", old.ChangeFreq)
+	assert.Equal(t, 0.3, old.Priority)
+	assert.Nil(t, old.News)
+
+	news := sitemap.Entries[2]
+	assert.Equal(t, "https://example.org/news", news.URL)
+	assert.NotNil(t, news.News)
+	assert.Equal(t, "Example Times", news.News.PublicationName)
+	assert.Equal(t, "en", news.News.Language)
+	assert.Equal(t, "Breaking & News", news.News.Title)
+	assert.Equal(t, []string{"politics", "economy"}, news.News.Keywords)
+	assert.Equal(t, time.Date(2021, 5, 10, 8, 30, 0, 0, time.UTC), news.Date())
+	assert.Equal(t, []Image{{URL: "https://example.org/image.jpg", Caption: "A caption"}}, news.Images)
+
+	// Sitemap index
+	sitemap, err = Parse(strings.NewReader(`<sitemapindex>
+		<sitemap><loc>https://example.org/sitemap-1.xml.gz</loc><lastmod>2021-05</lastmod></sitemap>
+		<sitemap><loc></loc></sitemap>
+	</sitemapindex>`))
+	assert.NoError(t, err)
+	assert.Empty(t, sitemap.Entries)
+	assert.Len(t, sitemap.Sitemaps, 1)
+	assert.Equal(t, "https://example.org/sitemap-1.xml.gz", sitemap.Sitemaps[0].URL)
+	assert.Equal(t, time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), sitemap.Sitemaps[0].LastMod)
+}
+
+func Test_ParseTextAndGzip(t *testing.T) {
+	text := "https://example.org/a\n\n  https://example.org/b  \nnot an url\n"
+	sitemap, err := Parse(strings.NewReader(text))
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{{URL: "https://example.org/a"}, {URL: "https://example.org/b"}}, sitemap.Entries)
+
+	// Gzipped XML
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(newsSitemap))
+	gz.Close()
+
+	sitemap, err = Parse(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, sitemap.Entries, 3)
+}
+
+func Test_ParseGzipBomb(t *testing.T) {
+	// Small gzip that expands beyond the limit is rejected
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("<urlset>"))
+	gz.Write(make([]byte, maxSitemapSize))
+	gz.Close()
+	assert.Less(t, buf.Len(), 1024*1024)
+
+	_, err := Parse(&buf)
+	assert.ErrorContains(t, err, "larger than")
+}
+
+func Test_FilterAndSort(t *testing.T) {
+	sitemap, _ := Parse(strings.NewReader(newsSitemap))
+	entries := sitemap.Entries
+
+	SortByRecency(entries)
+	urls := func(entries []Entry) []string {
+		var result []string
+		for _, e := range entries {
+			result = append(result, e.URL)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{
+		"https://example.org/news",
+		"https://example.org/old",
+		"https://example.org/no-date",
+	}, urls(entries))
+
+	since := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"https://example.org/news"}, urls(Filter(entries, since, time.Time{})))
+	assert.Equal(t, []string{"https://example.org/old"}, urls(Filter(entries, time.Time{}, since)))
+	assert.Len(t, Filter(entries, time.Time{}, time.Time{}), 3)
+}
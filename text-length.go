@@ -0,0 +1,135 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Most of the size thresholds are tuned for alphabetic scripts, where a word is made
+// of several characters separated by spaces. In Chinese and Japanese a single character
+// carries roughly as much information as a short word, while Thai, Lao, Khmer and
+// Burmese don't separate their words with spaces. To keep the thresholds meaningful,
+// the text is measured in "Latin equivalent" characters and words.
+const (
+	// Weight of Han, Hiragana and Katakana character in text length.
+	cjkCharWeight = 3
+
+	// Weight of Hangul syllable in text length. Korean uses spaces between words,
+	// but each syllable block is made of two or three letters.
+	hangulCharWeight = 2
+
+	// Average number of characters in a word for scripts without word separator.
+	cjkCharsPerWord  = 2
+	thaiCharsPerWord = 5
+)
+
+// Code point where the first script that needs special treatment (Thai) begins.
+// Text whose characters are all below it can be measured like usual.
+const minSpecialScriptRune = 0x0E00
+
+type scriptKind uint8
+
+const (
+	alphabeticScript scriptKind = iota
+	cjkScript
+	hangulScript
+	unspacedScript
+)
+
+func runeScript(r rune) scriptKind {
+	switch {
+	case r < minSpecialScriptRune:
+		return alphabeticScript
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+		return cjkScript
+	case unicode.Is(unicode.Hangul, r):
+		return hangulScript
+	case unicode.In(r, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar):
+		return unspacedScript
+	default:
+		return alphabeticScript
+	}
+}
+
+func hasSpecialScript(s string) bool {
+	for _, r := range s {
+		if r >= minSpecialScriptRune {
+			return true
+		}
+	}
+	return false
+}
+
+// strLength returns the length of text in Latin equivalent characters, i.e. the
+// CJK and Hangul characters are weighted more than alphabetic characters.
+func strLength(s string) int {
+	if !hasSpecialScript(s) {
+		return utf8.RuneCountInString(s)
+	}
+
+	var length int
+	for _, r := range s {
+		switch runeScript(r) {
+		case cjkScript:
+			length += cjkCharWeight
+		case hangulScript:
+			length += hangulCharWeight
+		default:
+			length++
+		}
+	}
+	return length
+}
+
+// strWordCount returns the number of words in text. For scripts that don't separate
+// their words with spaces, the number of words is estimated from the characters.
+func strWordCount(s string) int {
+	words := strings.Fields(s)
+	if !hasSpecialScript(s) {
+		return len(words)
+	}
+
+	var count int
+	for _, word := range words {
+		var nCJK, nUnspaced int
+		var hasOther bool
+		for _, r := range word {
+			switch runeScript(r) {
+			case cjkScript:
+				nCJK++
+			case unspacedScript:
+				if !unicode.Is(unicode.Mn, r) {
+					nUnspaced++
+				}
+			default:
+				hasOther = hasOther || !unicode.IsPunct(r)
+			}
+		}
+
+		count += (nCJK + cjkCharsPerWord - 1) / cjkCharsPerWord
+		count += (nUnspaced + thaiCharsPerWord - 1) / thaiCharsPerWord
+		if hasOther || (nCJK == 0 && nUnspaced == 0) {
+			count++
+		}
+	}
+	return count
+}
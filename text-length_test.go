@@ -0,0 +1,75 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StrLength(t *testing.T) {
+	assert.Equal(t, 11, strLength("Hello world"))
+	assert.Equal(t, 7, strLength("Привет!"))
+	assert.Equal(t, 12, strLength("北京天气"))
+	assert.Equal(t, 15, strLength("こんにちは"))
+	assert.Equal(t, 11, strLength("안녕 하세요"))
+	assert.Equal(t, 10, strLength("สวัสดีครับ"))
+	assert.Equal(t, 15, strLength("Go 语言教程"))
+}
+
+func Test_StrWordCount(t *testing.T) {
+	assert.Equal(t, 2, strWordCount("Hello world"))
+	assert.Equal(t, 1, strWordCount("Hello"))
+	assert.Equal(t, 2, strWordCount("북한 핵실험"))
+	assert.Equal(t, 4, strWordCount("中国经济增长放缓"))
+	assert.Equal(t, 3, strWordCount("Go 语言教程"))
+	assert.Equal(t, 3, strWordCount("ข่าวเศรษฐกิจไทย"))
+	assert.Equal(t, 1, strWordCount("「東京」"))
+}
+
+func Test_LanguageConfig(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("今天北京的天气很好。", 5) + "</p>"
+	rawHTML := `<html lang="zh-CN"><body><article>` + strings.Repeat(paragraph, 2) + `</article></body></html>`
+
+	// Chinese characters are weighted when measuring the text
+	opts := Options{Config: DefaultConfig()}
+	result, err := ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 90*3+10+1, strLength(result.ContentText))
+
+	// Language specific setting
+	opts.Config.Languages = map[string]LanguageConfig{"zh": {MinOutputSize: 500}}
+	_, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.Error(t, err)
+
+	// Setting for other language is not used
+	opts.Config.Languages = map[string]LanguageConfig{"ja": {MinOutputSize: 500}}
+	_, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+
+	// Config is adjusted in a copy
+	cfg := &Config{MinExtractedSize: 250, MinOutputSize: 1, Languages: map[string]LanguageConfig{"zh": {MinExtractedSize: 100}}}
+	zhCfg := cfg.forLanguage("zh")
+	assert.Equal(t, 100, zhCfg.MinExtractedSize)
+	assert.Equal(t, 1, zhCfg.MinOutputSize)
+	assert.Equal(t, 250, cfg.MinExtractedSize)
+	assert.Same(t, cfg, cfg.forLanguage("de"))
+}
@@ -71,13 +71,13 @@ func Test_Trim(t *testing.T) {
 
 	elem := etree.Element("body")
 	etree.SetText(elem, "Test Text")
-	assert.False(t, textFilter(elem))
+	assert.False(t, textFilter(elem, zeroOpts))
 
 	etree.SetText(elem, "Instagram")
-	assert.True(t, textFilter(elem))
+	assert.True(t, textFilter(elem, zeroOpts))
 
 	etree.SetText(elem, "\t\t")
-	assert.True(t, textFilter(elem))
+	assert.True(t, textFilter(elem, zeroOpts))
 }
 
 func Test_ExoticTags(t *testing.T) {
@@ -248,17 +248,17 @@ func Test_LanguageClassifier(t *testing.T) {
 	var result *ExtractResult
 
 	// Content text only
-	lang = languageClassifier("Hier ist ein Text auf Deutsch", "")
+	lang = languageClassifier("Hier ist ein Text auf Deutsch", "", Options{}).Language
 	assert.Equal(t, "de", lang)
 
-	lang = languageClassifier("Hier ist ein Text auf Deutsch", "")
+	lang = languageClassifier("Hier ist ein Text auf Deutsch", "", Options{}).Language
 	assert.NotEqual(t, "en", lang)
 
 	// Comments text
-	lang = languageClassifier("Hier ist ein Text auf Deutsch", "Die Kommentare sind aber etwas länger.")
+	lang = languageClassifier("Hier ist ein Text auf Deutsch", "Die Kommentare sind aber etwas länger.", Options{}).Language
 	assert.Equal(t, "de", lang)
 
-	lang = languageClassifier("This is English.", "Die Kommentare sind aber etwas länger.")
+	lang = languageClassifier("This is English.", "Die Kommentare sind aber etwas länger.", Options{}).Language
 	assert.Equal(t, "de", lang)
 
 	// Extraction result
@@ -33,8 +33,17 @@ func trim(s string) string {
 	return strings.TrimSpace(s)
 }
 
-func strWordCount(s string) int {
-	return len(strings.Fields(s))
+// truncateRunes limits the text to the specified number of characters, without
+// splitting any multi-byte character.
+func truncateRunes(s string, maxLength int) string {
+	var nRunes int
+	for i := range s {
+		if nRunes == maxLength {
+			return s[:i]
+		}
+		nRunes++
+	}
+	return s
 }
 
 func strOr(args ...string) string {
@@ -27,7 +27,6 @@ import (
 	"strings"
 	"unicode/utf8"
 
-	"github.com/RadhiFadlillah/whatlanggo"
 	"github.com/go-shiori/dom"
 	"github.com/markusmobius/go-trafilatura/internal/etree"
 	"github.com/markusmobius/go-trafilatura/internal/lru"
@@ -50,10 +49,22 @@ func checkHtmlLanguage(doc *html.Node, opts Options, strict bool) bool {
 		}
 	}
 
+	targets := targetLanguages(opts)
+	isTarget := func(str string) bool {
+		for _, lang := range rxHtmlLang.FindAllString(str, -1) {
+			if slices.Contains(targets, strings.ToLower(lang)) {
+				return true
+			}
+		}
+		return false
+	}
+
 	// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Content-Language
 	selectors := []string{
-		`meta[http-equiv="content-language"][content]`,
+		`meta[http-equiv="content-language" i][content]`,
 		`meta[property="og:locale"][content]`,
+		`meta[name="language" i][content]`,
+		`meta[name="dc.language" i][content]`,
 	}
 
 	for _, selector := range selectors {
@@ -63,11 +74,8 @@ func checkHtmlLanguage(doc *html.Node, opts Options, strict bool) bool {
 		}
 
 		for _, metaNode := range metaNodes {
-			metaContent := dom.GetAttribute(metaNode, "content")
-			for _, lang := range rxHtmlLang.FindAllString(metaContent, -1) {
-				if strings.ToLower(lang) == opts.TargetLanguage {
-					return true
-				}
+			if isTarget(dom.GetAttribute(metaNode, "content")) {
+				return true
 			}
 		}
 
@@ -77,11 +85,8 @@ func checkHtmlLanguage(doc *html.Node, opts Options, strict bool) bool {
 
 	// HTML lang attribute: sometimes a wrong indication
 	if strict && htmlNode != nil && dom.HasAttribute(htmlNode, "lang") {
-		langAttr := dom.GetAttribute(htmlNode, "lang")
-		for _, lang := range rxHtmlLang.FindAllString(langAttr, -1) {
-			if strings.ToLower(lang) == opts.TargetLanguage {
-				return true
-			}
+		if isTarget(dom.GetAttribute(htmlNode, "lang")) {
+			return true
 		}
 
 		logWarn(opts, "html language detection failed")
@@ -93,7 +98,7 @@ func checkHtmlLanguage(doc *html.Node, opts Options, strict bool) bool {
 }
 
 // languageClassifier returns the language of the text.
-func languageClassifier(contentText, commentsText string) string {
+func languageClassifier(contentText, commentsText string, opts Options) LanguageInfo {
 	lenContent := utf8.RuneCountInString(contentText)
 	lenComments := utf8.RuneCountInString(commentsText)
 
@@ -104,12 +109,11 @@ func languageClassifier(contentText, commentsText string) string {
 		langTest = contentText
 	}
 
-	lang := whatlanggo.DetectLang(langTest)
-	return lang.Iso6391()
+	return detectLanguage(langTest, opts)
 }
 
 // textFilter filters out unwanted text
-func textFilter(n *html.Node) bool {
+func textFilter(n *html.Node, opts Options) bool {
 	var testText string
 	text, tail := etree.Text(n), etree.Tail(n)
 	if text == "" {
@@ -123,7 +127,7 @@ func textFilter(n *html.Node) bool {
 	}
 
 	lines := strings.Split(testText, "\n")
-	return slices.ContainsFunc(lines, re2go.IsTextFilter)
+	return slices.ContainsFunc(lines, re2go.IsTextFilter) || opts.langMatcher.isBoilerplate(testText)
 }
 
 // textCharsTest determine if a string is only composed of spaces and/or control characters.
@@ -137,7 +141,7 @@ func duplicateTest(element *html.Node, cache *lru.Cache, opts Options) bool {
 	var isDuplicate bool
 	testString := trim(etree.IterText(element, " "))
 
-	if utf8.RuneCountInString(testString) > opts.Config.MinDuplicateCheckSize {
+	if strLength(testString) > opts.Config.MinDuplicateCheckSize {
 		cacheVal, _ := cache.Get(testString)
 		if cacheVal > opts.Config.MaxDuplicateCount {
 			isDuplicate = true
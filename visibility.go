@@ -0,0 +1,245 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"github.com/markusmobius/go-trafilatura/internal/selector"
+	"golang.org/x/net/html"
+)
+
+// defaultHiddenClasses is the utility classes from popular CSS frameworks and CMS
+// that used to hide element, either completely or only visually. Generic names like
+// "hidden" or "hide" are not included, since many sites use them for the elements
+// that shown later by script, e.g. gallery slides and collapsed sections.
+var defaultHiddenClasses = []string{
+	"sr-only", "sr-only-focusable", "visually-hidden", "visually-hidden-focusable",
+	"visuallyhidden", "screen-reader-text", "screen-reader-only", "screenreader-only",
+	"element-invisible", "offscreen", "a11y-hidden", "u-visually-hidden", "u-hidden",
+	"hidden-visually", "assistive-text", "d-none", "display-none", "is-hidden",
+}
+
+// rxResponsiveDisplay matches the class that shows element on certain screen size,
+// e.g. "md:block" in Tailwind or "d-md-block" in Bootstrap, which means the element
+// is only hidden on small screen.
+var rxResponsiveDisplay = regexp.MustCompile(`^(?:[a-z0-9]+:(?:block|flex|grid|inline|inline-block|inline-flex|table|contents|visible)|d-[a-z]+-(?:block|flex|grid|inline|inline-block|inline-flex|table)|visible-[a-z]+(?:-[a-z]+)?)$`)
+
+// Hidden elements are kept if together they contain more than this fraction of the
+// page text, since they're likely the main content that hidden until the page is
+// loaded, or split into sections that shown one by one.
+const maxHiddenTextRatio = 0.5
+
+// DefaultHiddenClasses returns copy of the built-in utility classes that used to
+// hide element, e.g. "sr-only" and "visually-hidden". It can be extended and then
+// used in `Options.HiddenClasses`.
+func DefaultHiddenClasses() []string {
+	return slices.Clone(defaultHiddenClasses)
+}
+
+// pruneHiddenNodes removes the elements that never shown to readers from a copy of
+// the document, e.g. element with `hidden` attribute, `display:none` style, hidden
+// utility classes, closed dialog and template. If nothing is hidden, the document is
+// returned as it is.
+func pruneHiddenNodes(doc *html.Node, opts Options) *html.Node {
+	classes := opts.HiddenClasses
+	if classes == nil {
+		classes = defaultHiddenClasses
+	}
+
+	hiddenClasses := make(map[string]struct{}, len(classes))
+	for _, class := range classes {
+		hiddenClasses[strings.ToLower(strings.TrimSpace(class))] = struct{}{}
+	}
+
+	hiddenNodes := findPrunableHiddenNodes(doc, hiddenClasses)
+	if len(hiddenNodes) == 0 {
+		return doc
+	}
+
+	// The nodes are removed from the copy, so the original is left untouched
+	logDebug(opts, "pruning hidden content")
+	hidden := make(map[*html.Node]struct{}, len(hiddenNodes))
+	for _, node := range hiddenNodes {
+		hidden[node] = struct{}{}
+	}
+
+	clone := dom.Clone(doc, true)
+	prunable := make(map[*html.Node]struct{}, len(hiddenNodes))
+	mapClonedNodes(doc, clone, func(original, cloned *html.Node) {
+		if _, exist := hidden[original]; exist {
+			prunable[cloned] = struct{}{}
+		}
+	})
+
+	pruneUnwantedNodesInPlace(clone, []selector.Rule{func(node *html.Node) bool {
+		_, exist := prunable[node]
+		return exist
+	}})
+	return clone
+}
+
+// mapClonedNodes walks the original tree and its deep clone together, and calls fn
+// for each pair of matching nodes.
+func mapClonedNodes(original, clone *html.Node, fn func(original, cloned *html.Node)) {
+	fn(original, clone)
+	for o, c := original.FirstChild, clone.FirstChild; o != nil && c != nil; o, c = o.NextSibling, c.NextSibling {
+		mapClonedNodes(o, c, fn)
+	}
+}
+
+// findPrunableHiddenNodes returns the outermost hidden elements that can be removed.
+// The text of hidden elements is summed up first, since the main content might be
+// split into many hidden sections (e.g. gallery or tabs) which by themselves look
+// small. If together they hold most of the page text, only the empty ones are pruned.
+func findPrunableHiddenNodes(doc *html.Node, hiddenClasses map[string]struct{}) []*html.Node {
+	var hiddenNodes []*html.Node
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+
+			if isHiddenNode(child, hiddenClasses) {
+				hiddenNodes = append(hiddenNodes, child)
+			} else {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+
+	if len(hiddenNodes) == 0 {
+		return nil
+	}
+
+	var hiddenLength int
+	nodeLengths := make([]int, len(hiddenNodes))
+	for i, node := range hiddenNodes {
+		nodeLengths[i] = strLength(trim(pageText(node)))
+		hiddenLength += nodeLengths[i]
+	}
+
+	// Don't remove the main content that only hidden temporarily, e.g. by the
+	// anti-flicker snippet of A/B testing tools.
+	pageLength := strLength(trim(pageText(doc)))
+	if float64(hiddenLength) <= float64(pageLength)*maxHiddenTextRatio {
+		return hiddenNodes
+	}
+
+	var emptyNodes []*html.Node
+	for i, node := range hiddenNodes {
+		if nodeLengths[i] == 0 {
+			emptyNodes = append(emptyNodes, node)
+		}
+	}
+	return emptyNodes
+}
+
+// isHiddenNode checks whether the element is hidden using its tag, attributes, inline
+// style and classes.
+func isHiddenNode(node *html.Node, hiddenClasses map[string]struct{}) bool {
+	switch dom.TagName(node) {
+	case "html", "head", "body", "script", "style":
+		return false
+	case "template":
+		return true
+	case "dialog":
+		if !dom.HasAttribute(node, "open") {
+			return true
+		}
+	}
+
+	// Content of hidden="until-found" can be found by searching the page, so keep it
+	if dom.HasAttribute(node, "hidden") && !strings.EqualFold(dom.GetAttribute(node, "hidden"), "until-found") {
+		return true
+	}
+
+	// Element that hidden only from assistive technology is still visible, e.g. drop
+	// cap and decorative text, so only block without any paragraph is removed.
+	if strings.EqualFold(strings.TrimSpace(dom.GetAttribute(node, "aria-hidden")), "true") &&
+		slices.Contains(boilerplateBlockTags, dom.TagName(node)) &&
+		len(dom.GetElementsByTagName(node, "p")) == 0 {
+		return true
+	}
+
+	if style := dom.GetAttribute(node, "style"); style != "" && isHiddenStyle(style) {
+		return true
+	}
+
+	if len(hiddenClasses) > 0 {
+		var hasHiddenClass bool
+		for _, class := range strings.Fields(strings.ToLower(dom.ClassName(node))) {
+			if rxResponsiveDisplay.MatchString(class) {
+				return false
+			}
+
+			if _, exist := hiddenClasses[class]; exist {
+				hasHiddenClass = true
+			}
+		}
+		return hasHiddenClass
+	}
+
+	return false
+}
+
+// isHiddenStyle checks whether the inline style hides the element, either by not
+// rendering it or by moving it off the screen.
+func isHiddenStyle(style string) bool {
+	declarations := make(map[string]string)
+	for _, declaration := range strings.Split(strings.ToLower(style), ";") {
+		property, value, found := strings.Cut(declaration, ":")
+		if found {
+			value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+			declarations[strings.TrimSpace(property)] = value
+		}
+	}
+
+	switch {
+	case declarations["display"] == "none",
+		declarations["visibility"] == "hidden",
+		declarations["visibility"] == "collapse":
+		return true
+	}
+
+	// Off-screen element, e.g. the screen reader only text
+	position := declarations["position"]
+	if position != "absolute" && position != "fixed" {
+		return false
+	}
+
+	for _, property := range []string{"left", "top", "text-indent"} {
+		if value, isPixel := strings.CutSuffix(declarations[property], "px"); isPixel {
+			if px, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && px <= -999 {
+				return true
+			}
+		}
+	}
+
+	clip := strings.ReplaceAll(declarations["clip"], " ", "")
+	clipPath := strings.ReplaceAll(declarations["clip-path"], " ", "")
+	return strings.HasPrefix(clip, "rect(0") || strings.HasPrefix(clip, "rect(1px,1px,1px,1px)") ||
+		clipPath == "inset(50%)" || clipPath == "inset(100%)"
+}
@@ -0,0 +1,109 @@
+// This file is part of go-trafilatura, Go package for extracting readable
+// content, comments and metadata from a web page. Source available in
+// <https://github.com/markusmobius/go-trafilatura>.
+//
+// Copyright (C) 2021 Markus Mobius
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafilatura
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PruneHiddenNodes(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("The museum reopened its doors after two years of renovation. ", 4) + "</p>"
+	rawHTML := `<html><body><article>` + paragraph + `
+		<p hidden>Cheap flights cheap hotels</p>
+		<p style="display: none !important">Best casino bonus</p>
+		<div style="visibility:hidden">Hidden keyword list</div>
+		<div style="position:absolute; left:-10000px">Off screen keywords</div>
+		<span class="sr-only">Screen reader label</span>
+		<div class="d-none d-md-block">Desktop only teaser</div>
+		<div class="u-hidden lg:flex">Wide screen teaser</div>
+		<div class="hidden">Collapsed answer</div>
+		<div aria-hidden="true">Decorative banner</div>
+		<p>Drop <span aria-hidden="true">cap</span> text</p>
+		<div hidden="until-found">Collapsed section</div>
+		<dialog><p>Subscribe to our newsletter</p></dialog>
+		<dialog open><p>Open dialog</p></dialog>
+		<template><p>Template content</p></template>
+		` + paragraph + `</article></body></html>`
+
+	doc := docFromStr(rawHTML)
+	text := dom.TextContent(pruneHiddenNodes(doc, zeroOpts))
+	for _, hidden := range []string{"Cheap flights", "casino", "keyword list", "Off screen",
+		"Screen reader", "Decorative", "newsletter", "Template"} {
+		assert.NotContains(t, text, hidden)
+	}
+
+	for _, visible := range []string{"museum", "Desktop only", "Wide screen", "cap", "Collapsed", "Open dialog"} {
+		assert.Contains(t, text, visible)
+	}
+
+	// The original document is not modified
+	assert.Contains(t, dom.TextContent(doc), "Cheap flights")
+
+	// Custom classes
+	opts := zeroOpts
+	opts.HiddenClasses = []string{"seo-text"}
+	doc = docFromStr(`<html><body>` + paragraph + `<div class="seo-text">Keyword</div><span class="sr-only">Label</span></body></html>`)
+	text = dom.TextContent(pruneHiddenNodes(doc, opts))
+	assert.NotContains(t, text, "Keyword")
+	assert.Contains(t, text, "Label")
+
+	// Generic classes are only used when user adds them
+	doc = docFromStr(`<html><body>` + paragraph + `<div class="hidden">Keyword</div></body></html>`)
+	assert.Contains(t, dom.TextContent(pruneHiddenNodes(doc, zeroOpts)), "Keyword")
+	opts.HiddenClasses = append(DefaultHiddenClasses(), "hidden")
+	assert.NotContains(t, dom.TextContent(pruneHiddenNodes(doc, opts)), "Keyword")
+
+	// Main content that hidden until the page is loaded is kept
+	doc = docFromStr(`<html><body><main style="visibility:hidden">` + paragraph + `</main><p>Footer</p></body></html>`)
+	assert.Same(t, doc, pruneHiddenNodes(doc, zeroOpts))
+
+	// Main content that split into many hidden sections is kept as well, while the
+	// empty hidden elements are still removed
+	var sections string
+	for range 5 {
+		sections += `<div class="gallery--paragraph-section" style="display:none">` + paragraph + `</div>`
+	}
+	doc = docFromStr(`<html><body><article>` + sections + `<div class="d-none"><img src="a.jpg"/></div>` +
+		`</article><p>Footer</p></body></html>`)
+	pruned := pruneHiddenNodes(doc, zeroOpts)
+	assert.Len(t, dom.QuerySelectorAll(pruned, ".gallery--paragraph-section"), 5)
+	assert.Empty(t, dom.QuerySelectorAll(pruned, "img"))
+}
+
+func Test_KeepHidden(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("The museum reopened its doors after two years of renovation. ", 4) + "</p>"
+	rawHTML := `<html><body><article>` + paragraph +
+		`<p class="visually-hidden">Best casino bonus and cheap flights in town.</p>` + paragraph + `</article></body></html>`
+
+	opts := zeroOpts
+	opts.EnableFallback = false
+	result, err := ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Contains(t, result.ContentText, "museum")
+	assert.NotContains(t, result.ContentText, "casino")
+
+	opts.KeepHidden = true
+	result, err = ExtractDocument(docFromStr(rawHTML), opts)
+	assert.NoError(t, err)
+	assert.Contains(t, result.ContentText, "casino")
+}